@@ -36,19 +36,29 @@ func main() {
 	predictionRepo := repository.NewPredictionRepository(pool)
 	partialRepo := repository.NewPartialRepository(pool)
 	bolaoRepo := repository.NewBolaoRepository(pool)
-
-	classificationSvc := service.NewClassificationService(bolaoRepo, matchRepo, predictionRepo, partialRepo)
-	exportSvc := service.NewExportService(bolaoRepo, matchRepo, predictionRepo)
-	bolaoSvc := service.NewBolaoService(bolaoRepo, matchRepo)
+	rulesetRepo := repository.NewScoringRulesetRepository(pool)
+	jokerRepo := repository.NewJokerRepository(pool)
+	outrightRepo := repository.NewOutrightRepository(pool)
+	questionRepo := repository.NewRoundQuestionRepository(pool)
+	shareRepo := repository.NewOutcomeShareRepository(pool)
+	tieRepo := repository.NewTieRepository(pool)
+	roundScoreRepo := repository.NewRoundScoreRepository(pool)
+
+	classificationSvc := service.NewClassificationService(bolaoRepo, matchRepo, predictionRepo, partialRepo, rulesetRepo, jokerRepo, outrightRepo, questionRepo, shareRepo, userRepo, tieRepo, roundScoreRepo)
+	exportSvc := service.NewExportService(bolaoRepo, matchRepo, predictionRepo, rulesetRepo, jokerRepo, questionRepo, shareRepo, userRepo, tieRepo)
+	bolaoSvc := service.NewBolaoService(bolaoRepo, matchRepo, rulesetRepo, outrightRepo)
 
 	authHandler := handler.NewAuthHandler(userRepo, cfg.JWTSecret)
 	userHandler := handler.NewUserHandler(userRepo, bolaoRepo)
-	matchHandler := handler.NewMatchHandler(matchRepo, bolaoRepo)
-	predictionHandler := handler.NewPredictionHandler(predictionRepo, matchRepo, bolaoRepo)
+	matchHandler := handler.NewMatchHandler(matchRepo, bolaoRepo, tieRepo, classificationSvc)
+	predictionHandler := handler.NewPredictionHandler(predictionRepo, matchRepo, bolaoRepo, jokerRepo, tieRepo, classificationSvc)
 	partialHandler := handler.NewPartialHandler(matchRepo, partialRepo, bolaoRepo)
 	classificationHandler := handler.NewClassificationHandler(classificationSvc, bolaoRepo)
 	exportHandler := handler.NewExportHandler(exportSvc, bolaoRepo)
-	bolaoHandler := handler.NewBolaoHandler(bolaoSvc, bolaoRepo)
+	bolaoHandler := handler.NewBolaoHandler(bolaoSvc, bolaoRepo, rulesetRepo, classificationSvc)
+	outrightHandler := handler.NewOutrightHandler(outrightRepo, bolaoRepo)
+	questionHandler := handler.NewRoundQuestionHandler(questionRepo, matchRepo, bolaoRepo, classificationSvc)
+	tieHandler := handler.NewTieHandler(tieRepo, matchRepo, bolaoRepo, classificationSvc)
 
 	r := gin.Default()
 
@@ -62,12 +72,22 @@ func main() {
 	{
 		api.POST("/auth/change-password", authHandler.ChangePassword)
 		api.GET("/classification", classificationHandler.Get)
+		api.GET("/classification/history", classificationHandler.GetHistory)
+		api.GET("/classification/months", classificationHandler.GetMonths)
+		api.GET("/classification/live", classificationHandler.GetLive)
+		api.GET("/classification/odds", classificationHandler.GetOdds)
+		api.GET("/classification/elimination", classificationHandler.GetElimination)
+		api.GET("/compare", classificationHandler.Compare)
+		api.GET("/analytics/teams", classificationHandler.GetTeams)
+		api.POST("/classification/simulate", classificationHandler.Simulate)
 		api.GET("/matches/rounds", matchHandler.ListRounds)
 		api.GET("/matches/rounds/summary", matchHandler.ListRoundsSummary)
 		api.GET("/matches/round/:round", matchHandler.ListByRound)
 		api.GET("/users", userHandler.List)
+		api.GET("/users/:id/stats", classificationHandler.GetPlayerStats)
 		api.GET("/predictions", predictionHandler.GetMyPredictions)
 		api.GET("/predictions/round/:round/user/:user_id", predictionHandler.GetByUserAndRound)
+		api.GET("/predictions/round/:round/user/:user_id/breakdown", predictionHandler.GetBreakdown)
 		api.POST("/predictions", predictionHandler.UpsertPredictions)
 		api.GET("/me", userHandler.GetMe)
 		api.PUT("/me", userHandler.UpdateMe)
@@ -80,6 +100,15 @@ func main() {
 		api.GET("/boloes", bolaoHandler.List)
 		api.GET("/boloes/active", bolaoHandler.GetActive)
 		api.GET("/boloes/:id/participants", bolaoHandler.ListParticipants)
+		api.GET("/boloes/:id/scoring", bolaoHandler.GetScoring)
+		api.GET("/boloes/:id/scoring/versions", bolaoHandler.ListScoringVersions)
+		api.GET("/outrights", outrightHandler.List)
+		api.GET("/outrights/:id/answers", outrightHandler.ListAnswers)
+		api.POST("/outrights/:id/answer", outrightHandler.Answer)
+		api.GET("/questions/round/:round", questionHandler.ListByRound)
+		api.GET("/questions/round/:round/answers", questionHandler.ListAnswers)
+		api.POST("/questions/round/:round/answers", questionHandler.Answer)
+		api.GET("/ties", tieHandler.List)
 
 		admin := api.Group("")
 		admin.Use(handler.AdminMiddleware())
@@ -95,6 +124,16 @@ func main() {
 			admin.POST("/boloes", bolaoHandler.Create)
 			admin.POST("/boloes/active/finish", bolaoHandler.FinishActive)
 			admin.PUT("/boloes/:id/participants/:user_id", bolaoHandler.UpdateParticipantAmountPaid)
+			admin.POST("/boloes/:id/scoring/versions", bolaoHandler.AddScoringVersion)
+			admin.PUT("/boloes/:id/settings", bolaoHandler.UpdateSettings)
+			admin.POST("/outrights", outrightHandler.Create)
+			admin.PUT("/outrights/:id/outcome", outrightHandler.SetOutcome)
+			admin.DELETE("/outrights/:id", outrightHandler.Delete)
+			admin.POST("/questions", questionHandler.Create)
+			admin.PUT("/questions/:id/result", questionHandler.SetResult)
+			admin.DELETE("/questions/:id", questionHandler.Delete)
+			admin.POST("/ties", tieHandler.Create)
+			admin.GET("/classification/consistency", classificationHandler.CheckConsistency)
 		}
 	}
 
@@ -117,7 +156,7 @@ func corsMiddleware() gin.HandlerFunc {
 }
 
 func runMigrations(ctx context.Context, pool *pgxpool.Pool) error {
-	for _, name := range []string{"001_init.sql", "002_timestamptz.sql", "003_match_partials.sql", "004_passwords.sql", "005_partials_nullable.sql", "006_boloes.sql"} {
+	for _, name := range []string{"001_init.sql", "002_timestamptz.sql", "003_match_partials.sql", "004_passwords.sql", "005_partials_nullable.sql", "006_boloes.sql", "007_scoring_rulesets.sql", "008_jokers.sql", "009_outrights.sql", "010_round_questions.sql", "011_scoring_engines.sql", "012_ruleset_versions.sql", "013_bolao_settings.sql", "014_underdog.sql", "015_round_weights.sql", "016_drop_worst_rounds.sql", "017_favorite_team.sql", "018_knockout.sql", "019_ties.sql", "020_round_scores.sql", "021_round_ranges.sql", "022_monthly_prize.sql"} {
 		path := filepath.Join("migrations", name)
 		content, err := os.ReadFile(path)
 		if err != nil {
@@ -3,6 +3,7 @@ package handler
 import (
 	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/bolao-app/api/internal/models"
 	"github.com/bolao-app/api/internal/repository"
@@ -12,16 +13,24 @@ import (
 )
 
 type BolaoHandler struct {
-	bolaoSvc  *service.BolaoService
-	bolaoRepo *repository.BolaoRepository
+	bolaoSvc          *service.BolaoService
+	bolaoRepo         *repository.BolaoRepository
+	rulesetRepo       *repository.ScoringRulesetRepository
+	classificationSvc *service.ClassificationService
 }
 
-func NewBolaoHandler(bolaoSvc *service.BolaoService, bolaoRepo *repository.BolaoRepository) *BolaoHandler {
-	return &BolaoHandler{bolaoSvc: bolaoSvc, bolaoRepo: bolaoRepo}
+func NewBolaoHandler(bolaoSvc *service.BolaoService, bolaoRepo *repository.BolaoRepository, rulesetRepo *repository.ScoringRulesetRepository, classificationSvc *service.ClassificationService) *BolaoHandler {
+	return &BolaoHandler{bolaoSvc: bolaoSvc, bolaoRepo: bolaoRepo, rulesetRepo: rulesetRepo, classificationSvc: classificationSvc}
 }
 
 type CreateBolaoRequest struct {
 	Name string `json:"name" binding:"required"`
+	// Scoring is decoded over the SCORING.md defaults (see Create), so a client only
+	// sends the values the group voted to change.
+	Scoring *models.ScoringRuleset `json:"scoring"`
+	// Tiebreakers and shared_positions at the top level; omitted means the defaults, and
+	// omitted round_ranges the turno and the returno.
+	models.BolaoSettings
 }
 
 type FinishBolaoRequest struct {
@@ -66,15 +75,144 @@ func (h *BolaoHandler) ListParticipants(c *gin.Context) {
 	c.JSON(http.StatusOK, participants)
 }
 
+// GetScoring returns the rules in force at ?round=, or the latest version when no round is
+// given.
+func (h *BolaoHandler) GetScoring(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+	rulesets, err := service.LoadRulesetHistory(c.Request.Context(), h.rulesetRepo, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "regras de pontuação não encontradas"})
+		return
+	}
+	if raw := c.Query("round"); raw != "" {
+		round, err := strconv.Atoi(raw)
+		if err != nil || round < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "rodada inválida"})
+			return
+		}
+		c.JSON(http.StatusOK, rulesets.ForRound(round))
+		return
+	}
+	c.JSON(http.StatusOK, rulesets.Latest())
+}
+
+func (h *BolaoHandler) ListScoringVersions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+	rulesets, err := service.LoadRulesetHistory(c.Request.Context(), h.rulesetRepo, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "regras de pontuação não encontradas"})
+		return
+	}
+	c.JSON(http.StatusOK, rulesets)
+}
+
+// AddScoringVersion changes the rules of the active bolão from effective_from_round on. The
+// body is decoded over the latest version, so it only needs the round and the values that
+// change.
+func (h *BolaoHandler) AddScoringVersion(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+	rulesets, err := service.LoadRulesetHistory(c.Request.Context(), h.rulesetRepo, id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "regras de pontuação não encontradas"})
+		return
+	}
+
+	next := rulesets.Latest()
+	next.EffectiveFromRound = 0
+	if err := c.ShouldBindJSON(&next); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.bolaoSvc.AddRulesetVersion(c.Request.Context(), id, next)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRuleset) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrNoActiveBolao) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "só é possível mudar as regras do bolão ativo"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.classificationSvc.InvalidateFromRound(c.Request.Context(), id, created.EffectiveFromRound); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "regras salvas, mas a classificação não foi atualizada: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// UpdateSettings changes the active bolão's tiebreakers and shared positions. The body is
+// decoded over the current settings, so it only needs what changes.
+func (h *BolaoHandler) UpdateSettings(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+	bolao, err := h.bolaoRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "bolão não encontrado"})
+		return
+	}
+
+	settings := bolao.BolaoSettings
+	if err := c.ShouldBindJSON(&settings); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.bolaoSvc.UpdateSettings(c.Request.Context(), id, settings)
+	if err != nil {
+		if invalidSettings(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrNoActiveBolao) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "só é possível mudar as configurações do bolão ativo"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
 func (h *BolaoHandler) Create(c *gin.Context) {
-	var req CreateBolaoRequest
+	// encoding/json decodes into an already-allocated pointer, leaving the fields the
+	// body omits at their default value.
+	defaults := service.DefaultScoringRuleset()
+	req := CreateBolaoRequest{Scoring: &defaults}
+	req.RoundRanges = service.DefaultRoundRanges()
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	// An explicit "scoring": null also means the defaults.
+	if req.Scoring == nil {
+		req.Scoring = &defaults
+	}
 
-	bolao, err := h.bolaoSvc.CreateNew(c.Request.Context(), req.Name)
+	bolao, err := h.bolaoSvc.CreateNew(c.Request.Context(), req.Name, *req.Scoring, req.BolaoSettings)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidRuleset) || invalidSettings(err) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		if errors.Is(err, service.ErrActiveBolaoExists) {
 			c.JSON(http.StatusConflict, gin.H{"error": "finalize o bolão atual antes de criar um novo"})
 			return
@@ -102,6 +240,15 @@ func (h *BolaoHandler) FinishActive(c *gin.Context) {
 			})
 			return
 		}
+		var unresolvedOutrights *service.ErrUnresolvedOutrights
+		if errors.As(err, &unresolvedOutrights) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":     "existem apostas de temporada sem resultado definido",
+				"questions": unresolvedOutrights.Questions,
+				"count":     unresolvedOutrights.Count,
+			})
+			return
+		}
 		if errors.Is(err, service.ErrNoActiveBolao) {
 			c.JSON(http.StatusNotFound, gin.H{"error": "nenhum bolão ativo encontrado"})
 			return
@@ -140,3 +287,10 @@ func (h *BolaoHandler) UpdateParticipantAmountPaid(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "pagamento atualizado"})
 }
+
+// invalidSettings reports whether err is a validation error of models.BolaoSettings.
+func invalidSettings(err error) bool {
+	return errors.Is(err, service.ErrInvalidTiebreakers) || errors.Is(err, service.ErrInvalidRoundWeights) ||
+		errors.Is(err, service.ErrInvalidDropWorstRounds) || errors.Is(err, service.ErrInvalidRoundRanges) ||
+		errors.Is(err, service.ErrInvalidMonthlyPrize)
+}
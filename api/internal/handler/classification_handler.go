@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/bolao-app/api/internal/repository"
 	"github.com/bolao-app/api/internal/service"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type ClassificationHandler struct {
@@ -32,6 +35,14 @@ func (h *ClassificationHandler) Get(c *gin.Context) {
 	}
 
 	ctx := c.Request.Context()
+	if month := c.Query("month"); month != "" {
+		h.getMonth(c, bolaoID, month)
+		return
+	}
+	if name, from, to := c.Query("range"), c.Query("from_round"), c.Query("to_round"); name != "" || from != "" || to != "" {
+		h.getRange(c, bolaoID, name, from, to)
+		return
+	}
 	// Specific round (1..998): classification for that round only. 0 or 999: cumulative up to last round.
 	if round >= 1 && round <= 998 {
 		classification, err := h.classificationSvc.GetClassificationForRound(ctx, bolaoID, round)
@@ -50,6 +61,197 @@ func (h *ClassificationHandler) Get(c *gin.Context) {
 	c.JSON(http.StatusOK, classification)
 }
 
+// getRange answers Get for the standings over a range of rounds: one of the bolão's presets
+// by name (?range=turno) or any from_round..to_round.
+func (h *ClassificationHandler) getRange(c *gin.Context, bolaoID uuid.UUID, name, fromStr, toStr string) {
+	ctx := c.Request.Context()
+	var classification *service.RangeClassification
+	var err error
+	if name != "" {
+		classification, err = h.classificationSvc.GetClassificationPreset(ctx, bolaoID, name)
+	} else {
+		from, errFrom := strconv.Atoi(fromStr)
+		to, errTo := strconv.Atoi(toStr)
+		if errFrom != nil || errTo != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "informe from_round e to_round"})
+			return
+		}
+		classification, err = h.classificationSvc.GetClassificationRange(ctx, bolaoID, from, to)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRoundRange) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrRoundRangeNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, classification)
+}
+
+// getMonth answers Get for the standings of a calendar month (?month=2026-05).
+func (h *ClassificationHandler) getMonth(c *gin.Context, bolaoID uuid.UUID, month string) {
+	if _, err := time.Parse(service.MonthLayout, month); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mês inválido, use AAAA-MM"})
+		return
+	}
+	classification, err := h.classificationSvc.GetMonthlyClassification(c.Request.Context(), bolaoID, month)
+	if err != nil {
+		if errors.Is(err, service.ErrMonthNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, classification)
+}
+
+// GetMonths lists the months of the bolão with their players of the month and prizes.
+func (h *ClassificationHandler) GetMonths(c *gin.Context) {
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	months, err := h.classificationSvc.GetMonthlyWinners(c.Request.Context(), bolaoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, months)
+}
+
+// GetHistory returns the cumulative standings after every round played, for charting the
+// title race.
+func (h *ClassificationHandler) GetHistory(c *gin.Context) {
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	history, err := h.classificationSvc.GetClassificationHistory(c.Request.Context(), bolaoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// GetLive returns the cumulative standings with the matches in play counted on their
+// parciais, for following the table during a round.
+func (h *ClassificationHandler) GetLive(c *gin.Context) {
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	live, err := h.classificationSvc.GetLiveStandings(c.Request.Context(), bolaoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, live)
+}
+
+// GetOdds plays the rest of the season out many times and returns each player's chances
+// of finishing first, in the top 3 and last.
+func (h *ClassificationHandler) GetOdds(c *gin.Context) {
+	simulations, err := strconv.Atoi(c.DefaultQuery("simulations", strconv.Itoa(service.DefaultOddsSimulations)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "simulations deve ser um número"})
+		return
+	}
+
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	odds, err := h.classificationSvc.Odds(c.Request.Context(), bolaoID, simulations, c.DefaultQuery("model", service.GoalModelPoisson))
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOdds) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, odds)
+}
+
+// GetElimination returns each player's maximum, who is out of the title race and the
+// leader's magic number.
+func (h *ClassificationHandler) GetElimination(c *gin.Context) {
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	report, err := h.classificationSvc.GetElimination(c.Request.Context(), bolaoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// Compare puts two players side by side over the rounds whose market has closed.
+func (h *ClassificationHandler) Compare(c *gin.Context) {
+	userA, errA := uuid.Parse(c.Query("user_a"))
+	userB, errB := uuid.Parse(c.Query("user_b"))
+	if errA != nil || errB != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_a e user_b devem ser ids de usuário"})
+		return
+	}
+	if userA == userB {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "escolha dois jogadores diferentes"})
+		return
+	}
+
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	comparison, err := h.classificationSvc.Compare(c.Request.Context(), bolaoID, userA, userB)
+	if err != nil {
+		if errors.Is(err, service.ErrNotParticipant) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, comparison)
+}
+
+// CheckConsistency compares the bolão's materialized round scores with a full recompute.
+func (h *ClassificationHandler) CheckConsistency(c *gin.Context) {
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	check, err := h.classificationSvc.CheckRoundScores(c.Request.Context(), bolaoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, check)
+}
+
 func (h *ClassificationHandler) GetByPartials(c *gin.Context) {
 	roundStr := c.Param("round")
 	round, err := strconv.Atoi(roundStr)
@@ -71,3 +273,78 @@ func (h *ClassificationHandler) GetByPartials(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, classification)
 }
+
+type SimulateRequest struct {
+	// Round picks the round table to return; 0 means the latest round the results touch.
+	Round   int                          `json:"round"`
+	Results []service.HypotheticalResult `json:"results"`
+}
+
+// Simulate returns the round and cumulative standings as they would be with hypothetical
+// scores for matches still without a result. Nothing is saved.
+func (h *ClassificationHandler) Simulate(c *gin.Context) {
+	var req SimulateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	sim, err := h.classificationSvc.Simulate(c.Request.Context(), bolaoID, req.Round, req.Results)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidSimulation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, sim)
+}
+
+// GetPlayerStats returns a player's analytics over the bolão.
+func (h *ClassificationHandler) GetPlayerStats(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id de usuário inválido"})
+		return
+	}
+
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	stats, err := h.classificationSvc.GetPlayerStats(c.Request.Context(), bolaoID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrNotParticipant) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// GetTeams returns how well the group and each player read every team's games.
+func (h *ClassificationHandler) GetTeams(c *gin.Context) {
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	teams, err := h.classificationSvc.GetTeamAccuracy(c.Request.Context(), bolaoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, teams)
+}
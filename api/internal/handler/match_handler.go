@@ -12,15 +12,18 @@ import (
 	"github.com/bolao-app/api/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type MatchHandler struct {
-	matchRepo *repository.MatchRepository
-	bolaoRepo *repository.BolaoRepository
+	matchRepo         *repository.MatchRepository
+	bolaoRepo         *repository.BolaoRepository
+	tieRepo           *repository.TieRepository
+	classificationSvc *service.ClassificationService
 }
 
-func NewMatchHandler(matchRepo *repository.MatchRepository, bolaoRepo *repository.BolaoRepository) *MatchHandler {
-	return &MatchHandler{matchRepo: matchRepo, bolaoRepo: bolaoRepo}
+func NewMatchHandler(matchRepo *repository.MatchRepository, bolaoRepo *repository.BolaoRepository, tieRepo *repository.TieRepository, classificationSvc *service.ClassificationService) *MatchHandler {
+	return &MatchHandler{matchRepo: matchRepo, bolaoRepo: bolaoRepo, tieRepo: tieRepo, classificationSvc: classificationSvc}
 }
 
 type CreateMatchRequest struct {
@@ -36,12 +39,17 @@ type CreateMatchesRequest struct {
 	Matches        []struct {
 		HomeTeam string `json:"home_team" binding:"required"`
 		AwayTeam string `json:"away_team" binding:"required"`
+		Knockout bool   `json:"knockout"`
 	} `json:"matches" binding:"required"`
 }
 
+// The extra-time score and penalty winner only apply to knockout matches (SCORING.md §2.7).
 type UpdateResultsRequest struct {
-	HomeGoals int `json:"home_goals" binding:"gte=0"`
-	AwayGoals int `json:"away_goals" binding:"gte=0"`
+	HomeGoals          int     `json:"home_goals" binding:"gte=0"`
+	AwayGoals          int     `json:"away_goals" binding:"gte=0"`
+	ExtraTimeHomeGoals *int    `json:"extra_time_home_goals"`
+	ExtraTimeAwayGoals *int    `json:"extra_time_away_goals"`
+	PenaltyWinner      *string `json:"penalty_winner"`
 }
 
 type UpdateRoundClosesRequest struct {
@@ -85,8 +93,13 @@ func (h *MatchHandler) ListRoundsSummary(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	bolao, err := h.bolaoRepo.GetByID(c.Request.Context(), bolaoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
 
-	c.JSON(http.StatusOK, service.SummarizeRounds(matches, time.Now()))
+	c.JSON(http.StatusOK, service.SummarizeRounds(matches, bolao.RoundWeights, time.Now()))
 }
 
 func (h *MatchHandler) ListByRound(c *gin.Context) {
@@ -145,6 +158,7 @@ func (h *MatchHandler) CreateMatches(c *gin.Context) {
 			Round:          req.Round,
 			HomeTeam:       m.HomeTeam,
 			AwayTeam:       m.AwayTeam,
+			Knockout:       m.Knockout,
 			MarketClosesAt: closesAt,
 		}
 		if err := h.matchRepo.Create(c.Request.Context(), match); err != nil {
@@ -153,6 +167,10 @@ func (h *MatchHandler) CreateMatches(c *gin.Context) {
 		}
 		created = append(created, *match)
 	}
+	// A new match can move the round's first market close, when the favorite teams freeze.
+	if err := refreshRounds(c, h.classificationSvc, active.ID, []int{req.Round}); err != nil {
+		return
+	}
 
 	c.JSON(http.StatusCreated, created)
 }
@@ -170,16 +188,34 @@ func (h *MatchHandler) UpdateResults(c *gin.Context) {
 		return
 	}
 
-	if err := h.assertMatchInActiveBolao(c, id); err != nil {
+	match, err := h.activeMatch(c, id)
+	if err != nil {
+		return
+	}
+	result := models.MatchResult{
+		HomeGoals:          req.HomeGoals,
+		AwayGoals:          req.AwayGoals,
+		ExtraTimeHomeGoals: req.ExtraTimeHomeGoals,
+		ExtraTimeAwayGoals: req.ExtraTimeAwayGoals,
+		PenaltyWinner:      req.PenaltyWinner,
+	}
+	if err := h.validateResult(c, *match, result); err != nil {
+		return
+	}
+	rounds, err := affectedRounds(c, h.classificationSvc, match.BolaoID, match.Round)
+	if err != nil {
 		return
 	}
 
-	if err := h.matchRepo.UpdateResults(c.Request.Context(), id, req.HomeGoals, req.AwayGoals); err != nil {
+	if err := h.matchRepo.UpdateResults(c.Request.Context(), id, result); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if err := refreshRounds(c, h.classificationSvc, match.BolaoID, rounds); err != nil {
+		return
+	}
 
-	match, _ := h.matchRepo.GetByID(c.Request.Context(), id)
+	match, _ = h.matchRepo.GetByID(c.Request.Context(), id)
 	c.JSON(http.StatusOK, match)
 }
 
@@ -206,10 +242,17 @@ func (h *MatchHandler) UpdateRoundCloses(c *gin.Context) {
 	if req.MarketClosesAt != nil && req.MarketClosesAt.Time != nil {
 		closesAt = req.MarketClosesAt.Time
 	}
+	rounds, err := affectedRounds(c, h.classificationSvc, active.ID, round)
+	if err != nil {
+		return
+	}
 	if err := h.matchRepo.UpdateMarketClosesAt(c.Request.Context(), active.ID, round, closesAt); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if err := refreshRounds(c, h.classificationSvc, active.ID, rounds); err != nil {
+		return
+	}
 
 	matches, _ := h.matchRepo.ListByRound(c.Request.Context(), active.ID, round)
 	c.JSON(http.StatusOK, matches)
@@ -233,7 +276,12 @@ func (h *MatchHandler) UpdateMatch(c *gin.Context) {
 		return
 	}
 
-	if err := h.assertMatchInActiveBolao(c, id); err != nil {
+	match, err := h.activeMatch(c, id)
+	if err != nil {
+		return
+	}
+	rounds, err := affectedRounds(c, h.classificationSvc, match.BolaoID, match.Round)
+	if err != nil {
 		return
 	}
 
@@ -241,8 +289,12 @@ func (h *MatchHandler) UpdateMatch(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// The teams decide the favorite-team bonus.
+	if err := refreshRounds(c, h.classificationSvc, match.BolaoID, rounds); err != nil {
+		return
+	}
 
-	match, _ := h.matchRepo.GetByID(c.Request.Context(), id)
+	match, _ = h.matchRepo.GetByID(c.Request.Context(), id)
 	c.JSON(http.StatusOK, match)
 }
 
@@ -253,7 +305,12 @@ func (h *MatchHandler) DeleteMatch(c *gin.Context) {
 		return
 	}
 
-	if err := h.assertMatchInActiveBolao(c, id); err != nil {
+	match, err := h.activeMatch(c, id)
+	if err != nil {
+		return
+	}
+	rounds, err := affectedRounds(c, h.classificationSvc, match.BolaoID, match.Round)
+	if err != nil {
 		return
 	}
 
@@ -261,6 +318,9 @@ func (h *MatchHandler) DeleteMatch(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if err := refreshRounds(c, h.classificationSvc, match.BolaoID, rounds); err != nil {
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "jogo excluído"})
 }
@@ -278,33 +338,65 @@ func (h *MatchHandler) DeleteRound(c *gin.Context) {
 		return
 	}
 
+	rounds, err := affectedRounds(c, h.classificationSvc, active.ID, round)
+	if err != nil {
+		return
+	}
 	if err := h.matchRepo.DeleteRound(c.Request.Context(), active.ID, round); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	if err := refreshRounds(c, h.classificationSvc, active.ID, rounds); err != nil {
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "rodada excluída"})
 }
 
-// assertMatchInActiveBolao writes a 403 response and returns a non-nil error if the
-// match doesn't belong to the currently active bolão (defense-in-depth against a
-// stale client trying to edit a finished bolão's match by id).
-func (h *MatchHandler) assertMatchInActiveBolao(c *gin.Context, matchID uuid.UUID) error {
+// activeMatch returns the match, or writes a 403 response and returns a non-nil error if
+// it doesn't belong to the currently active bolão (defense-in-depth against a stale
+// client trying to edit a finished bolão's match by id).
+func (h *MatchHandler) activeMatch(c *gin.Context, matchID uuid.UUID) (*models.Match, error) {
 	match, err := h.matchRepo.GetByID(c.Request.Context(), matchID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "jogo não encontrado"})
-		return err
+		return nil, err
 	}
 	active, err := h.bolaoRepo.GetActive(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "nenhum bolão ativo encontrado"})
-		return err
+		return nil, err
 	}
 	if match.BolaoID != active.ID {
 		c.JSON(http.StatusForbidden, gin.H{"error": "não é possível editar jogos de um bolão encerrado"})
-		return errMatchNotInActiveBolao
+		return nil, errMatchNotInActiveBolao
 	}
-	return nil
+	return match, nil
 }
 
 var errMatchNotInActiveBolao = errors.New("match not in active bolão")
+
+// validateResult writes the error response and returns a non-nil error unless r is a
+// valid result for m: checked against the aggregate when m is the second leg of a tie.
+func (h *MatchHandler) validateResult(c *gin.Context, m models.Match, r models.MatchResult) error {
+	ctx := c.Request.Context()
+	tie, err := h.tieRepo.GetByMatch(ctx, m.ID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return err
+	}
+	if tie != nil && tie.SecondLegID == m.ID {
+		first, err := h.matchRepo.GetByID(ctx, tie.FirstLegID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return err
+		}
+		err = service.ValidateTieResult(*tie, *first, r)
+	} else {
+		err = service.ValidateMatchResult(m, r)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	}
+	return err
+}
@@ -0,0 +1,280 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/bolao-app/api/internal/repository"
+	"github.com/bolao-app/api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type OutrightHandler struct {
+	outrightRepo *repository.OutrightRepository
+	bolaoRepo    *repository.BolaoRepository
+}
+
+func NewOutrightHandler(outrightRepo *repository.OutrightRepository, bolaoRepo *repository.BolaoRepository) *OutrightHandler {
+	return &OutrightHandler{outrightRepo: outrightRepo, bolaoRepo: bolaoRepo}
+}
+
+type CreateOutrightRequest struct {
+	Title        string       `json:"title"`
+	Picks        int          `json:"picks"`
+	PointsPerHit int          `json:"points_per_hit"`
+	ClosesAt     FlexibleTime `json:"closes_at"`
+}
+
+type TeamsRequest struct {
+	Teams []string `json:"teams"`
+}
+
+// OutrightWithAnswer is a question as the caller sees it: with their own answer and what it
+// is worth so far.
+type OutrightWithAnswer struct {
+	models.OutrightQuestion
+	MyTeams  []string `json:"my_teams"`
+	MyPoints int      `json:"my_points"`
+}
+
+func (h *OutrightHandler) List(c *gin.Context) {
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	userIDVal, _ := c.Get("user_id")
+	userID := userIDVal.(uuid.UUID)
+
+	questions, err := h.outrightRepo.ListQuestions(c.Request.Context(), bolaoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	answers, err := h.outrightRepo.ListAnswersByUser(c.Request.Context(), bolaoID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	mine := make(map[uuid.UUID][]string, len(answers))
+	for _, a := range answers {
+		mine[a.QuestionID] = a.Teams
+	}
+
+	result := make([]OutrightWithAnswer, 0, len(questions))
+	for _, q := range questions {
+		teams := mine[q.ID]
+		if teams == nil {
+			teams = []string{}
+		}
+		result = append(result, OutrightWithAnswer{
+			OutrightQuestion: q,
+			MyTeams:          teams,
+			MyPoints:         service.ScoreOutright(q, teams),
+		})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListAnswers shows everyone's answer to a question. Like the other players' predictions,
+// they stay hidden until the deadline so nobody can copy them.
+func (h *OutrightHandler) ListAnswers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+
+	q, err := h.outrightRepo.GetQuestion(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "aposta não encontrada"})
+		return
+	}
+	if !time.Now().After(q.ClosesAt) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "as apostas só ficam visíveis após o prazo"})
+		return
+	}
+
+	answers, err := h.outrightRepo.ListAnswersByQuestion(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if answers == nil {
+		answers = []models.OutrightAnswer{}
+	}
+	c.JSON(http.StatusOK, answers)
+}
+
+func (h *OutrightHandler) Answer(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+
+	var req TeamsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	q, ok := h.activeQuestion(c, id)
+	if !ok {
+		return
+	}
+	if time.Now().After(q.ClosesAt) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "prazo encerrado para esta aposta"})
+		return
+	}
+	if err := service.ValidateTeamPicks(req.Teams, q.Picks); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userIDVal, _ := c.Get("user_id")
+	answer := &models.OutrightAnswer{
+		QuestionID: q.ID,
+		UserID:     userIDVal.(uuid.UUID),
+		Teams:      req.Teams,
+	}
+	if err := h.outrightRepo.UpsertAnswer(c.Request.Context(), answer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, answer)
+}
+
+func (h *OutrightHandler) Create(c *gin.Context) {
+	var req CreateOutrightRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "título é obrigatório"})
+		return
+	}
+	if req.Picks < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "picks deve ser pelo menos 1"})
+		return
+	}
+	if req.PointsPerHit < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "points_per_hit não pode ser negativo"})
+		return
+	}
+	if req.ClosesAt.Time == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "closes_at é obrigatório"})
+		return
+	}
+
+	active, err := h.bolaoRepo.GetActive(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "nenhum bolão ativo encontrado"})
+		return
+	}
+
+	q := &models.OutrightQuestion{
+		ID:           uuid.New(),
+		BolaoID:      active.ID,
+		Title:        req.Title,
+		Picks:        req.Picks,
+		PointsPerHit: req.PointsPerHit,
+		ClosesAt:     req.ClosesAt.Time.UTC(),
+	}
+	if err := h.outrightRepo.CreateQuestion(c.Request.Context(), q); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, q)
+}
+
+// SetOutcome records which teams actually made it. Sending an empty list reopens the
+// question as unresolved.
+func (h *OutrightHandler) SetOutcome(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+
+	var req TeamsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	q, ok := h.activeQuestion(c, id)
+	if !ok {
+		return
+	}
+
+	var outcome []string
+	if len(req.Teams) > 0 {
+		if err := service.CheckOutrightOutcome(*q, time.Now()); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if err := service.ValidateTeamPicks(req.Teams, q.Picks); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		outcome = req.Teams
+	}
+
+	if err := h.outrightRepo.SetOutcome(c.Request.Context(), id, outcome); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "aposta não encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	q.Outcome = outcome
+	c.JSON(http.StatusOK, q)
+}
+
+func (h *OutrightHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+
+	if _, ok := h.activeQuestion(c, id); !ok {
+		return
+	}
+
+	if err := h.outrightRepo.DeleteQuestion(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "aposta excluída"})
+}
+
+// activeQuestion loads a question and refuses it when it belongs to a finished bolão, whose
+// standings must no longer move. It writes the error response itself.
+func (h *OutrightHandler) activeQuestion(c *gin.Context, id uuid.UUID) (*models.OutrightQuestion, bool) {
+	q, err := h.outrightRepo.GetQuestion(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "aposta não encontrada"})
+		return nil, false
+	}
+	active, err := h.bolaoRepo.GetActive(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "nenhum bolão ativo encontrado"})
+		return nil, false
+	}
+	if q.BolaoID != active.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "não é possível alterar apostas de um bolão encerrado"})
+		return nil, false
+	}
+	return q, true
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -10,16 +11,34 @@ import (
 	"github.com/bolao-app/api/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type PredictionHandler struct {
-	predictionRepo *repository.PredictionRepository
-	matchRepo      *repository.MatchRepository
-	bolaoRepo      *repository.BolaoRepository
+	predictionRepo    *repository.PredictionRepository
+	matchRepo         *repository.MatchRepository
+	bolaoRepo         *repository.BolaoRepository
+	jokerRepo         *repository.JokerRepository
+	tieRepo           *repository.TieRepository
+	classificationSvc *service.ClassificationService
 }
 
-func NewPredictionHandler(predictionRepo *repository.PredictionRepository, matchRepo *repository.MatchRepository, bolaoRepo *repository.BolaoRepository) *PredictionHandler {
-	return &PredictionHandler{predictionRepo: predictionRepo, matchRepo: matchRepo, bolaoRepo: bolaoRepo}
+func NewPredictionHandler(
+	predictionRepo *repository.PredictionRepository,
+	matchRepo *repository.MatchRepository,
+	bolaoRepo *repository.BolaoRepository,
+	jokerRepo *repository.JokerRepository,
+	tieRepo *repository.TieRepository,
+	classificationSvc *service.ClassificationService,
+) *PredictionHandler {
+	return &PredictionHandler{
+		predictionRepo:    predictionRepo,
+		matchRepo:         matchRepo,
+		bolaoRepo:         bolaoRepo,
+		jokerRepo:         jokerRepo,
+		tieRepo:           tieRepo,
+		classificationSvc: classificationSvc,
+	}
 }
 
 type UpsertPredictionRequest struct {
@@ -33,7 +52,11 @@ type UpsertPredictionsRequest struct {
 		MatchID   string `json:"match_id" binding:"required"`
 		HomeGoals int    `json:"home_goals" binding:"gte=0"`
 		AwayGoals int    `json:"away_goals" binding:"gte=0"`
+		// Advances picks who goes through a knockout match predicted as a draw.
+		Advances *string `json:"advances"`
 	} `json:"predictions" binding:"required"`
+	// JokerMatchID optionally picks the coringa for that match's round.
+	JokerMatchID *string `json:"joker_match_id"`
 }
 
 func (h *PredictionHandler) GetMyPredictions(c *gin.Context) {
@@ -69,6 +92,13 @@ func (h *PredictionHandler) GetMyPredictions(c *gin.Context) {
 	}
 	predictions = service.FillMissingPredictions(matches, userID, predictions, time.Now())
 
+	joker, err := h.roundJoker(c, userID, bolaoID, roundInt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	predictions = service.MarkJoker(predictions, joker)
+
 	c.JSON(http.StatusOK, predictions)
 }
 
@@ -100,11 +130,9 @@ func (h *PredictionHandler) GetByUserAndRound(c *gin.Context) {
 		return
 	}
 	now := time.Now()
-	for _, m := range matches {
-		if m.MarketClosesAt == nil || now.Before(*m.MarketClosesAt) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "só é possível ver palpites de outros jogadores após o fechamento do mercado da rodada"})
-			return
-		}
+	if !service.RoundMarketClosed(matches, now) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "só é possível ver palpites de outros jogadores após o fechamento do mercado da rodada"})
+		return
 	}
 
 	predictions, err := h.predictionRepo.GetByUserAndRound(c.Request.Context(), userID, bolaoID, round)
@@ -116,9 +144,64 @@ func (h *PredictionHandler) GetByUserAndRound(c *gin.Context) {
 	// Reuses the gate's `now`, which already proved every match in the round is closed.
 	predictions = service.FillMissingPredictions(matches, userID, predictions, now)
 
+	joker, err := h.roundJoker(c, userID, bolaoID, round)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	predictions = service.MarkJoker(predictions, joker)
+
 	c.JSON(http.StatusOK, predictions)
 }
 
+// GetBreakdown explains, rule by rule, the points a user got in a round. It reveals the
+// user's predictions, so it sits behind the same market-closed gate as GetByUserAndRound.
+func (h *PredictionHandler) GetBreakdown(c *gin.Context) {
+	round, err := strconv.Atoi(c.Param("round"))
+	if err != nil || round < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rodada inválida"})
+		return
+	}
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "user_id inválido"})
+		return
+	}
+
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	breakdown, err := h.classificationSvc.ExplainRound(c.Request.Context(), bolaoID, userID, round)
+	if err != nil {
+		if errors.Is(err, service.ErrRoundMarketOpen) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrNotParticipant) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// roundJoker returns the user's coringa match for the round, or uuid.Nil when none was picked.
+func (h *PredictionHandler) roundJoker(c *gin.Context, userID, bolaoID uuid.UUID, round int) (uuid.UUID, error) {
+	joker, err := h.jokerRepo.GetByUserAndRound(c.Request.Context(), userID, bolaoID, round)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return uuid.Nil, nil
+	}
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return joker.MatchID, nil
+}
+
 func (h *PredictionHandler) UpsertPredictions(c *gin.Context) {
 	userID := c.MustGet("user_id").(uuid.UUID)
 
@@ -134,6 +217,20 @@ func (h *PredictionHandler) UpsertPredictions(c *gin.Context) {
 		return
 	}
 
+	// The coringa is checked before any prediction is written, so a rejected pick does
+	// not leave the request half-applied.
+	var joker *models.Joker
+	if req.JokerMatchID != nil {
+		joker, err = h.validateJoker(c, userID, active.ID, *req.JokerMatchID)
+		if err != nil {
+			return
+		}
+	}
+
+	var rounds []int
+	if joker != nil {
+		rounds = append(rounds, joker.Round)
+	}
 	for _, p := range req.Predictions {
 		matchID, err := uuid.Parse(p.MatchID)
 		if err != nil {
@@ -157,18 +254,99 @@ func (h *PredictionHandler) UpsertPredictions(c *gin.Context) {
 			return
 		}
 
+		// On the second leg of a tie the pick is about the aggregate, not this match.
+		tie, err := h.tieRepo.GetByMatch(c.Request.Context(), matchID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if tie != nil && tie.SecondLegID == matchID {
+			err = service.ValidateTiePick(p.Advances)
+		} else {
+			err = service.ValidateAdvancePick(*match, p.HomeGoals, p.AwayGoals, p.Advances)
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
 		prediction := &models.Prediction{
 			ID:        uuid.New(),
 			UserID:    userID,
 			MatchID:   matchID,
 			HomeGoals: p.HomeGoals,
 			AwayGoals: p.AwayGoals,
+			Advances:  p.Advances,
 		}
 		if err := h.predictionRepo.Upsert(c.Request.Context(), prediction); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
+		rounds = append(rounds, match.Round)
+	}
+
+	if joker != nil {
+		if err := h.jokerRepo.Upsert(c.Request.Context(), joker); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	// A first-leg prediction also counts in the second leg's round.
+	if len(rounds) > 0 {
+		affected, err := affectedRounds(c, h.classificationSvc, active.ID, rounds...)
+		if err != nil {
+			return
+		}
+		if err := invalidateRounds(c, h.classificationSvc, active.ID, affected); err != nil {
+			return
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{"message": "palpites salvos"})
 }
+
+// validateJoker writes the error response and returns a non-nil error unless matchIDStr
+// can become the user's coringa: a match of the active bolão whose market is still open,
+// in a round where the current coringa (if any) has not closed yet. Once the chosen
+// match closes, the pick for that round is locked — like the predictions themselves.
+func (h *PredictionHandler) validateJoker(c *gin.Context, userID, bolaoID uuid.UUID, matchIDStr string) (*models.Joker, error) {
+	ctx := c.Request.Context()
+	matchID, err := uuid.Parse(matchIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "joker_match_id inválido: " + matchIDStr})
+		return nil, err
+	}
+	match, err := h.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "jogo do coringa não encontrado"})
+		return nil, err
+	}
+	if match.BolaoID != bolaoID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "não é possível escolher coringa em um bolão encerrado"})
+		return nil, errJokerLocked
+	}
+
+	now := time.Now()
+	if service.MarketClosed(*match, now) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "mercado fechado para o jogo " + match.HomeTeam + " x " + match.AwayTeam})
+		return nil, errJokerLocked
+	}
+
+	current, err := h.jokerRepo.GetByUserAndRound(ctx, userID, bolaoID, match.Round)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, err
+	}
+	if err == nil && current.MatchID != matchID {
+		currentMatch, err := h.matchRepo.GetByID(ctx, current.MatchID)
+		if err == nil && service.MarketClosed(*currentMatch, now) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "o coringa da rodada já está travado no jogo " + currentMatch.HomeTeam + " x " + currentMatch.AwayTeam})
+			return nil, errJokerLocked
+		}
+	}
+
+	return &models.Joker{UserID: userID, BolaoID: bolaoID, Round: match.Round, MatchID: matchID}, nil
+}
+
+var errJokerLocked = errors.New("joker locked")
@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/bolao-app/api/internal/repository"
+	"github.com/bolao-app/api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type RoundQuestionHandler struct {
+	questionRepo      *repository.RoundQuestionRepository
+	matchRepo         *repository.MatchRepository
+	bolaoRepo         *repository.BolaoRepository
+	classificationSvc *service.ClassificationService
+}
+
+func NewRoundQuestionHandler(questionRepo *repository.RoundQuestionRepository, matchRepo *repository.MatchRepository, bolaoRepo *repository.BolaoRepository, classificationSvc *service.ClassificationService) *RoundQuestionHandler {
+	return &RoundQuestionHandler{questionRepo: questionRepo, matchRepo: matchRepo, bolaoRepo: bolaoRepo, classificationSvc: classificationSvc}
+}
+
+type CreateRoundQuestionRequest struct {
+	Round   int      `json:"round" binding:"required,min=1"`
+	Prompt  string   `json:"prompt"`
+	Kind    string   `json:"kind"`
+	Options []string `json:"options"`
+	Points  int      `json:"points"`
+}
+
+type AnswerRoundQuestionsRequest struct {
+	Answers []struct {
+		QuestionID string `json:"question_id" binding:"required"`
+		Answer     string `json:"answer"`
+	} `json:"answers" binding:"required,dive"`
+}
+
+type SetRoundQuestionResultRequest struct {
+	// Result nil clears it, the same as sending no goals to UpdateResults would.
+	Result *string `json:"result"`
+}
+
+// RoundQuestionWithAnswer is a question as the caller sees it, with their own answer.
+type RoundQuestionWithAnswer struct {
+	models.RoundQuestion
+	MyAnswer *string `json:"my_answer"`
+}
+
+func (h *RoundQuestionHandler) ListByRound(c *gin.Context) {
+	round, err := strconv.Atoi(c.Param("round"))
+	if err != nil || round < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rodada inválida"})
+		return
+	}
+
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	userIDVal, _ := c.Get("user_id")
+	userID := userIDVal.(uuid.UUID)
+
+	questions, err := h.questionRepo.ListByRound(c.Request.Context(), bolaoID, round)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	answers, err := h.questionRepo.ListAnswersByRound(c.Request.Context(), bolaoID, round)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	mine := make(map[uuid.UUID]string)
+	for _, a := range answers {
+		if a.UserID == userID {
+			mine[a.QuestionID] = a.Answer
+		}
+	}
+
+	result := make([]RoundQuestionWithAnswer, 0, len(questions))
+	for _, q := range questions {
+		item := RoundQuestionWithAnswer{RoundQuestion: q}
+		if a, ok := mine[q.ID]; ok {
+			item.MyAnswer = &a
+		}
+		result = append(result, item)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ListAnswers shows everyone's answers for the round, only once they are locked — the same
+// rule as seeing other players' predictions.
+func (h *RoundQuestionHandler) ListAnswers(c *gin.Context) {
+	round, err := strconv.Atoi(c.Param("round"))
+	if err != nil || round < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rodada inválida"})
+		return
+	}
+
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	matches, err := h.matchRepo.ListByRound(c.Request.Context(), bolaoID, round)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !service.RoundQuestionsClosed(matches, time.Now()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "as respostas só ficam visíveis após o fechamento do mercado da rodada"})
+		return
+	}
+
+	answers, err := h.questionRepo.ListAnswersByRound(c.Request.Context(), bolaoID, round)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if answers == nil {
+		answers = []models.RoundQuestionAnswer{}
+	}
+	c.JSON(http.StatusOK, answers)
+}
+
+// Answer saves the caller's answers to the round's questions. Like UpsertPredictions it
+// validates the whole batch before writing any of it.
+func (h *RoundQuestionHandler) Answer(c *gin.Context) {
+	round, err := strconv.Atoi(c.Param("round"))
+	if err != nil || round < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "rodada inválida"})
+		return
+	}
+
+	var req AnswerRoundQuestionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	active, err := h.bolaoRepo.GetActive(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "nenhum bolão ativo encontrado"})
+		return
+	}
+
+	matches, err := h.matchRepo.ListByRound(ctx, active.ID, round)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if service.RoundQuestionsClosed(matches, time.Now()) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "mercado fechado para as perguntas da rodada"})
+		return
+	}
+
+	questions, err := h.questionRepo.ListByRound(ctx, active.ID, round)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	byID := make(map[uuid.UUID]models.RoundQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+
+	userIDVal, _ := c.Get("user_id")
+	userID := userIDVal.(uuid.UUID)
+
+	toSave := make([]models.RoundQuestionAnswer, 0, len(req.Answers))
+	for _, a := range req.Answers {
+		questionID, err := uuid.Parse(a.QuestionID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "question_id inválido: " + a.QuestionID})
+			return
+		}
+		q, ok := byID[questionID]
+		if !ok {
+			c.JSON(http.StatusNotFound, gin.H{"error": "pergunta não encontrada nesta rodada: " + a.QuestionID})
+			return
+		}
+		answer, err := service.NormalizeQuestionAnswer(q, a.Answer)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		toSave = append(toSave, models.RoundQuestionAnswer{QuestionID: questionID, UserID: userID, Answer: answer})
+	}
+
+	for i := range toSave {
+		if err := h.questionRepo.UpsertAnswer(ctx, &toSave[i]); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+	if err := invalidateRounds(c, h.classificationSvc, active.ID, []int{round}); err != nil {
+		return
+	}
+	c.JSON(http.StatusOK, toSave)
+}
+
+func (h *RoundQuestionHandler) Create(c *gin.Context) {
+	var req CreateRoundQuestionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	active, err := h.bolaoRepo.GetActive(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "nenhum bolão ativo encontrado"})
+		return
+	}
+
+	q := &models.RoundQuestion{
+		ID:      uuid.New(),
+		BolaoID: active.ID,
+		Round:   req.Round,
+		Prompt:  strings.TrimSpace(req.Prompt),
+		Kind:    req.Kind,
+		Options: req.Options,
+		Points:  req.Points,
+	}
+	if err := service.ValidateRoundQuestion(*q); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.questionRepo.Create(c.Request.Context(), q); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, q)
+}
+
+func (h *RoundQuestionHandler) SetResult(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+
+	var req SetRoundQuestionResultRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	q, ok := h.activeQuestion(c, id)
+	if !ok {
+		return
+	}
+
+	var result *string
+	if req.Result != nil {
+		matches, err := h.matchRepo.ListByRound(c.Request.Context(), q.BolaoID, q.Round)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if err := service.CheckRoundQuestionResult(matches, time.Now()); err != nil {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		normalized, err := service.NormalizeQuestionAnswer(*q, *req.Result)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		result = &normalized
+	}
+
+	if err := h.questionRepo.SetResult(c.Request.Context(), id, result); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "pergunta não encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := refreshRounds(c, h.classificationSvc, q.BolaoID, []int{q.Round}); err != nil {
+		return
+	}
+	q.Result = result
+	c.JSON(http.StatusOK, q)
+}
+
+func (h *RoundQuestionHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id inválido"})
+		return
+	}
+
+	q, ok := h.activeQuestion(c, id)
+	if !ok {
+		return
+	}
+
+	if err := h.questionRepo.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := refreshRounds(c, h.classificationSvc, q.BolaoID, []int{q.Round}); err != nil {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "pergunta excluída"})
+}
+
+// activeQuestion loads a question and refuses it when it belongs to a finished bolão. It
+// writes the error response itself.
+func (h *RoundQuestionHandler) activeQuestion(c *gin.Context, id uuid.UUID) (*models.RoundQuestion, bool) {
+	q, err := h.questionRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "pergunta não encontrada"})
+		return nil, false
+	}
+	active, err := h.bolaoRepo.GetActive(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "nenhum bolão ativo encontrado"})
+		return nil, false
+	}
+	if q.BolaoID != active.ID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "não é possível alterar perguntas de um bolão encerrado"})
+		return nil, false
+	}
+	return q, true
+}
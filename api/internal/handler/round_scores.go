@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/bolao-app/api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// affectedRounds returns the rounds whose materialized scores a write to rounds changes,
+// or writes a 500 response and returns a non-nil error. Call it before the write: a
+// deleted match takes its tie with it.
+func affectedRounds(c *gin.Context, svc *service.ClassificationService, bolaoID uuid.UUID, rounds ...int) ([]int, error) {
+	affected, err := svc.AffectedRounds(c.Request.Context(), bolaoID, rounds...)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, err
+	}
+	return affected, nil
+}
+
+// refreshRounds recomputes the materialized scores of rounds after a write, or writes a
+// 500 response and returns a non-nil error. The write itself is kept, so retrying it is
+// safe.
+func refreshRounds(c *gin.Context, svc *service.ClassificationService, bolaoID uuid.UUID, rounds []int) error {
+	if err := svc.RefreshRounds(c.Request.Context(), bolaoID, rounds...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "alteração salva, mas a classificação não foi recalculada: " + err.Error()})
+		return err
+	}
+	return nil
+}
+
+// invalidateRounds marks the materialized scores of rounds stale after a write, for the
+// next read to recompute, or writes a 500 response and returns a non-nil error.
+func invalidateRounds(c *gin.Context, svc *service.ClassificationService, bolaoID uuid.UUID, rounds []int) error {
+	if err := svc.InvalidateRounds(c.Request.Context(), bolaoID, rounds...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "alteração salva, mas a classificação não foi atualizada: " + err.Error()})
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/bolao-app/api/internal/repository"
+	"github.com/bolao-app/api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+type TieHandler struct {
+	tieRepo           *repository.TieRepository
+	matchRepo         *repository.MatchRepository
+	bolaoRepo         *repository.BolaoRepository
+	classificationSvc *service.ClassificationService
+}
+
+func NewTieHandler(tieRepo *repository.TieRepository, matchRepo *repository.MatchRepository, bolaoRepo *repository.BolaoRepository, classificationSvc *service.ClassificationService) *TieHandler {
+	return &TieHandler{tieRepo: tieRepo, matchRepo: matchRepo, bolaoRepo: bolaoRepo, classificationSvc: classificationSvc}
+}
+
+type CreateTieRequest struct {
+	FirstLegID  string `json:"first_leg_id" binding:"required"`
+	SecondLegID string `json:"second_leg_id" binding:"required"`
+	AwayGoals   bool   `json:"away_goals"`
+}
+
+func (h *TieHandler) List(c *gin.Context) {
+	bolaoID, err := resolveBolaoID(c, h.bolaoRepo)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "bolão inválido"})
+		return
+	}
+
+	ties, err := h.tieRepo.ListByBolao(c.Request.Context(), bolaoID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if ties == nil {
+		ties = []models.Tie{}
+	}
+	c.JSON(http.StatusOK, ties)
+}
+
+// Create links two matches of the active bolão into a two-legged tie; the second leg
+// becomes a knockout match.
+func (h *TieHandler) Create(c *gin.Context) {
+	var req CreateTieRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx := c.Request.Context()
+	active, err := h.bolaoRepo.GetActive(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "nenhum bolão ativo encontrado"})
+		return
+	}
+
+	var legs [2]models.Match
+	for i, idStr := range []string{req.FirstLegID, req.SecondLegID} {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "id de jogo inválido: " + idStr})
+			return
+		}
+		m, err := h.matchRepo.GetByID(ctx, id)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "jogo não encontrado: " + idStr})
+			return
+		}
+		if m.BolaoID != active.ID {
+			c.JSON(http.StatusForbidden, gin.H{"error": "não é possível criar confrontos em um bolão encerrado"})
+			return
+		}
+		if _, err := h.tieRepo.GetByMatch(ctx, id); err == nil {
+			c.JSON(http.StatusConflict, gin.H{"error": m.HomeTeam + " x " + m.AwayTeam + " já faz parte de um confronto"})
+			return
+		} else if !errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		legs[i] = *m
+	}
+	if err := service.ValidateTie(legs[0], legs[1]); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tie := &models.Tie{
+		ID:          uuid.New(),
+		BolaoID:     active.ID,
+		FirstLegID:  legs[0].ID,
+		SecondLegID: legs[1].ID,
+		AwayGoals:   req.AwayGoals,
+	}
+	if err := h.tieRepo.Create(ctx, tie); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := refreshRounds(c, h.classificationSvc, active.ID, []int{legs[1].Round}); err != nil {
+		return
+	}
+	c.JSON(http.StatusCreated, tie)
+}
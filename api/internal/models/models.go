@@ -25,10 +25,65 @@ type Match struct {
 	HomeTeam       string     `json:"home_team"`
 	AwayTeam       string     `json:"away_team"`
 	MarketClosesAt *time.Time `json:"market_closes_at,omitempty"`
-	HomeGoals      *int       `json:"home_goals,omitempty"`
-	AwayGoals      *int       `json:"away_goals,omitempty"`
-	CreatedAt      time.Time  `json:"created_at"`
-	UpdatedAt      time.Time  `json:"updated_at"`
+	// HomeGoals and AwayGoals are the regulation (90-minute) score, also in a knockout match.
+	HomeGoals *int `json:"home_goals,omitempty"`
+	AwayGoals *int `json:"away_goals,omitempty"`
+	// Knockout marks a cup match someone has to advance from (SCORING.md §2.7).
+	Knockout bool `json:"knockout"`
+	// ExtraTimeHomeGoals and ExtraTimeAwayGoals are the score after extra time, regulation
+	// goals included; nil when the match had none.
+	ExtraTimeHomeGoals *int `json:"extra_time_home_goals,omitempty"`
+	ExtraTimeAwayGoals *int `json:"extra_time_away_goals,omitempty"`
+	// PenaltyWinner is the side ("home" or "away") that won the shoot-out, nil without one.
+	PenaltyWinner *string   `json:"penalty_winner,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// MatchResult is a final result as the admin enters it. The extra-time score and the
+// penalty winner only apply to a knockout match.
+type MatchResult struct {
+	HomeGoals          int     `json:"home_goals"`
+	AwayGoals          int     `json:"away_goals"`
+	ExtraTimeHomeGoals *int    `json:"extra_time_home_goals"`
+	ExtraTimeAwayGoals *int    `json:"extra_time_away_goals"`
+	PenaltyWinner      *string `json:"penalty_winner"`
+}
+
+// Tie is a two-legged cup tie, decided on the aggregate of its two matches (SCORING.md
+// §2.8). Its sides are those of the second leg, where extra time, penalties and the
+// advances pick go; that leg is a knockout match.
+type Tie struct {
+	ID          uuid.UUID `json:"id"`
+	BolaoID     uuid.UUID `json:"bolao_id"`
+	FirstLegID  uuid.UUID `json:"first_leg_id"`
+	SecondLegID uuid.UUID `json:"second_leg_id"`
+	// AwayGoals breaks a level aggregate on goals scored away.
+	AwayGoals bool      `json:"away_goals"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RoundScoreRow is a participant's materialized score in one round, as the cumulative
+// standings add it up: the points before the round's weight, and the first prediction of
+// the round for the tiebreakers.
+type RoundScoreRow struct {
+	UserID          uuid.UUID  `json:"user_id"`
+	Round           int        `json:"round"`
+	Points          int        `json:"points"`
+	ExactScores     int        `json:"exact_scores"`
+	CorrectResults  int        `json:"correct_results"`
+	Missed          int        `json:"missed"`
+	RoundTotalHits  int        `json:"round_total_hits"`
+	FirstSubmission *time.Time `json:"first_submission,omitempty"`
+}
+
+// RoundScoreState says whether a round's materialized scores are current: they are while
+// ComputedGeneration equals Generation and ValidUntil hasn't passed.
+type RoundScoreState struct {
+	Round              int        `json:"round"`
+	Generation         int64      `json:"generation"`
+	ComputedGeneration *int64     `json:"computed_generation,omitempty"`
+	ValidUntil         *time.Time `json:"valid_until,omitempty"`
 }
 
 type Bolao struct {
@@ -37,8 +92,106 @@ type Bolao struct {
 	Status     string     `json:"status"`
 	StartedAt  time.Time  `json:"started_at"`
 	FinishedAt *time.Time `json:"finished_at,omitempty"`
-	CreatedAt  time.Time  `json:"created_at"`
-	UpdatedAt  time.Time  `json:"updated_at"`
+	BolaoSettings
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// BolaoSettings are the bolão-wide options of how the standings are built, as opposed to
+// the per-round point values of ScoringRuleset.
+type BolaoSettings struct {
+	// Tiebreakers orders the criteria that separate players on the same points (see
+	// service.Tiebreak*). Empty means the default chain.
+	Tiebreakers []string `json:"tiebreakers"`
+	// SharedPositions gives players no criterion separates the same position.
+	SharedPositions bool `json:"shared_positions"`
+	// RoundWeights boosts the points of ranges of rounds in the cumulative standings.
+	// Rounds outside every range weigh 100%.
+	RoundWeights []RoundWeight `json:"round_weights"`
+	// DropWorstRounds discards each player's N lowest round scores from the cumulative
+	// standings. 0 keeps every round.
+	DropWorstRounds int `json:"drop_worst_rounds"`
+	// RoundRanges names ranges of rounds that get standings of their own, such as the
+	// turno and the returno.
+	RoundRanges []RoundRange `json:"round_ranges"`
+	// MonthlyPrize is the money paid to the player of each month, split among the players
+	// sharing first place. 0 pays no monthly prize.
+	MonthlyPrize float64 `json:"monthly_prize"`
+}
+
+// RoundWeight makes rounds FromRound..ToRound count Percent% of their points (150 = 1.5×).
+type RoundWeight struct {
+	FromRound int `json:"from_round"`
+	ToRound   int `json:"to_round"`
+	Percent   int `json:"percent"`
+}
+
+// RoundRange is a named range of rounds FromRound..ToRound with standings of its own.
+type RoundRange struct {
+	Name      string `json:"name"`
+	FromRound int    `json:"from_round"`
+	ToRound   int    `json:"to_round"`
+}
+
+// ScoringRuleset holds the point values a bolão is scored with. It is created together with
+// the bolão and never edited afterwards, so a finished season keeps the rules it was
+// played under even after the group votes new values for the next one.
+type ScoringRuleset struct {
+	ID      uuid.UUID `json:"id"`
+	BolaoID uuid.UUID `json:"bolao_id"`
+	// Version numbers a bolão's rule changes from 1; each version scores the rounds from
+	// EffectiveFromRound until the next version takes over.
+	Version            int `json:"version"`
+	EffectiveFromRound int `json:"effective_from_round"`
+	// Engine is the scoring system (service.Engine*). The point values below only apply to
+	// the "bolao" engine; JokerMultiplier, UnderdogMultiplier, the favorite-team bonuses and
+	// QualifierPoints apply to all of them.
+	Engine           string `json:"engine"`
+	CorrectResult    int    `json:"correct_result"`
+	CorrectDraw      int    `json:"correct_draw"`
+	CorrectHomeGoals int    `json:"correct_home_goals"`
+	CorrectAwayGoals int    `json:"correct_away_goals"`
+	ExactScore       int    `json:"exact_score"`
+	ExactScoreHigh   int    `json:"exact_score_high"`
+	TotalGoalsHigh   int    `json:"total_goals_high"`
+	// HighScoringGoals is the real goal total from which a match counts as high-scoring
+	// (ExactScoreHigh replaces ExactScore, and TotalGoalsHigh applies).
+	HighScoringGoals int `json:"high_scoring_goals"`
+	RoundTotalGoals  int `json:"round_total_goals"`
+	// ScoreTypeBonus[i] is the bonus for i+1 distinct exact-score types in a round; the last
+	// entry also covers every count above it.
+	ScoreTypeBonus []int `json:"score_type_bonus"`
+	// JokerMultiplier multiplies the match points of each participant's coringa.
+	JokerMultiplier int `json:"joker_multiplier"`
+	// UnderdogMultiplier is what the points of a result nobody else called are multiplied
+	// by; a result everyone called keeps its points. 0 turns the underdog bonus off.
+	UnderdogMultiplier int `json:"underdog_multiplier"`
+	// FavoriteTeamExactBonus and FavoriteTeamResultBonus are paid on top of an exact score,
+	// or a correct result that isn't exact, in a match of the player's favorite team. 0 turns
+	// each off.
+	FavoriteTeamExactBonus  int `json:"favorite_team_exact_bonus"`
+	FavoriteTeamResultBonus int `json:"favorite_team_result_bonus"`
+	// QualifierPoints is paid for picking the side that goes through a knockout match.
+	QualifierPoints int       `json:"qualifier_points"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+// OutcomeShares is how the participants split on a match's outcome once its market closed.
+// It is frozen then, so the underdog bonus doesn't move when someone joins later.
+type OutcomeShares struct {
+	MatchID  uuid.UUID `json:"match_id"`
+	Home     int       `json:"home"`
+	Draw     int       `json:"draw"`
+	Away     int       `json:"away"`
+	FrozenAt time.Time `json:"frozen_at"`
+}
+
+// FavoriteTeamChange records a user's favorite team from ChangedAt on. Team is nil when the
+// user cleared it.
+type FavoriteTeamChange struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Team      *string   `json:"team"`
+	ChangedAt time.Time `json:"changed_at"`
 }
 
 type BolaoParticipant struct {
@@ -61,9 +214,70 @@ type Prediction struct {
 	MatchID   uuid.UUID `json:"match_id"`
 	HomeGoals int       `json:"home_goals"`
 	AwayGoals int       `json:"away_goals"`
+	// Advances is the side ("home" or "away") picked to go through a knockout match. A score
+	// with a winner already picks it; it only matters for a draw (SCORING.md §2.7).
+	Advances *string `json:"advances,omitempty"`
 	// Synthesized at read time for a closed match with no prediction; no database row exists.
-	AutoFilled bool      `json:"auto_filled,omitempty"`
-	CreatedAt  time.Time `json:"created_at"`
+	AutoFilled bool `json:"auto_filled,omitempty"`
+	// Set at read time from the jokers table: this match is the user's coringa.
+	Joker     bool      `json:"joker,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Joker is a participant's coringa for a round: the one match whose points count
+// JokerMultiplier times. At most one per participant per round.
+type Joker struct {
+	UserID    uuid.UUID `json:"user_id"`
+	BolaoID   uuid.UUID `json:"bolao_id"`
+	Round     int       `json:"round"`
+	MatchID   uuid.UUID `json:"match_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// OutrightQuestion is a season-long bet (champion, relegated teams, G4...): each participant
+// picks Picks teams before ClosesAt and earns PointsPerHit for every one that is in the
+// Outcome an admin enters once the season is decided. Outcome is nil until then.
+type OutrightQuestion struct {
+	ID           uuid.UUID `json:"id"`
+	BolaoID      uuid.UUID `json:"bolao_id"`
+	Title        string    `json:"title"`
+	Picks        int       `json:"picks"`
+	PointsPerHit int       `json:"points_per_hit"`
+	ClosesAt     time.Time `json:"closes_at"`
+	Outcome      []string  `json:"outcome,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type OutrightAnswer struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Teams      []string  `json:"teams"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// RoundQuestion is an ad-hoc bonus question an admin attaches to a round ("will there be a
+// red card in Fla x Flu?"). Kind is "choice", answered with one of Options, or "number",
+// answered with a whole number. Result stays nil until an admin enters it; a matching
+// answer then earns Points in that round.
+type RoundQuestion struct {
+	ID        uuid.UUID `json:"id"`
+	BolaoID   uuid.UUID `json:"bolao_id"`
+	Round     int       `json:"round"`
+	Prompt    string    `json:"prompt"`
+	Kind      string    `json:"kind"`
+	Options   []string  `json:"options,omitempty"`
+	Points    int       `json:"points"`
+	Result    *string   `json:"result,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type RoundQuestionAnswer struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	UserID     uuid.UUID `json:"user_id"`
+	Answer     string    `json:"answer"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
 
@@ -77,9 +291,21 @@ type MatchPartial struct {
 
 type UserWithStats struct {
 	User
+	// Position is the rank shown to players; equal for tied players in shared-position mode.
+	Position       int     `json:"position"`
 	AmountPaid     float64 `json:"amount_paid"`
 	TotalPoints    int     `json:"total_points"`
 	ExactScores    int     `json:"exact_scores"`
 	CorrectResults int     `json:"correct_results"`
 	RoundsWon      int     `json:"rounds_won"`
+	// MissedPredictions and RoundTotalHits feed the fewest_missed and round_total_hits
+	// tiebreakers.
+	MissedPredictions int `json:"missed_predictions"`
+	RoundTotalHits    int `json:"round_total_hits"`
+	// OutrightPoints is the part of TotalPoints that came from season-long bets.
+	OutrightPoints int `json:"outright_points,omitempty"`
+	// GrossPoints is the cumulative total before the drop-worst-rounds rule; TotalPoints is
+	// the net total. DiscardedRounds are the rounds the rule dropped.
+	GrossPoints     int   `json:"gross_points,omitempty"`
+	DiscardedRounds []int `json:"discarded_rounds,omitempty"`
 }
@@ -23,13 +23,28 @@ func NewBolaoRepository(pool *pgxpool.Pool) *BolaoRepository {
 	return &BolaoRepository{pool: pool}
 }
 
-func (r *BolaoRepository) Create(ctx context.Context, name string) (*models.Bolao, error) {
-	var b models.Bolao
-	query := `INSERT INTO boloes (id, name) VALUES ($1, $2)
-		RETURNING id, name, status, started_at, finished_at, created_at, updated_at`
-	err := r.pool.QueryRow(ctx, query, uuid.New(), name).Scan(
-		&b.ID, &b.Name, &b.Status, &b.StartedAt, &b.FinishedAt, &b.CreatedAt, &b.UpdatedAt,
+const bolaoColumns = `id, name, status, started_at, finished_at, tiebreakers, shared_positions, round_weights, drop_worst_rounds, round_ranges, monthly_prize, created_at, updated_at`
+
+func scanBolao(row pgx.Row, b *models.Bolao) error {
+	return row.Scan(
+		&b.ID, &b.Name, &b.Status, &b.StartedAt, &b.FinishedAt, &b.Tiebreakers, &b.SharedPositions, &b.RoundWeights, &b.DropWorstRounds, &b.RoundRanges, &b.MonthlyPrize, &b.CreatedAt, &b.UpdatedAt,
 	)
+}
+
+// Create starts a bolão with rules as its first ruleset and every user enrolled, in one
+// transaction: a failure leaves no active bolão without rules behind to block the next try.
+func (r *BolaoRepository) Create(ctx context.Context, name string, settings models.BolaoSettings, rules *models.ScoringRuleset) (*models.Bolao, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var b models.Bolao
+	query := `INSERT INTO boloes (id, name, tiebreakers, shared_positions, round_weights, drop_worst_rounds, round_ranges, monthly_prize)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + bolaoColumns
+	err = scanBolao(tx.QueryRow(ctx, query, uuid.New(), name, nonNilStrings(settings.Tiebreakers), settings.SharedPositions, nonNilWeights(settings.RoundWeights), settings.DropWorstRounds, nonNilRanges(settings.RoundRanges), settings.MonthlyPrize), &b)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -37,17 +52,26 @@ func (r *BolaoRepository) Create(ctx context.Context, name string) (*models.Bola
 		}
 		return nil, err
 	}
-	return &b, nil
+
+	rules.BolaoID = b.ID
+	if err := insertScoringRuleset(ctx, tx, rules); err != nil {
+		return nil, err
+	}
+
+	enroll := `INSERT INTO bolao_participants (bolao_id, user_id)
+		SELECT $1, id FROM users
+		ON CONFLICT (bolao_id, user_id) DO NOTHING`
+	if _, err := tx.Exec(ctx, enroll, b.ID); err != nil {
+		return nil, err
+	}
+	return &b, tx.Commit(ctx)
 }
 
 func (r *BolaoRepository) GetActive(ctx context.Context) (*models.Bolao, error) {
 	var b models.Bolao
-	query := `SELECT id, name, status, started_at, finished_at, created_at, updated_at
+	query := `SELECT ` + bolaoColumns + `
 		FROM boloes WHERE status = 'active' LIMIT 1`
-	err := r.pool.QueryRow(ctx, query).Scan(
-		&b.ID, &b.Name, &b.Status, &b.StartedAt, &b.FinishedAt, &b.CreatedAt, &b.UpdatedAt,
-	)
-	if err != nil {
+	if err := scanBolao(r.pool.QueryRow(ctx, query), &b); err != nil {
 		return nil, err
 	}
 	return &b, nil
@@ -55,19 +79,16 @@ func (r *BolaoRepository) GetActive(ctx context.Context) (*models.Bolao, error)
 
 func (r *BolaoRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Bolao, error) {
 	var b models.Bolao
-	query := `SELECT id, name, status, started_at, finished_at, created_at, updated_at
+	query := `SELECT ` + bolaoColumns + `
 		FROM boloes WHERE id = $1`
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&b.ID, &b.Name, &b.Status, &b.StartedAt, &b.FinishedAt, &b.CreatedAt, &b.UpdatedAt,
-	)
-	if err != nil {
+	if err := scanBolao(r.pool.QueryRow(ctx, query, id), &b); err != nil {
 		return nil, err
 	}
 	return &b, nil
 }
 
 func (r *BolaoRepository) List(ctx context.Context) ([]models.Bolao, error) {
-	query := `SELECT id, name, status, started_at, finished_at, created_at, updated_at
+	query := `SELECT ` + bolaoColumns + `
 		FROM boloes ORDER BY started_at DESC`
 	rows, err := r.pool.Query(ctx, query)
 	if err != nil {
@@ -78,7 +99,7 @@ func (r *BolaoRepository) List(ctx context.Context) ([]models.Bolao, error) {
 	var boloes []models.Bolao
 	for rows.Next() {
 		var b models.Bolao
-		if err := rows.Scan(&b.ID, &b.Name, &b.Status, &b.StartedAt, &b.FinishedAt, &b.CreatedAt, &b.UpdatedAt); err != nil {
+		if err := scanBolao(rows, &b); err != nil {
 			return nil, err
 		}
 		boloes = append(boloes, b)
@@ -86,6 +107,21 @@ func (r *BolaoRepository) List(ctx context.Context) ([]models.Bolao, error) {
 	return boloes, rows.Err()
 }
 
+// UpdateSettings replaces the bolão's standings settings.
+func (r *BolaoRepository) UpdateSettings(ctx context.Context, id uuid.UUID, settings models.BolaoSettings) error {
+	query := `UPDATE boloes SET tiebreakers = $2, shared_positions = $3, round_weights = $4, drop_worst_rounds = $5, round_ranges = $6, monthly_prize = $7,
+		updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	tag, err := r.pool.Exec(ctx, query, id, nonNilStrings(settings.Tiebreakers), settings.SharedPositions, nonNilWeights(settings.RoundWeights), settings.DropWorstRounds, nonNilRanges(settings.RoundRanges), settings.MonthlyPrize)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
 func (r *BolaoRepository) Finish(ctx context.Context, id uuid.UUID) error {
 	query := `UPDATE boloes SET status = 'finished', finished_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
 		WHERE id = $1 AND status = 'active'`
@@ -99,15 +135,6 @@ func (r *BolaoRepository) Finish(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// EnrollAllUsers adds every existing user as a participant of bolaoID (amount_paid defaults to 0).
-func (r *BolaoRepository) EnrollAllUsers(ctx context.Context, bolaoID uuid.UUID) error {
-	query := `INSERT INTO bolao_participants (bolao_id, user_id)
-		SELECT $1, id FROM users
-		ON CONFLICT (bolao_id, user_id) DO NOTHING`
-	_, err := r.pool.Exec(ctx, query, bolaoID)
-	return err
-}
-
 // AddParticipant enrolls a single user in bolaoID (used when a new user is created mid-season).
 func (r *BolaoRepository) AddParticipant(ctx context.Context, bolaoID, userID uuid.UUID) error {
 	query := `INSERT INTO bolao_participants (bolao_id, user_id)
@@ -151,3 +178,27 @@ func (r *BolaoRepository) UpdateParticipantAmountPaid(ctx context.Context, bolao
 	_, err := r.pool.Exec(ctx, query, bolaoID, userID, amount)
 	return err
 }
+
+// nonNilStrings keeps a nil slice from being written as NULL into a NOT NULL array column.
+func nonNilStrings(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
+
+// nonNilWeights writes a nil list as the empty JSON array rather than null.
+func nonNilWeights(w []models.RoundWeight) []models.RoundWeight {
+	if w == nil {
+		return []models.RoundWeight{}
+	}
+	return w
+}
+
+// nonNilRanges writes a nil list as the empty JSON array rather than null.
+func nonNilRanges(r []models.RoundRange) []models.RoundRange {
+	if r == nil {
+		return []models.RoundRange{}
+	}
+	return r
+}
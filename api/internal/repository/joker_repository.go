@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type JokerRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewJokerRepository(pool *pgxpool.Pool) *JokerRepository {
+	return &JokerRepository{pool: pool}
+}
+
+// Upsert sets the user's coringa for j.Round, replacing any previous pick for that round.
+func (r *JokerRepository) Upsert(ctx context.Context, j *models.Joker) error {
+	query := `
+		INSERT INTO jokers (user_id, bolao_id, round, match_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, bolao_id, round) DO UPDATE SET match_id = $4, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at`
+	return r.pool.QueryRow(ctx, query, j.UserID, j.BolaoID, j.Round, j.MatchID).Scan(&j.UpdatedAt)
+}
+
+func (r *JokerRepository) GetByUserAndRound(ctx context.Context, userID, bolaoID uuid.UUID, round int) (*models.Joker, error) {
+	var j models.Joker
+	query := `SELECT user_id, bolao_id, round, match_id, updated_at
+		FROM jokers WHERE user_id = $1 AND bolao_id = $2 AND round = $3`
+	err := r.pool.QueryRow(ctx, query, userID, bolaoID, round).Scan(&j.UserID, &j.BolaoID, &j.Round, &j.MatchID, &j.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// ListByBolao returns every coringa of a bolão in one query (see ClassificationService).
+func (r *JokerRepository) ListByBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.Joker, error) {
+	query := `SELECT user_id, bolao_id, round, match_id, updated_at
+		FROM jokers WHERE bolao_id = $1`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jokers []models.Joker
+	for rows.Next() {
+		var j models.Joker
+		if err := rows.Scan(&j.UserID, &j.BolaoID, &j.Round, &j.MatchID, &j.UpdatedAt); err != nil {
+			return nil, err
+		}
+		jokers = append(jokers, j)
+	}
+	return jokers, rows.Err()
+}
+
+func (r *JokerRepository) ListByRound(ctx context.Context, bolaoID uuid.UUID, round int) ([]models.Joker, error) {
+	query := `SELECT user_id, bolao_id, round, match_id, updated_at
+		FROM jokers WHERE bolao_id = $1 AND round = $2`
+	rows, err := r.pool.Query(ctx, query, bolaoID, round)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jokers []models.Joker
+	for rows.Next() {
+		var j models.Joker
+		if err := rows.Scan(&j.UserID, &j.BolaoID, &j.Round, &j.MatchID, &j.UpdatedAt); err != nil {
+			return nil, err
+		}
+		jokers = append(jokers, j)
+	}
+	return jokers, rows.Err()
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/bolao-app/api/internal/models"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -17,29 +18,36 @@ func NewMatchRepository(pool *pgxpool.Pool) *MatchRepository {
 	return &MatchRepository{pool: pool}
 }
 
+const matchColumns = `id, bolao_id, round, home_team, away_team, market_closes_at, home_goals, away_goals,
+	knockout, extra_time_home_goals, extra_time_away_goals, penalty_winner, created_at, updated_at`
+
+func scanMatch(row pgx.Row, m *models.Match) error {
+	return row.Scan(
+		&m.ID, &m.BolaoID, &m.Round, &m.HomeTeam, &m.AwayTeam, &m.MarketClosesAt, &m.HomeGoals, &m.AwayGoals,
+		&m.Knockout, &m.ExtraTimeHomeGoals, &m.ExtraTimeAwayGoals, &m.PenaltyWinner, &m.CreatedAt, &m.UpdatedAt,
+	)
+}
+
 func (r *MatchRepository) Create(ctx context.Context, m *models.Match) error {
 	query := `
-		INSERT INTO matches (id, bolao_id, round, home_team, away_team, market_closes_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO matches (id, bolao_id, round, home_team, away_team, market_closes_at, knockout)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING created_at, updated_at`
-	return r.pool.QueryRow(ctx, query, m.ID, m.BolaoID, m.Round, m.HomeTeam, m.AwayTeam, m.MarketClosesAt).Scan(&m.CreatedAt, &m.UpdatedAt)
+	return r.pool.QueryRow(ctx, query, m.ID, m.BolaoID, m.Round, m.HomeTeam, m.AwayTeam, m.MarketClosesAt, m.Knockout).Scan(&m.CreatedAt, &m.UpdatedAt)
 }
 
 func (r *MatchRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.Match, error) {
 	var m models.Match
-	query := `SELECT id, bolao_id, round, home_team, away_team, market_closes_at, home_goals, away_goals, created_at, updated_at
+	query := `SELECT ` + matchColumns + `
 		FROM matches WHERE id = $1`
-	err := r.pool.QueryRow(ctx, query, id).Scan(
-		&m.ID, &m.BolaoID, &m.Round, &m.HomeTeam, &m.AwayTeam, &m.MarketClosesAt, &m.HomeGoals, &m.AwayGoals, &m.CreatedAt, &m.UpdatedAt,
-	)
-	if err != nil {
+	if err := scanMatch(r.pool.QueryRow(ctx, query, id), &m); err != nil {
 		return nil, err
 	}
 	return &m, nil
 }
 
 func (r *MatchRepository) ListByRound(ctx context.Context, bolaoID uuid.UUID, round int) ([]models.Match, error) {
-	query := `SELECT id, bolao_id, round, home_team, away_team, market_closes_at, home_goals, away_goals, created_at, updated_at
+	query := `SELECT ` + matchColumns + `
 		FROM matches WHERE bolao_id = $1 AND round = $2 ORDER BY created_at`
 	rows, err := r.pool.Query(ctx, query, bolaoID, round)
 	if err != nil {
@@ -50,7 +58,7 @@ func (r *MatchRepository) ListByRound(ctx context.Context, bolaoID uuid.UUID, ro
 	var matches []models.Match
 	for rows.Next() {
 		var m models.Match
-		if err := rows.Scan(&m.ID, &m.BolaoID, &m.Round, &m.HomeTeam, &m.AwayTeam, &m.MarketClosesAt, &m.HomeGoals, &m.AwayGoals, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		if err := scanMatch(rows, &m); err != nil {
 			return nil, err
 		}
 		matches = append(matches, m)
@@ -61,7 +69,7 @@ func (r *MatchRepository) ListByRound(ctx context.Context, bolaoID uuid.UUID, ro
 // ListAllByBolao returns every match for a bolão in one query, for callers that need
 // to group by round in memory instead of issuing one query per round (see ClassificationService).
 func (r *MatchRepository) ListAllByBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.Match, error) {
-	query := `SELECT id, bolao_id, round, home_team, away_team, market_closes_at, home_goals, away_goals, created_at, updated_at
+	query := `SELECT ` + matchColumns + `
 		FROM matches WHERE bolao_id = $1 ORDER BY round, created_at`
 	rows, err := r.pool.Query(ctx, query, bolaoID)
 	if err != nil {
@@ -72,7 +80,7 @@ func (r *MatchRepository) ListAllByBolao(ctx context.Context, bolaoID uuid.UUID)
 	var matches []models.Match
 	for rows.Next() {
 		var m models.Match
-		if err := rows.Scan(&m.ID, &m.BolaoID, &m.Round, &m.HomeTeam, &m.AwayTeam, &m.MarketClosesAt, &m.HomeGoals, &m.AwayGoals, &m.CreatedAt, &m.UpdatedAt); err != nil {
+		if err := scanMatch(rows, &m); err != nil {
 			return nil, err
 		}
 		matches = append(matches, m)
@@ -99,9 +107,13 @@ func (r *MatchRepository) ListRounds(ctx context.Context, bolaoID uuid.UUID) ([]
 	return rounds, rows.Err()
 }
 
-func (r *MatchRepository) UpdateResults(ctx context.Context, id uuid.UUID, homeGoals, awayGoals int) error {
-	query := `UPDATE matches SET home_goals = $2, away_goals = $3, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
-	_, err := r.pool.Exec(ctx, query, id, homeGoals, awayGoals)
+// UpdateResults stores the final result. The extra-time score and the penalty winner are
+// replaced too, so correcting a knockout result can clear them.
+func (r *MatchRepository) UpdateResults(ctx context.Context, id uuid.UUID, res models.MatchResult) error {
+	query := `UPDATE matches SET home_goals = $2, away_goals = $3,
+		extra_time_home_goals = $4, extra_time_away_goals = $5, penalty_winner = $6, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1`
+	_, err := r.pool.Exec(ctx, query, id, res.HomeGoals, res.AwayGoals, res.ExtraTimeHomeGoals, res.ExtraTimeAwayGoals, res.PenaltyWinner)
 	return err
 }
 
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutcomeShareRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutcomeShareRepository(pool *pgxpool.Pool) *OutcomeShareRepository {
+	return &OutcomeShareRepository{pool: pool}
+}
+
+// Freeze stores a match's split unless one is already stored: the first split frozen
+// after the market closes is the one that counts.
+func (r *OutcomeShareRepository) Freeze(ctx context.Context, s *models.OutcomeShares) error {
+	query := `
+		INSERT INTO match_outcome_shares (match_id, home, draw, away)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (match_id) DO NOTHING
+		RETURNING frozen_at`
+	err := r.pool.QueryRow(ctx, query, s.MatchID, s.Home, s.Draw, s.Away).Scan(&s.FrozenAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Frozen concurrently; read back the stored split.
+		query = `SELECT home, draw, away, frozen_at FROM match_outcome_shares WHERE match_id = $1`
+		return r.pool.QueryRow(ctx, query, s.MatchID).Scan(&s.Home, &s.Draw, &s.Away, &s.FrozenAt)
+	}
+	return err
+}
+
+// ListByBolao returns every frozen split of a bolão, keyed by match.
+func (r *OutcomeShareRepository) ListByBolao(ctx context.Context, bolaoID uuid.UUID) (map[uuid.UUID]models.OutcomeShares, error) {
+	query := `SELECT s.match_id, s.home, s.draw, s.away, s.frozen_at
+		FROM match_outcome_shares s
+		JOIN matches m ON s.match_id = m.id
+		WHERE m.bolao_id = $1`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID]models.OutcomeShares)
+	for rows.Next() {
+		var s models.OutcomeShares
+		if err := rows.Scan(&s.MatchID, &s.Home, &s.Draw, &s.Away, &s.FrozenAt); err != nil {
+			return nil, err
+		}
+		result[s.MatchID] = s
+	}
+	return result, rows.Err()
+}
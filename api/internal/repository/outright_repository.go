@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type OutrightRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewOutrightRepository(pool *pgxpool.Pool) *OutrightRepository {
+	return &OutrightRepository{pool: pool}
+}
+
+func (r *OutrightRepository) CreateQuestion(ctx context.Context, q *models.OutrightQuestion) error {
+	query := `
+		INSERT INTO outright_questions (id, bolao_id, title, picks, points_per_hit, closes_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at`
+	return r.pool.QueryRow(ctx, query, q.ID, q.BolaoID, q.Title, q.Picks, q.PointsPerHit, q.ClosesAt).Scan(&q.CreatedAt, &q.UpdatedAt)
+}
+
+func (r *OutrightRepository) GetQuestion(ctx context.Context, id uuid.UUID) (*models.OutrightQuestion, error) {
+	var q models.OutrightQuestion
+	query := `SELECT id, bolao_id, title, picks, points_per_hit, closes_at, outcome, created_at, updated_at
+		FROM outright_questions WHERE id = $1`
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&q.ID, &q.BolaoID, &q.Title, &q.Picks, &q.PointsPerHit, &q.ClosesAt, &q.Outcome, &q.CreatedAt, &q.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *OutrightRepository) ListQuestions(ctx context.Context, bolaoID uuid.UUID) ([]models.OutrightQuestion, error) {
+	query := `SELECT id, bolao_id, title, picks, points_per_hit, closes_at, outcome, created_at, updated_at
+		FROM outright_questions WHERE bolao_id = $1 ORDER BY closes_at, created_at`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []models.OutrightQuestion
+	for rows.Next() {
+		var q models.OutrightQuestion
+		if err := rows.Scan(&q.ID, &q.BolaoID, &q.Title, &q.Picks, &q.PointsPerHit, &q.ClosesAt, &q.Outcome, &q.CreatedAt, &q.UpdatedAt); err != nil {
+			return nil, err
+		}
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+// SetOutcome resolves a question. A nil outcome reopens it as unresolved.
+func (r *OutrightRepository) SetOutcome(ctx context.Context, id uuid.UUID, outcome []string) error {
+	query := `UPDATE outright_questions SET outcome = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	tag, err := r.pool.Exec(ctx, query, id, outcome)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *OutrightRepository) DeleteQuestion(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM outright_questions WHERE id = $1`, id)
+	return err
+}
+
+func (r *OutrightRepository) UpsertAnswer(ctx context.Context, a *models.OutrightAnswer) error {
+	query := `
+		INSERT INTO outright_answers (question_id, user_id, teams)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (question_id, user_id) DO UPDATE SET teams = $3, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at`
+	return r.pool.QueryRow(ctx, query, a.QuestionID, a.UserID, a.Teams).Scan(&a.UpdatedAt)
+}
+
+func (r *OutrightRepository) ListAnswersByQuestion(ctx context.Context, questionID uuid.UUID) ([]models.OutrightAnswer, error) {
+	query := `SELECT question_id, user_id, teams, updated_at
+		FROM outright_answers WHERE question_id = $1`
+	return r.listAnswers(ctx, query, questionID)
+}
+
+// ListAnswersByBolao returns every answer to every question of a bolão in one query.
+func (r *OutrightRepository) ListAnswersByBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.OutrightAnswer, error) {
+	query := `SELECT a.question_id, a.user_id, a.teams, a.updated_at
+		FROM outright_answers a
+		JOIN outright_questions q ON a.question_id = q.id
+		WHERE q.bolao_id = $1`
+	return r.listAnswers(ctx, query, bolaoID)
+}
+
+func (r *OutrightRepository) ListAnswersByUser(ctx context.Context, bolaoID, userID uuid.UUID) ([]models.OutrightAnswer, error) {
+	query := `SELECT a.question_id, a.user_id, a.teams, a.updated_at
+		FROM outright_answers a
+		JOIN outright_questions q ON a.question_id = q.id
+		WHERE q.bolao_id = $1 AND a.user_id = $2`
+	return r.listAnswers(ctx, query, bolaoID, userID)
+}
+
+func (r *OutrightRepository) listAnswers(ctx context.Context, query string, args ...any) ([]models.OutrightAnswer, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var answers []models.OutrightAnswer
+	for rows.Next() {
+		var a models.OutrightAnswer
+		if err := rows.Scan(&a.QuestionID, &a.UserID, &a.Teams, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+	return answers, rows.Err()
+}
@@ -65,3 +65,26 @@ func (r *PartialRepository) ListByRound(ctx context.Context, bolaoID uuid.UUID,
 	}
 	return result, rows.Err()
 }
+
+// ListByBolao returns every parcial of the bolão, keyed by match.
+func (r *PartialRepository) ListByBolao(ctx context.Context, bolaoID uuid.UUID) (map[uuid.UUID]models.MatchPartial, error) {
+	query := `SELECT p.match_id, p.home_goals, p.away_goals, p.updated_by, p.updated_at
+		FROM match_partials p
+		JOIN matches m ON p.match_id = m.id
+		WHERE m.bolao_id = $1`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[uuid.UUID]models.MatchPartial)
+	for rows.Next() {
+		var p models.MatchPartial
+		if err := rows.Scan(&p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.UpdatedBy, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		result[p.MatchID] = p
+	}
+	return result, rows.Err()
+}
@@ -18,18 +18,18 @@ func NewPredictionRepository(pool *pgxpool.Pool) *PredictionRepository {
 
 func (r *PredictionRepository) Upsert(ctx context.Context, p *models.Prediction) error {
 	query := `
-		INSERT INTO predictions (id, user_id, match_id, home_goals, away_goals)
-		VALUES ($1, $2, $3, $4, $5)
-		ON CONFLICT (user_id, match_id) DO UPDATE SET home_goals = $4, away_goals = $5, updated_at = CURRENT_TIMESTAMP
+		INSERT INTO predictions (id, user_id, match_id, home_goals, away_goals, advances)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, match_id) DO UPDATE SET home_goals = $4, away_goals = $5, advances = $6, updated_at = CURRENT_TIMESTAMP
 		RETURNING created_at, updated_at`
-	return r.pool.QueryRow(ctx, query, p.ID, p.UserID, p.MatchID, p.HomeGoals, p.AwayGoals).Scan(&p.CreatedAt, &p.UpdatedAt)
+	return r.pool.QueryRow(ctx, query, p.ID, p.UserID, p.MatchID, p.HomeGoals, p.AwayGoals, p.Advances).Scan(&p.CreatedAt, &p.UpdatedAt)
 }
 
 func (r *PredictionRepository) GetByUserAndMatch(ctx context.Context, userID, matchID uuid.UUID) (*models.Prediction, error) {
 	var p models.Prediction
-	query := `SELECT id, user_id, match_id, home_goals, away_goals, created_at, updated_at
+	query := `SELECT id, user_id, match_id, home_goals, away_goals, advances, created_at, updated_at
 		FROM predictions WHERE user_id = $1 AND match_id = $2`
-	err := r.pool.QueryRow(ctx, query, userID, matchID).Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.CreatedAt, &p.UpdatedAt)
+	err := r.pool.QueryRow(ctx, query, userID, matchID).Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.Advances, &p.CreatedAt, &p.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +37,7 @@ func (r *PredictionRepository) GetByUserAndMatch(ctx context.Context, userID, ma
 }
 
 func (r *PredictionRepository) GetByUserAndRound(ctx context.Context, userID, bolaoID uuid.UUID, round int) ([]models.Prediction, error) {
-	query := `SELECT p.id, p.user_id, p.match_id, p.home_goals, p.away_goals, p.created_at, p.updated_at
+	query := `SELECT p.id, p.user_id, p.match_id, p.home_goals, p.away_goals, p.advances, p.created_at, p.updated_at
 		FROM predictions p
 		JOIN matches m ON p.match_id = m.id
 		WHERE p.user_id = $1 AND m.bolao_id = $2 AND m.round = $3`
@@ -50,7 +50,7 @@ func (r *PredictionRepository) GetByUserAndRound(ctx context.Context, userID, bo
 	var predictions []models.Prediction
 	for rows.Next() {
 		var p models.Prediction
-		if err := rows.Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.Advances, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, err
 		}
 		predictions = append(predictions, p)
@@ -59,7 +59,7 @@ func (r *PredictionRepository) GetByUserAndRound(ctx context.Context, userID, bo
 }
 
 func (r *PredictionRepository) GetByMatch(ctx context.Context, matchID uuid.UUID) ([]models.Prediction, error) {
-	query := `SELECT id, user_id, match_id, home_goals, away_goals, created_at, updated_at
+	query := `SELECT id, user_id, match_id, home_goals, away_goals, advances, created_at, updated_at
 		FROM predictions WHERE match_id = $1`
 	rows, err := r.pool.Query(ctx, query, matchID)
 	if err != nil {
@@ -70,7 +70,7 @@ func (r *PredictionRepository) GetByMatch(ctx context.Context, matchID uuid.UUID
 	var predictions []models.Prediction
 	for rows.Next() {
 		var p models.Prediction
-		if err := rows.Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.Advances, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, err
 		}
 		predictions = append(predictions, p)
@@ -81,7 +81,7 @@ func (r *PredictionRepository) GetByMatch(ctx context.Context, matchID uuid.UUID
 // GetAllForBolao returns every prediction across every round of a bolão in one query,
 // for callers that need to group by round/user in memory (see ClassificationService).
 func (r *PredictionRepository) GetAllForBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.Prediction, error) {
-	query := `SELECT p.id, p.user_id, p.match_id, p.home_goals, p.away_goals, p.created_at, p.updated_at
+	query := `SELECT p.id, p.user_id, p.match_id, p.home_goals, p.away_goals, p.advances, p.created_at, p.updated_at
 		FROM predictions p
 		JOIN matches m ON p.match_id = m.id
 		WHERE m.bolao_id = $1`
@@ -94,7 +94,31 @@ func (r *PredictionRepository) GetAllForBolao(ctx context.Context, bolaoID uuid.
 	var predictions []models.Prediction
 	for rows.Next() {
 		var p models.Prediction
-		if err := rows.Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.Advances, &p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		predictions = append(predictions, p)
+	}
+	return predictions, rows.Err()
+}
+
+// GetFirstLegsForBolao returns the predictions on the first legs of the bolão's ties,
+// which scoring a second leg needs whatever round it is in.
+func (r *PredictionRepository) GetFirstLegsForBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.Prediction, error) {
+	query := `SELECT p.id, p.user_id, p.match_id, p.home_goals, p.away_goals, p.advances, p.created_at, p.updated_at
+		FROM predictions p
+		JOIN ties t ON p.match_id = t.first_leg_id
+		WHERE t.bolao_id = $1`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var predictions []models.Prediction
+	for rows.Next() {
+		var p models.Prediction
+		if err := rows.Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.Advances, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, err
 		}
 		predictions = append(predictions, p)
@@ -103,7 +127,7 @@ func (r *PredictionRepository) GetAllForBolao(ctx context.Context, bolaoID uuid.
 }
 
 func (r *PredictionRepository) GetAllPredictionsForRound(ctx context.Context, bolaoID uuid.UUID, round int) ([]models.Prediction, error) {
-	query := `SELECT p.id, p.user_id, p.match_id, p.home_goals, p.away_goals, p.created_at, p.updated_at
+	query := `SELECT p.id, p.user_id, p.match_id, p.home_goals, p.away_goals, p.advances, p.created_at, p.updated_at
 		FROM predictions p
 		JOIN matches m ON p.match_id = m.id
 		WHERE m.bolao_id = $1 AND m.round = $2`
@@ -116,7 +140,7 @@ func (r *PredictionRepository) GetAllPredictionsForRound(ctx context.Context, bo
 	var predictions []models.Prediction
 	for rows.Next() {
 		var p models.Prediction
-		if err := rows.Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.CreatedAt, &p.UpdatedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.UserID, &p.MatchID, &p.HomeGoals, &p.AwayGoals, &p.Advances, &p.CreatedAt, &p.UpdatedAt); err != nil {
 			return nil, err
 		}
 		predictions = append(predictions, p)
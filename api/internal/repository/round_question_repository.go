@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RoundQuestionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRoundQuestionRepository(pool *pgxpool.Pool) *RoundQuestionRepository {
+	return &RoundQuestionRepository{pool: pool}
+}
+
+const roundQuestionColumns = `id, bolao_id, round, prompt, kind, options, points, result, created_at, updated_at`
+
+func (r *RoundQuestionRepository) Create(ctx context.Context, q *models.RoundQuestion) error {
+	query := `
+		INSERT INTO round_questions (id, bolao_id, round, prompt, kind, options, points)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at, updated_at`
+	return r.pool.QueryRow(ctx, query, q.ID, q.BolaoID, q.Round, q.Prompt, q.Kind, q.Options, q.Points).Scan(&q.CreatedAt, &q.UpdatedAt)
+}
+
+func (r *RoundQuestionRepository) GetByID(ctx context.Context, id uuid.UUID) (*models.RoundQuestion, error) {
+	var q models.RoundQuestion
+	query := `SELECT ` + roundQuestionColumns + ` FROM round_questions WHERE id = $1`
+	err := r.pool.QueryRow(ctx, query, id).Scan(
+		&q.ID, &q.BolaoID, &q.Round, &q.Prompt, &q.Kind, &q.Options, &q.Points, &q.Result, &q.CreatedAt, &q.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &q, nil
+}
+
+func (r *RoundQuestionRepository) ListByRound(ctx context.Context, bolaoID uuid.UUID, round int) ([]models.RoundQuestion, error) {
+	query := `SELECT ` + roundQuestionColumns + ` FROM round_questions
+		WHERE bolao_id = $1 AND round = $2 ORDER BY created_at`
+	return r.list(ctx, query, bolaoID, round)
+}
+
+// ListByBolao returns every round question of a bolão in one query.
+func (r *RoundQuestionRepository) ListByBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.RoundQuestion, error) {
+	query := `SELECT ` + roundQuestionColumns + ` FROM round_questions
+		WHERE bolao_id = $1 ORDER BY round, created_at`
+	return r.list(ctx, query, bolaoID)
+}
+
+func (r *RoundQuestionRepository) list(ctx context.Context, query string, args ...any) ([]models.RoundQuestion, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var questions []models.RoundQuestion
+	for rows.Next() {
+		var q models.RoundQuestion
+		if err := rows.Scan(&q.ID, &q.BolaoID, &q.Round, &q.Prompt, &q.Kind, &q.Options, &q.Points, &q.Result, &q.CreatedAt, &q.UpdatedAt); err != nil {
+			return nil, err
+		}
+		questions = append(questions, q)
+	}
+	return questions, rows.Err()
+}
+
+// SetResult records the correct answer. A nil result clears it back to unresolved.
+func (r *RoundQuestionRepository) SetResult(ctx context.Context, id uuid.UUID, result *string) error {
+	query := `UPDATE round_questions SET result = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+	tag, err := r.pool.Exec(ctx, query, id, result)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *RoundQuestionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `DELETE FROM round_questions WHERE id = $1`, id)
+	return err
+}
+
+func (r *RoundQuestionRepository) UpsertAnswer(ctx context.Context, a *models.RoundQuestionAnswer) error {
+	query := `
+		INSERT INTO round_question_answers (question_id, user_id, answer)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (question_id, user_id) DO UPDATE SET answer = $3, updated_at = CURRENT_TIMESTAMP
+		RETURNING updated_at`
+	return r.pool.QueryRow(ctx, query, a.QuestionID, a.UserID, a.Answer).Scan(&a.UpdatedAt)
+}
+
+func (r *RoundQuestionRepository) ListAnswersByRound(ctx context.Context, bolaoID uuid.UUID, round int) ([]models.RoundQuestionAnswer, error) {
+	query := `SELECT a.question_id, a.user_id, a.answer, a.updated_at
+		FROM round_question_answers a
+		JOIN round_questions q ON a.question_id = q.id
+		WHERE q.bolao_id = $1 AND q.round = $2`
+	return r.listAnswers(ctx, query, bolaoID, round)
+}
+
+func (r *RoundQuestionRepository) ListAnswersByBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.RoundQuestionAnswer, error) {
+	query := `SELECT a.question_id, a.user_id, a.answer, a.updated_at
+		FROM round_question_answers a
+		JOIN round_questions q ON a.question_id = q.id
+		WHERE q.bolao_id = $1`
+	return r.listAnswers(ctx, query, bolaoID)
+}
+
+func (r *RoundQuestionRepository) listAnswers(ctx context.Context, query string, args ...any) ([]models.RoundQuestionAnswer, error) {
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var answers []models.RoundQuestionAnswer
+	for rows.Next() {
+		var a models.RoundQuestionAnswer
+		if err := rows.Scan(&a.QuestionID, &a.UserID, &a.Answer, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		answers = append(answers, a)
+	}
+	return answers, rows.Err()
+}
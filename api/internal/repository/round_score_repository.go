@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RoundScoreRepository stores the materialized per-round scores. Writers invalidate a
+// round by bumping its generation after their change; a recompute only stores its rows if
+// the generation it started from is still current, so a score computed from data older
+// than the last change is never marked fresh.
+type RoundScoreRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewRoundScoreRepository(pool *pgxpool.Pool) *RoundScoreRepository {
+	return &RoundScoreRepository{pool: pool}
+}
+
+// AffectedRounds returns rounds plus the rounds of the second legs whose first leg is in
+// one of them, whose scores depend on the first leg too.
+func (r *RoundScoreRepository) AffectedRounds(ctx context.Context, bolaoID uuid.UUID, rounds []int) ([]int, error) {
+	query := `
+		SELECT unnest($2::int[])
+		UNION
+		SELECT second_leg.round FROM ties t
+		JOIN matches first_leg ON first_leg.id = t.first_leg_id
+		JOIN matches second_leg ON second_leg.id = t.second_leg_id
+		WHERE t.bolao_id = $1 AND first_leg.round = ANY($2)`
+	rows, err := r.pool.Query(ctx, query, bolaoID, rounds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var affected []int
+	for rows.Next() {
+		var round int
+		if err := rows.Scan(&round); err != nil {
+			return nil, err
+		}
+		affected = append(affected, round)
+	}
+	return affected, rows.Err()
+}
+
+// Invalidate marks the rounds stale and returns their new generation by round.
+func (r *RoundScoreRepository) Invalidate(ctx context.Context, bolaoID uuid.UUID, rounds []int) (map[int]int64, error) {
+	query := `
+		INSERT INTO round_score_rounds (bolao_id, round, generation)
+		SELECT $1, round, 1 FROM (SELECT DISTINCT unnest($2::int[]) AS round) affected
+		ON CONFLICT (bolao_id, round) DO UPDATE SET generation = round_score_rounds.generation + 1
+		RETURNING round, generation`
+	rows, err := r.pool.Query(ctx, query, bolaoID, rounds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	generations := make(map[int]int64, len(rounds))
+	for rows.Next() {
+		var round int
+		var generation int64
+		if err := rows.Scan(&round, &generation); err != nil {
+			return nil, err
+		}
+		generations[round] = generation
+	}
+	return generations, rows.Err()
+}
+
+// InvalidateFrom marks every computed round from fromRound on stale. A round never
+// computed is stale already.
+func (r *RoundScoreRepository) InvalidateFrom(ctx context.Context, bolaoID uuid.UUID, fromRound int) error {
+	query := `UPDATE round_score_rounds SET generation = generation + 1 WHERE bolao_id = $1 AND round >= $2`
+	_, err := r.pool.Exec(ctx, query, bolaoID, fromRound)
+	return err
+}
+
+func (r *RoundScoreRepository) ListStates(ctx context.Context, bolaoID uuid.UUID) ([]models.RoundScoreState, error) {
+	query := `SELECT round, generation, computed_generation, valid_until FROM round_score_rounds WHERE bolao_id = $1 ORDER BY round`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var states []models.RoundScoreState
+	for rows.Next() {
+		var s models.RoundScoreState
+		if err := rows.Scan(&s.Round, &s.Generation, &s.ComputedGeneration, &s.ValidUntil); err != nil {
+			return nil, err
+		}
+		states = append(states, s)
+	}
+	return states, rows.Err()
+}
+
+const roundScoreColumns = `user_id, round, points, exact_scores, correct_results, missed, round_total_hits, first_submission`
+
+func (r *RoundScoreRepository) ListByBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.RoundScoreRow, error) {
+	query := `SELECT ` + roundScoreColumns + ` FROM round_scores WHERE bolao_id = $1 ORDER BY round`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var scores []models.RoundScoreRow
+	for rows.Next() {
+		var s models.RoundScoreRow
+		if err := rows.Scan(&s.UserID, &s.Round, &s.Points, &s.ExactScores, &s.CorrectResults, &s.Missed, &s.RoundTotalHits, &s.FirstSubmission); err != nil {
+			return nil, err
+		}
+		scores = append(scores, s)
+	}
+	return scores, rows.Err()
+}
+
+// Replace stores rows as the round's scores, computed from data read after the round was
+// at generation, and valid until validUntil (nil for no expiry). It reports false, storing
+// nothing, when the round was invalidated again in the meantime.
+func (r *RoundScoreRepository) Replace(ctx context.Context, bolaoID uuid.UUID, round int, generation int64, validUntil *time.Time, rows []models.RoundScoreRow) (bool, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	ensure := `INSERT INTO round_score_rounds (bolao_id, round) VALUES ($1, $2) ON CONFLICT (bolao_id, round) DO NOTHING`
+	if _, err := tx.Exec(ctx, ensure, bolaoID, round); err != nil {
+		return false, err
+	}
+	var current int64
+	lock := `SELECT generation FROM round_score_rounds WHERE bolao_id = $1 AND round = $2 FOR UPDATE`
+	if err := tx.QueryRow(ctx, lock, bolaoID, round).Scan(&current); err != nil {
+		return false, err
+	}
+	if current != generation {
+		return false, nil
+	}
+
+	batch := &pgx.Batch{}
+	batch.Queue(`DELETE FROM round_scores WHERE bolao_id = $1 AND round = $2`, bolaoID, round)
+	for _, s := range rows {
+		batch.Queue(`INSERT INTO round_scores (bolao_id, `+roundScoreColumns+`) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+			bolaoID, s.UserID, round, s.Points, s.ExactScores, s.CorrectResults, s.Missed, s.RoundTotalHits, s.FirstSubmission)
+	}
+	batch.Queue(`
+		UPDATE round_score_rounds SET computed_generation = $3, valid_until = $4, computed_at = CURRENT_TIMESTAMP
+		WHERE bolao_id = $1 AND round = $2`, bolaoID, round, generation, validUntil)
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return false, err
+	}
+	return true, tx.Commit(ctx)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ScoringRulesetRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewScoringRulesetRepository(pool *pgxpool.Pool) *ScoringRulesetRepository {
+	return &ScoringRulesetRepository{pool: pool}
+}
+
+const scoringRulesetColumns = `id, bolao_id, version, effective_from_round, engine,
+	correct_result, correct_draw, correct_home_goals, correct_away_goals,
+	exact_score, exact_score_high, total_goals_high, high_scoring_goals, round_total_goals, score_type_bonus,
+	joker_multiplier, underdog_multiplier, favorite_team_exact_bonus, favorite_team_result_bonus, qualifier_points, created_at`
+
+func scanScoringRuleset(row pgx.Row, rs *models.ScoringRuleset) error {
+	return row.Scan(
+		&rs.ID, &rs.BolaoID, &rs.Version, &rs.EffectiveFromRound, &rs.Engine,
+		&rs.CorrectResult, &rs.CorrectDraw, &rs.CorrectHomeGoals, &rs.CorrectAwayGoals,
+		&rs.ExactScore, &rs.ExactScoreHigh, &rs.TotalGoalsHigh, &rs.HighScoringGoals, &rs.RoundTotalGoals, &rs.ScoreTypeBonus,
+		&rs.JokerMultiplier, &rs.UnderdogMultiplier, &rs.FavoriteTeamExactBonus, &rs.FavoriteTeamResultBonus, &rs.QualifierPoints, &rs.CreatedAt,
+	)
+}
+
+// queryRower is a pool or a transaction, for the inserts BolaoRepository.Create also runs
+// inside its own.
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+func (r *ScoringRulesetRepository) Create(ctx context.Context, rs *models.ScoringRuleset) error {
+	return insertScoringRuleset(ctx, r.pool, rs)
+}
+
+func insertScoringRuleset(ctx context.Context, db queryRower, rs *models.ScoringRuleset) error {
+	query := `
+		INSERT INTO scoring_rulesets (id, bolao_id, version, effective_from_round, engine,
+			correct_result, correct_draw, correct_home_goals, correct_away_goals,
+			exact_score, exact_score_high, total_goals_high, high_scoring_goals, round_total_goals, score_type_bonus,
+			joker_multiplier, underdog_multiplier, favorite_team_exact_bonus, favorite_team_result_bonus, qualifier_points)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+		RETURNING created_at`
+	return db.QueryRow(ctx, query,
+		rs.ID, rs.BolaoID, rs.Version, rs.EffectiveFromRound, rs.Engine,
+		rs.CorrectResult, rs.CorrectDraw, rs.CorrectHomeGoals, rs.CorrectAwayGoals,
+		rs.ExactScore, rs.ExactScoreHigh, rs.TotalGoalsHigh, rs.HighScoringGoals, rs.RoundTotalGoals, rs.ScoreTypeBonus,
+		rs.JokerMultiplier, rs.UnderdogMultiplier, rs.FavoriteTeamExactBonus, rs.FavoriteTeamResultBonus, rs.QualifierPoints,
+	).Scan(&rs.CreatedAt)
+}
+
+// ListByBolao returns every rule version of a bolão, oldest first.
+func (r *ScoringRulesetRepository) ListByBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.ScoringRuleset, error) {
+	query := `SELECT ` + scoringRulesetColumns + `
+		FROM scoring_rulesets WHERE bolao_id = $1 ORDER BY effective_from_round`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var versions []models.ScoringRuleset
+	for rows.Next() {
+		var rs models.ScoringRuleset
+		if err := scanScoringRuleset(rows, &rs); err != nil {
+			return nil, err
+		}
+		versions = append(versions, rs)
+	}
+	return versions, rows.Err()
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TieRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewTieRepository(pool *pgxpool.Pool) *TieRepository {
+	return &TieRepository{pool: pool}
+}
+
+const tieColumns = `id, bolao_id, first_leg_id, second_leg_id, away_goals, created_at`
+
+// Create links the two legs and turns the second one into a knockout match in the same
+// statement, so a tie never exists with a second leg that can't take extra time.
+func (r *TieRepository) Create(ctx context.Context, t *models.Tie) error {
+	query := `
+		WITH tie AS (
+			INSERT INTO ties (id, bolao_id, first_leg_id, second_leg_id, away_goals)
+			VALUES ($1, $2, $3, $4, $5)
+			RETURNING created_at
+		), second_leg AS (
+			UPDATE matches SET knockout = TRUE, updated_at = CURRENT_TIMESTAMP WHERE id = $4
+		)
+		SELECT created_at FROM tie`
+	return r.pool.QueryRow(ctx, query, t.ID, t.BolaoID, t.FirstLegID, t.SecondLegID, t.AwayGoals).Scan(&t.CreatedAt)
+}
+
+// GetByMatch returns the tie either leg of which is matchID, pgx.ErrNoRows when none.
+func (r *TieRepository) GetByMatch(ctx context.Context, matchID uuid.UUID) (*models.Tie, error) {
+	var t models.Tie
+	query := `SELECT ` + tieColumns + ` FROM ties WHERE first_leg_id = $1 OR second_leg_id = $1`
+	err := r.pool.QueryRow(ctx, query, matchID).Scan(&t.ID, &t.BolaoID, &t.FirstLegID, &t.SecondLegID, &t.AwayGoals, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *TieRepository) ListByBolao(ctx context.Context, bolaoID uuid.UUID) ([]models.Tie, error) {
+	query := `SELECT ` + tieColumns + ` FROM ties WHERE bolao_id = $1 ORDER BY created_at`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ties []models.Tie
+	for rows.Next() {
+		var t models.Tie
+		if err := rows.Scan(&t.ID, &t.BolaoID, &t.FirstLegID, &t.SecondLegID, &t.AwayGoals, &t.CreatedAt); err != nil {
+			return nil, err
+		}
+		ties = append(ties, t)
+	}
+	return ties, rows.Err()
+}
@@ -16,11 +16,18 @@ func NewUserRepository(pool *pgxpool.Pool) *UserRepository {
 	return &UserRepository{pool: pool}
 }
 
+// Create inserts the user and starts their favorite-team history in the same statement.
 func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
-		INSERT INTO users (id, username, display_name, favorite_team, is_admin, password_hash, must_change_password)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING created_at, updated_at`
+		WITH u AS (
+			INSERT INTO users (id, username, display_name, favorite_team, is_admin, password_hash, must_change_password)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			RETURNING id, favorite_team, created_at, updated_at
+		), history AS (
+			INSERT INTO favorite_team_changes (user_id, team, changed_at)
+			SELECT id, favorite_team, created_at FROM u
+		)
+		SELECT created_at, updated_at FROM u`
 	return r.pool.QueryRow(ctx, query,
 		user.ID, user.Username, user.DisplayName, user.FavoriteTeam, user.IsAdmin, user.PasswordHash, user.MustChangePassword,
 	).Scan(&user.CreatedAt, &user.UpdatedAt)
@@ -78,10 +85,21 @@ func (r *UserRepository) UpdatePassword(ctx context.Context, userID uuid.UUID, p
 	return err
 }
 
+// Update saves the user's profile. A change of favorite team is appended to its history in
+// the same statement; the old row is read from the statement's snapshot, before the update.
 func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
 	query := `
-		UPDATE users SET username = $2, display_name = $3, favorite_team = $4, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1 RETURNING updated_at`
+		WITH old AS (
+			SELECT favorite_team FROM users WHERE id = $1
+		), u AS (
+			UPDATE users SET username = $2, display_name = $3, favorite_team = $4, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 RETURNING id, favorite_team, updated_at
+		), history AS (
+			INSERT INTO favorite_team_changes (user_id, team, changed_at)
+			SELECT u.id, u.favorite_team, u.updated_at FROM u, old
+			WHERE u.favorite_team IS DISTINCT FROM old.favorite_team
+		)
+		SELECT updated_at FROM u`
 	return r.pool.QueryRow(ctx, query, user.ID, user.Username, user.DisplayName, user.FavoriteTeam).Scan(&user.UpdatedAt)
 }
 
@@ -97,3 +115,28 @@ func (r *UserRepository) GetByUsernameExcluding(ctx context.Context, username st
 	}
 	return &u, nil
 }
+
+// ListFavoriteTeamChanges returns the favorite-team history of the bolão's participants,
+// oldest first.
+func (r *UserRepository) ListFavoriteTeamChanges(ctx context.Context, bolaoID uuid.UUID) ([]models.FavoriteTeamChange, error) {
+	query := `SELECT c.user_id, c.team, c.changed_at
+		FROM favorite_team_changes c
+		JOIN bolao_participants p ON p.user_id = c.user_id
+		WHERE p.bolao_id = $1
+		ORDER BY c.changed_at, c.id`
+	rows, err := r.pool.Query(ctx, query, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []models.FavoriteTeamChange
+	for rows.Next() {
+		var ch models.FavoriteTeamChange
+		if err := rows.Scan(&ch.UserID, &ch.Team, &ch.ChangedAt); err != nil {
+			return nil, err
+		}
+		changes = append(changes, ch)
+	}
+	return changes, rows.Err()
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/bolao-app/api/internal/models"
 	"github.com/bolao-app/api/internal/repository"
+	"github.com/google/uuid"
 )
 
 var ErrNoActiveBolao = errors.New("nenhum bolão ativo encontrado")
@@ -24,12 +25,19 @@ func (e *ErrUnresolvedMatches) Error() string {
 }
 
 type BolaoService struct {
-	bolaoRepo *repository.BolaoRepository
-	matchRepo *repository.MatchRepository
+	bolaoRepo    *repository.BolaoRepository
+	matchRepo    *repository.MatchRepository
+	rulesetRepo  *repository.ScoringRulesetRepository
+	outrightRepo *repository.OutrightRepository
 }
 
-func NewBolaoService(bolaoRepo *repository.BolaoRepository, matchRepo *repository.MatchRepository) *BolaoService {
-	return &BolaoService{bolaoRepo: bolaoRepo, matchRepo: matchRepo}
+func NewBolaoService(
+	bolaoRepo *repository.BolaoRepository,
+	matchRepo *repository.MatchRepository,
+	rulesetRepo *repository.ScoringRulesetRepository,
+	outrightRepo *repository.OutrightRepository,
+) *BolaoService {
+	return &BolaoService{bolaoRepo: bolaoRepo, matchRepo: matchRepo, rulesetRepo: rulesetRepo, outrightRepo: outrightRepo}
 }
 
 func (s *BolaoService) GetActiveOrErr(ctx context.Context) (*models.Bolao, error) {
@@ -72,6 +80,20 @@ func (s *BolaoService) FinishActive(ctx context.Context, force bool) (*models.Bo
 		if unresolvedCount > 0 {
 			return nil, &ErrUnresolvedMatches{Rounds: unresolvedRounds, Count: unresolvedCount}
 		}
+
+		questions, err := s.outrightRepo.ListQuestions(ctx, active.ID)
+		if err != nil {
+			return nil, err
+		}
+		var unresolvedQuestions []string
+		for _, q := range questions {
+			if q.Outcome == nil {
+				unresolvedQuestions = append(unresolvedQuestions, q.Title)
+			}
+		}
+		if len(unresolvedQuestions) > 0 {
+			return nil, &ErrUnresolvedOutrights{Questions: unresolvedQuestions, Count: len(unresolvedQuestions)}
+		}
 	}
 
 	if err := s.bolaoRepo.Finish(ctx, active.ID); err != nil {
@@ -80,19 +102,77 @@ func (s *BolaoService) FinishActive(ctx context.Context, force bool) (*models.Bo
 	return s.bolaoRepo.GetByID(ctx, active.ID)
 }
 
-func (s *BolaoService) CreateNew(ctx context.Context, name string) (*models.Bolao, error) {
-	if _, err := s.bolaoRepo.GetActive(ctx); err == nil {
-		return nil, ErrActiveBolaoExists
+// AddRulesetVersion changes the active bolão's rules from next.EffectiveFromRound on. The
+// rounds before it keep being scored by the version they were played under.
+func (s *BolaoService) AddRulesetVersion(ctx context.Context, bolaoID uuid.UUID, next models.ScoringRuleset) (*models.ScoringRuleset, error) {
+	active, err := s.bolaoRepo.GetActive(ctx)
+	if err != nil || active.ID != bolaoID {
+		return nil, ErrNoActiveBolao
 	}
-
-	bolao, err := s.bolaoRepo.Create(ctx, name)
+	history, err := LoadRulesetHistory(ctx, s.rulesetRepo, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	next, err = NextRulesetVersion(history, next)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.rulesetRepo.Create(ctx, &next); err != nil {
+		return nil, err
+	}
+	return &next, nil
+}
 
-	if err := s.bolaoRepo.EnrollAllUsers(ctx, bolao.ID); err != nil {
+// ValidateBolaoSettings checks every setting of a bolão, the first error found winning.
+func ValidateBolaoSettings(settings models.BolaoSettings) error {
+	if err := ValidateTiebreakers(settings.Tiebreakers); err != nil {
+		return err
+	}
+	if err := ValidateRoundWeights(settings.RoundWeights); err != nil {
+		return err
+	}
+	if err := ValidateDropWorstRounds(settings.DropWorstRounds); err != nil {
+		return err
+	}
+	if err := ValidateRoundRanges(settings.RoundRanges); err != nil {
+		return err
+	}
+	return ValidateMonthlyPrize(settings.MonthlyPrize)
+}
+
+// UpdateSettings changes how the active bolão's standings are built. Unlike the rules,
+// the settings have no versions: a change recomputes the whole season's standings.
+func (s *BolaoService) UpdateSettings(ctx context.Context, bolaoID uuid.UUID, settings models.BolaoSettings) (*models.Bolao, error) {
+	active, err := s.bolaoRepo.GetActive(ctx)
+	if err != nil || active.ID != bolaoID {
+		return nil, ErrNoActiveBolao
+	}
+	if err := ValidateBolaoSettings(settings); err != nil {
 		return nil, err
 	}
+	if err := s.bolaoRepo.UpdateSettings(ctx, bolaoID, settings); err != nil {
+		return nil, err
+	}
+	return s.bolaoRepo.GetByID(ctx, bolaoID)
+}
+
+// CreateNew starts a bolão scored with rules, every user enrolled. The ruleset and settings
+// are validated before anything is written, and the bolão, its rules and the enrollments
+// are written together, so a failure never leaves a bolão behind without rules.
+func (s *BolaoService) CreateNew(ctx context.Context, name string, rules models.ScoringRuleset, settings models.BolaoSettings) (*models.Bolao, error) {
+	if err := ValidateScoringRuleset(rules); err != nil {
+		return nil, err
+	}
+	if err := ValidateBolaoSettings(settings); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.bolaoRepo.GetActive(ctx); err == nil {
+		return nil, ErrActiveBolaoExists
+	}
 
-	return bolao, nil
+	rules.ID = uuid.New()
+	rules.Version = 1
+	rules.EffectiveFromRound = 1
+	return s.bolaoRepo.Create(ctx, name, settings, &rules)
 }
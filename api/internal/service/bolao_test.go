@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/bolao-app/api/internal/models"
+)
+
+// Every setting goes through the one validator CreateNew and UpdateSettings share.
+func TestValidateBolaoSettings(t *testing.T) {
+	valid := models.BolaoSettings{RoundRanges: DefaultRoundRanges(), MonthlyPrize: 50}
+	if err := ValidateBolaoSettings(valid); err != nil {
+		t.Fatalf("valid settings: err = %v", err)
+	}
+
+	for name, tc := range map[string]struct {
+		edit func(*models.BolaoSettings)
+		want error
+	}{
+		"tiebreakers": {func(s *models.BolaoSettings) { s.Tiebreakers = []string{"sorteio"} }, ErrInvalidTiebreakers},
+		"round weight": {func(s *models.BolaoSettings) {
+			s.RoundWeights = []models.RoundWeight{{FromRound: 0, ToRound: 1, Percent: 100}}
+		}, ErrInvalidRoundWeights},
+		"drop worst":   {func(s *models.BolaoSettings) { s.DropWorstRounds = -1 }, ErrInvalidDropWorstRounds},
+		"round ranges": {func(s *models.BolaoSettings) { s.RoundRanges = []models.RoundRange{{FromRound: 1, ToRound: 2}} }, ErrInvalidRoundRanges},
+		"prize":        {func(s *models.BolaoSettings) { s.MonthlyPrize = math.NaN() }, ErrInvalidMonthlyPrize},
+	} {
+		settings := valid
+		tc.edit(&settings)
+		if err := ValidateBolaoSettings(settings); !errors.Is(err, tc.want) {
+			t.Errorf("%s: err = %v, want %v", name, err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrRoundMarketOpen = errors.New("só é possível ver palpites de outros jogadores após o fechamento do mercado da rodada")
+
+// MatchPointsBreakdown explains one match of a participant's round.
+type MatchPointsBreakdown struct {
+	MatchID  uuid.UUID `json:"match_id"`
+	HomeTeam string    `json:"home_team"`
+	AwayTeam string    `json:"away_team"`
+	// PredHome/PredAway are the effective prediction: nil when the participant did not
+	// predict and the market is still open, 0×0 with AutoFilled once it has closed.
+	PredHome   *int `json:"pred_home"`
+	PredAway   *int `json:"pred_away"`
+	AutoFilled bool `json:"auto_filled,omitempty"`
+	Joker      bool `json:"joker,omitempty"`
+	// HomeGoals/AwayGoals are the final result; a match without one scores nothing yet.
+	HomeGoals *int        `json:"home_goals"`
+	AwayGoals *int        `json:"away_goals"`
+	Items     []ScoreItem `json:"items"`
+	Points    int         `json:"points"`
+}
+
+// RoundPointsBreakdown is every point a participant got in a round, match by match, plus
+// the round-level bonuses. Points always equals the round classification's total.
+type RoundPointsBreakdown struct {
+	UserID uuid.UUID `json:"user_id"`
+	Round  int       `json:"round"`
+	// RulesVersion and RulesFromRound identify the version of the bolão's rules that
+	// scored the round.
+	RulesVersion   int                    `json:"rules_version"`
+	RulesFromRound int                    `json:"rules_from_round"`
+	Matches        []MatchPointsBreakdown `json:"matches"`
+	Bonuses        []ScoreItem            `json:"bonuses"`
+	Points         int                    `json:"points"`
+	ExactScores    int                    `json:"exact_scores"`
+	CorrectResults int                    `json:"correct_results"`
+}
+
+// explainRound breaks down userID's round the way GetClassificationForRound scores it:
+// only matches with a final result count, with the round-total bonus enabled.
+func (d *bolaoSnapshot) explainRound(userID uuid.UUID, round int, now time.Time) *RoundPointsBreakdown {
+	rules := d.rulesets.ForRound(round)
+	matches := d.byRound[round]
+	counted, awardRoundTotalBonus := d.countedMatches(round, finalResults)
+	joker := d.jokers.match(userID, round)
+	b := explainParticipantRound(rules, counted, d.predictions.of(userID), joker, d.favorites.forRound(userID, matches, now),
+		d.questions.items(userID, round), awardRoundTotalBonus, now)
+
+	itemsByMatch := make(map[uuid.UUID][]ScoreItem, len(counted))
+	for i, mwr := range counted {
+		itemsByMatch[mwr.m.ID] = b.Matches[i]
+	}
+
+	out := &RoundPointsBreakdown{
+		UserID:         userID,
+		Round:          round,
+		RulesVersion:   rules.Version,
+		RulesFromRound: rules.EffectiveFromRound,
+		Matches:        make([]MatchPointsBreakdown, 0, len(matches)),
+		Bonuses:        b.Bonuses,
+		Points:         b.Points,
+		ExactScores:    b.ExactScores,
+		CorrectResults: b.CorrectResults,
+	}
+	if out.Bonuses == nil {
+		out.Bonuses = []ScoreItem{}
+	}
+	for _, m := range matches {
+		p, has := d.predictions[userID][m.ID]
+		entry := MatchPointsBreakdown{
+			MatchID:   m.ID,
+			HomeTeam:  m.HomeTeam,
+			AwayTeam:  m.AwayTeam,
+			HomeGoals: m.HomeGoals,
+			AwayGoals: m.AwayGoals,
+			Items:     itemsByMatch[m.ID],
+			Joker:     joker != uuid.Nil && m.ID == joker,
+		}
+		if h, a, counts := EffectivePrediction(m, p.Home, p.Away, has, now); counts {
+			entry.PredHome, entry.PredAway = &h, &a
+			entry.AutoFilled = !has
+		}
+		if entry.Items == nil {
+			entry.Items = []ScoreItem{}
+		}
+		entry.Points = sumItems(entry.Items)
+		out.Matches = append(out.Matches, entry)
+	}
+	return out
+}
+
+// ExplainRound breaks down, rule by rule, userID's points in round. It reveals their
+// predictions, so it returns ErrRoundMarketOpen until every market of the round has
+// closed, and ErrNotParticipant when they aren't one of the bolão's participants.
+func (s *ClassificationService) ExplainRound(ctx context.Context, bolaoID, userID uuid.UUID, round int) (*RoundPointsBreakdown, error) {
+	d, err := s.loadSnapshot(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	if !RoundMarketClosed(d.byRound[round], now) {
+		return nil, ErrRoundMarketOpen
+	}
+	for _, p := range d.participants {
+		if p.ID == userID {
+			return d.explainRound(userID, round, now), nil
+		}
+	}
+	return nil, ErrNotParticipant
+}
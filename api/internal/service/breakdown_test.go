@@ -0,0 +1,71 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestExplainRoundMatchesClassification(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	matches := []models.Match{
+		exportMatch("Vitória", "Remo", 2, 1, closed),
+		exportMatch("Atlético-MG", "Palmeiras", 0, 0, closed),                                     // no-show -> 0×0
+		{ID: uuid.New(), Round: 1, HomeTeam: "Bahia", AwayTeam: "Santos", MarketClosesAt: closed}, // not played
+	}
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	d := testSnapshot([]models.User{ana, bruno}, matches, []models.Prediction{
+		testPrediction(ana, matches[0], 2, 1),
+		testPrediction(ana, matches[2], 1, 1),
+		testPrediction(bruno, matches[0], 0, 3), // someone else
+	})
+
+	got := d.explainRound(ana.ID, 1, testNow)
+
+	// 18 (exact 2-1) + 18 (auto-filled 0-0) + 10 for two exact-score types. The predicted
+	// total of the played matches is 3, the real one 3, so the round-total bonus applies too.
+	if got.Points != 56 || got.ExactScores != 2 || got.CorrectResults != 2 {
+		t.Fatalf("explainRound = {%d %d %d}, want {56 2 2}", got.Points, got.ExactScores, got.CorrectResults)
+	}
+	if len(got.Matches) != 3 {
+		t.Fatalf("%d matches explained, want all 3 of the round", len(got.Matches))
+	}
+
+	sum := 0
+	for _, m := range got.Matches {
+		sum += m.Points
+	}
+	if sum+sumItems(got.Bonuses) != got.Points {
+		t.Errorf("match points %d + bonuses %d != total %d", sum, sumItems(got.Bonuses), got.Points)
+	}
+
+	if !got.Matches[1].AutoFilled || got.Matches[1].PredHome == nil || *got.Matches[1].PredHome != 0 {
+		t.Errorf("the no-show match = %+v, want an auto-filled 0×0", got.Matches[1])
+	}
+	if got.Matches[2].Points != 0 || len(got.Matches[2].Items) != 0 {
+		t.Errorf("a match without a result scored %+v", got.Matches[2])
+	}
+
+	want := []string{"round_total_goals", "score_type_bonus"}
+	if len(got.Bonuses) != len(want) {
+		t.Fatalf("bonuses = %+v, want %v", got.Bonuses, want)
+	}
+	for i, rule := range want {
+		if got.Bonuses[i].Rule != rule {
+			t.Errorf("bonus %d = %s, want %s", i, got.Bonuses[i].Rule, rule)
+		}
+	}
+}
+
+func TestExplainRoundOpenMarketHasNoPrediction(t *testing.T) {
+	m := exportMatch("Vitória", "Remo", 1, 0, nil)
+	ana := exportUser("Ana")
+	d := testSnapshot([]models.User{ana}, []models.Match{m}, nil)
+
+	got := d.explainRound(ana.ID, 1, testNow)
+	if got.Points != 0 || got.Matches[0].PredHome != nil || got.Matches[0].AutoFilled {
+		t.Errorf("open market without a prediction = %+v, want no prediction and no points", got.Matches[0])
+	}
+}
@@ -2,7 +2,6 @@ package service
 
 import (
 	"context"
-	"sort"
 	"time"
 
 	"github.com/bolao-app/api/internal/models"
@@ -13,67 +12,161 @@ import (
 type matchWithResult struct {
 	m          models.Match
 	home, away int
+	// shares is the match's outcome split, nil when the underdog bonus doesn't
+	// apply (see LoadOutcomeShares).
+	shares *models.OutcomeShares
+	// tie is the two-legged tie the match is the second leg of, nil for any other match.
+	tie *tieLegs
+}
+
+// setMatchContext attaches each match's outcome split and tie.
+func setMatchContext(matches []matchWithResult, shares OutcomeShareIndex, ties TieIndex) {
+	for i := range matches {
+		matches[i].shares = shares.of(matches[i].m.ID)
+		matches[i].tie = ties[matches[i].m.ID]
+	}
+}
+
+func participantIDs(participants []models.ParticipantView) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(participants))
+	for _, p := range participants {
+		ids = append(ids, p.ID)
+	}
+	return ids
 }
 
 type roundScore struct {
 	points         int
 	exactScores    int
 	correctResults int
+	missed         int
+	roundTotalHits int
+}
+
+// standing is the round's score as the ranking sees it. firstSubmission is when the
+// participant's first prediction of the round was sent, if any.
+func (rs roundScore) standing(firstSubmission *time.Time) Standing {
+	return Standing{
+		Points:          rs.points,
+		ExactScores:     rs.exactScores,
+		CorrectResults:  rs.correctResults,
+		Missed:          rs.missed,
+		RoundTotalHits:  rs.roundTotalHits,
+		FirstSubmission: firstSubmission,
+	}
 }
 
 // scoreParticipantRound scores one participant over one round. lookup reports the stored
 // prediction for a match, with has=false when the participant did not submit one — which
-// EffectivePredEntry turns into 0×0 if that match's market has closed.
+// EffectivePredEntry turns into 0×0 if that match's market has closed. joker is the
+// participant's coringa for the round, uuid.Nil when none was picked. favoriteTeam is the
+// team the favorite-team bonus uses for them in the round, "" for none. questions are the
+// items the participant's bonus-question answers earned in the round.
 func scoreParticipantRound(
+	rules models.ScoringRuleset,
 	matches []matchWithResult,
-	lookup func(matchID uuid.UUID) (home, away int, has bool),
+	lookup predictionLookup,
+	joker uuid.UUID,
+	favoriteTeam string,
+	questions []ScoreItem,
 	awardRoundTotalBonus bool,
 	now time.Time,
 ) roundScore {
+	b := explainParticipantRound(rules, matches, lookup, joker, favoriteTeam, questions, awardRoundTotalBonus, now)
+	return roundScore{b.Points, b.ExactScores, b.CorrectResults, b.Missed, b.RoundTotalHits}
+}
+
+// explainParticipantRound is scoreParticipantRound with the breakdown kept, so the
+// per-match explanation can never disagree with the standings.
+func explainParticipantRound(
+	rules models.ScoringRuleset,
+	matches []matchWithResult,
+	lookup predictionLookup,
+	joker uuid.UUID,
+	favoriteTeam string,
+	questions []ScoreItem,
+	awardRoundTotalBonus bool,
+	now time.Time,
+) RoundBreakdown {
 	predList := make([]PredEntry, 0, len(matches))
 	matchList := make([]MatchScore, 0, len(matches))
+	missed := 0
 	for _, mwr := range matches {
-		home, away, has := lookup(mwr.m.ID)
-		predList = append(predList, EffectivePredEntry(mwr.m, home, away, has, now))
+		p, has := lookup(mwr.m.ID)
+		entry := EffectivePredEntry(mwr.m, p.Home, p.Away, has, now)
+		entry.Joker = joker != uuid.Nil && mwr.m.ID == joker
+		entry.Shares = mwr.shares
+		if playsFor(mwr.m, favoriteTeam) {
+			entry.FavoriteTeam = favoriteTeam
+		}
+		entry.Advances, entry.Qualifier = knockoutSides(mwr.m, mwr.tie, entry, p.Advances, lookup, now)
+		if !has && entry.PredHome != noPredSentinel {
+			missed++
+		}
+		predList = append(predList, entry)
 		matchList = append(matchList, MatchScore{HomeGoals: mwr.home, AwayGoals: mwr.away})
 	}
-	points, exactScores, correctResults := CalculateRoundPoints(predList, matchList, awardRoundTotalBonus)
-	return roundScore{points, exactScores, correctResults}
+	b := CalculateRoundBreakdown(rules, predList, matchList, awardRoundTotalBonus)
+	b.Missed = missed
+	// Bonus questions are round-level points like the §3 bonuses, but they aren't a
+	// function of the score predictions, so they are added here rather than in the
+	// calculator.
+	b.Bonuses = append(b.Bonuses, questions...)
+	b.Points += sumItems(questions)
+	return b
 }
 
 // pickRoundWinner returns the round winner, or ok=false when nobody scored. Players on
-// zero are skipped, so a round nobody scored in has no winner.
+// zero are skipped, so a round nobody scored in has no winner. scores are the round's
+// standings, ranked by the bolão's tiebreak chain.
 //
 // The last tiebreaker is the user ID. It is arbitrary, but Go randomizes map iteration, so
 // without it a fully tied round picks a different winner on every request and RoundsWon —
 // a standings tiebreaker — flaps between page loads. Players tied on every real criterion
 // have to be separated by something stable.
-func pickRoundWinner(scores map[uuid.UUID]roundScore) (uuid.UUID, bool) {
+func pickRoundWinner(ranking Ranking, scores map[uuid.UUID]Standing) (uuid.UUID, bool) {
 	var winner uuid.UUID
-	var best roundScore
+	var best Standing
 	found := false
-	for uid, rs := range scores {
-		if rs.points == 0 {
+	for uid, st := range scores {
+		if st.Points == 0 {
 			continue
 		}
-		if !found || beatsRoundWinner(uid, rs, winner, best) {
-			winner, best, found = uid, rs, true
+		if !found || beatsRoundWinner(ranking, uid, st, winner, best) {
+			winner, best, found = uid, st, true
 		}
 	}
 	return winner, found
 }
 
-func beatsRoundWinner(uid uuid.UUID, rs roundScore, winner uuid.UUID, best roundScore) bool {
-	if rs.points != best.points {
-		return rs.points > best.points
+func beatsRoundWinner(ranking Ranking, uid uuid.UUID, st Standing, winner uuid.UUID, best Standing) bool {
+	if c := ranking.Compare(st, best); c != 0 {
+		return c < 0
 	}
-	if rs.exactScores != best.exactScores {
-		return rs.exactScores > best.exactScores
+	return uid.String() < winner.String()
+}
+
+// roundStandings turns a round's scores into what the ranking compares, with each
+// participant's first submission of the round.
+func roundStandings(scores map[uuid.UUID]roundScore, submissions map[uuid.UUID]map[int]time.Time, round int) map[uuid.UUID]Standing {
+	standings := make(map[uuid.UUID]Standing, len(scores))
+	for uid, rs := range scores {
+		var first *time.Time
+		if t, ok := submissions[uid][round]; ok {
+			first = &t
+		}
+		standings[uid] = rs.standing(first)
 	}
-	if rs.correctResults != best.correctResults {
-		return rs.correctResults > best.correctResults
+	return standings
+}
+
+// matchRounds maps each match to its round, for firstSubmissions.
+func matchRounds(matches []models.Match) map[uuid.UUID]int {
+	rounds := make(map[uuid.UUID]int, len(matches))
+	for _, m := range matches {
+		rounds[m.ID] = m.Round
 	}
-	return uid.String() < winner.String()
+	return rounds
 }
 
 type ClassificationService struct {
@@ -81,6 +174,14 @@ type ClassificationService struct {
 	matchRepo      *repository.MatchRepository
 	predictionRepo *repository.PredictionRepository
 	partialRepo    *repository.PartialRepository
+	rulesetRepo    *repository.ScoringRulesetRepository
+	jokerRepo      *repository.JokerRepository
+	outrightRepo   *repository.OutrightRepository
+	questionRepo   *repository.RoundQuestionRepository
+	shareRepo      *repository.OutcomeShareRepository
+	userRepo       *repository.UserRepository
+	tieRepo        *repository.TieRepository
+	roundScoreRepo *repository.RoundScoreRepository
 }
 
 func NewClassificationService(
@@ -88,12 +189,28 @@ func NewClassificationService(
 	matchRepo *repository.MatchRepository,
 	predictionRepo *repository.PredictionRepository,
 	partialRepo *repository.PartialRepository,
+	rulesetRepo *repository.ScoringRulesetRepository,
+	jokerRepo *repository.JokerRepository,
+	outrightRepo *repository.OutrightRepository,
+	questionRepo *repository.RoundQuestionRepository,
+	shareRepo *repository.OutcomeShareRepository,
+	userRepo *repository.UserRepository,
+	tieRepo *repository.TieRepository,
+	roundScoreRepo *repository.RoundScoreRepository,
 ) *ClassificationService {
 	return &ClassificationService{
 		bolaoRepo:      bolaoRepo,
 		matchRepo:      matchRepo,
 		predictionRepo: predictionRepo,
 		partialRepo:    partialRepo,
+		rulesetRepo:    rulesetRepo,
+		jokerRepo:      jokerRepo,
+		outrightRepo:   outrightRepo,
+		questionRepo:   questionRepo,
+		shareRepo:      shareRepo,
+		userRepo:       userRepo,
+		tieRepo:        tieRepo,
+		roundScoreRepo: roundScoreRepo,
 	}
 }
 
@@ -105,120 +222,77 @@ type RoundScore struct {
 	CorrectResults int       `json:"correct_results"`
 }
 
+// GetClassification returns the cumulative classification up to upToRound, added up from
+// the materialized round scores. Only the rounds whose scores are stale are rescored.
 func (s *ClassificationService) GetClassification(ctx context.Context, bolaoID uuid.UUID, upToRound int) ([]models.UserWithStats, error) {
-	// Fetch everything for the bolão in bulk (3 queries total, regardless of round count)
-	// instead of one query per round per participant.
-	allMatches, err := s.matchRepo.ListAllByBolao(ctx, bolaoID)
+	d, rows, maxRound, err := s.loadRoundScores(ctx, bolaoID, upToRound)
 	if err != nil {
 		return nil, err
 	}
-	matchesByRound := make(map[int][]models.Match)
-	maxRound := 0
-	for _, m := range allMatches {
-		matchesByRound[m.Round] = append(matchesByRound[m.Round], m)
-		if m.Round > maxRound {
-			maxRound = m.Round
-		}
-	}
-
 	// Quando "todas" as rodadas são pedidas (0 ou >= 999), usar a última rodada que existe no banco
 	if upToRound <= 0 || upToRound >= 999 {
 		upToRound = maxRound
 	}
+	return d.standingsFromRows(upToRound, rows), nil
+}
 
-	participants, err := s.bolaoRepo.ListParticipants(ctx, bolaoID)
+// loadRoundScores returns the standings base of the bolão and the scores of its rounds up
+// to upToRound (every round when it is 0 or >= 999), recomputing the stale ones, with the
+// bolão's last round.
+func (s *ClassificationService) loadRoundScores(ctx context.Context, bolaoID uuid.UUID, upToRound int) (*bolaoSnapshot, map[int][]models.RoundScoreRow, int, error) {
+	rounds, err := s.matchRepo.ListRounds(ctx, bolaoID)
 	if err != nil {
-		return nil, err
+		return nil, nil, 0, err
 	}
-
-	allPredictions, err := s.predictionRepo.GetAllForBolao(ctx, bolaoID)
+	d, err := s.loadStandingsBase(ctx, bolaoID)
 	if err != nil {
-		return nil, err
+		return nil, nil, 0, err
 	}
-	predByMatchUser := make(map[uuid.UUID]map[uuid.UUID]struct{ Home, Away int })
-	for _, p := range allPredictions {
-		if predByMatchUser[p.MatchID] == nil {
-			predByMatchUser[p.MatchID] = make(map[uuid.UUID]struct{ Home, Away int })
-		}
-		predByMatchUser[p.MatchID][p.UserID] = struct{ Home, Away int }{p.HomeGoals, p.AwayGoals}
+	cache, err := s.loadRoundScoreCache(ctx, bolaoID)
+	if err != nil {
+		return nil, nil, 0, err
 	}
 
-	userStats := make(map[uuid.UUID]*models.UserWithStats)
-	for _, p := range participants {
-		userStats[p.ID] = &models.UserWithStats{
-			User:           p.User,
-			AmountPaid:     p.AmountPaid,
-			TotalPoints:    0,
-			ExactScores:    0,
-			CorrectResults: 0,
-			RoundsWon:      0,
-		}
+	maxRound := 0
+	if len(rounds) > 0 {
+		maxRound = rounds[len(rounds)-1]
+	}
+	if upToRound <= 0 || upToRound >= 999 {
+		upToRound = maxRound
 	}
-
-	// Round winners for tiebreaker
-	roundWinners := make(map[int]uuid.UUID)
 
 	now := time.Now()
-
-	for round := 1; round <= upToRound; round++ {
-		// Considera só jogos com resultado; jogos sem placar são ignorados.
-		var matchesWithResults []matchWithResult
-		for _, m := range matchesByRound[round] {
-			if m.HomeGoals == nil || m.AwayGoals == nil {
-				continue
-			}
-			matchesWithResults = append(matchesWithResults, matchWithResult{m, *m.HomeGoals, *m.AwayGoals})
-		}
-		if len(matchesWithResults) == 0 {
+	// Rows of a deleted round whose refresh failed are left out with the round.
+	rows := make(map[int][]models.RoundScoreRow, len(rounds))
+	stale := make(map[int]int64)
+	for _, round := range rounds {
+		if round > upToRound {
 			continue
 		}
-
-		roundScores := make(map[uuid.UUID]roundScore)
-
-		for _, participant := range participants {
-			rs := scoreParticipantRound(matchesWithResults, func(matchID uuid.UUID) (int, int, bool) {
-				if byUser, ok := predByMatchUser[matchID]; ok {
-					if p, ok2 := byUser[participant.ID]; ok2 {
-						return p.Home, p.Away, true
-					}
-				}
-				return 0, 0, false
-			}, true, now)
-
-			userStats[participant.ID].TotalPoints += rs.points
-			userStats[participant.ID].ExactScores += rs.exactScores
-			userStats[participant.ID].CorrectResults += rs.correctResults
-
-			roundScores[participant.ID] = rs
-		}
-
-		if winner, ok := pickRoundWinner(roundScores); ok {
-			roundWinners[round] = winner
-			userStats[winner].RoundsWon++
+		if cache.fresh(round, d.participants, now) {
+			rows[round] = cache.rows[round]
+		} else {
+			stale[round] = cache.generation(round)
 		}
 	}
-
-	// Build result and sort by tiebreaker rules
-	result := make([]models.UserWithStats, 0, len(userStats))
-	for _, u := range userStats {
-		result = append(result, *u)
-	}
-
-	sort.Slice(result, func(i, j int) bool {
-		a, b := result[i], result[j]
-		if a.TotalPoints != b.TotalPoints {
-			return a.TotalPoints > b.TotalPoints
+	if len(stale) > 0 {
+		staleRounds := make([]int, 0, len(stale))
+		for round := range stale {
+			staleRounds = append(staleRounds, round)
 		}
-		if a.ExactScores != b.ExactScores {
-			return a.ExactScores > b.ExactScores
+		snapshot, err := s.loadRounds(ctx, d, bolaoID, staleRounds)
+		if err != nil {
+			return nil, nil, 0, err
 		}
-		if a.CorrectResults != b.CorrectResults {
-			return a.CorrectResults > b.CorrectResults
+		computed, err := s.recompute(ctx, snapshot, bolaoID, stale, now)
+		if err != nil {
+			return nil, nil, 0, err
 		}
-		return a.RoundsWon > b.RoundsWon
-	})
-
-	return result, nil
+		for round, r := range computed {
+			rows[round] = r
+		}
+	}
+	return d, rows, maxRound, nil
 }
 
 // GetClassificationForRound returns ranking for a single round only (points in that round),
@@ -233,14 +307,14 @@ func (s *ClassificationService) GetClassificationForRound(ctx context.Context, b
 		if m.HomeGoals == nil || m.AwayGoals == nil {
 			continue
 		}
-		matchesWithResults = append(matchesWithResults, matchWithResult{m, *m.HomeGoals, *m.AwayGoals})
+		matchesWithResults = append(matchesWithResults, matchWithResult{m, *m.HomeGoals, *m.AwayGoals, nil, nil})
 	}
 	if len(matchesWithResults) == 0 {
 		participants, _ := s.bolaoRepo.ListParticipants(ctx, bolaoID)
 		result := make([]models.UserWithStats, 0, len(participants))
-		for _, p := range participants {
+		for i, p := range participants {
 			result = append(result, models.UserWithStats{
-				User: p.User, AmountPaid: p.AmountPaid, TotalPoints: 0, ExactScores: 0, CorrectResults: 0, RoundsWon: 0,
+				User: p.User, Position: i + 1, AmountPaid: p.AmountPaid, TotalPoints: 0, ExactScores: 0, CorrectResults: 0, RoundsWon: 0,
 			})
 		}
 		return result, nil
@@ -251,51 +325,71 @@ func (s *ClassificationService) GetClassificationForRound(ctx context.Context, b
 		return nil, err
 	}
 
+	rulesets, err := LoadRulesetHistory(ctx, s.rulesetRepo, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	rules := rulesets.ForRound(round)
+	bolao, err := s.bolaoRepo.GetByID(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+
 	allPredictions, err := s.predictionRepo.GetAllPredictionsForRound(ctx, bolaoID, round)
 	if err != nil {
 		return nil, err
 	}
-	predByUserMatch := make(map[uuid.UUID]map[uuid.UUID]struct{ Home, Away int })
-	for _, p := range allPredictions {
-		if predByUserMatch[p.UserID] == nil {
-			predByUserMatch[p.UserID] = make(map[uuid.UUID]struct{ Home, Away int })
-		}
-		predByUserMatch[p.UserID][p.MatchID] = struct{ Home, Away int }{p.HomeGoals, p.AwayGoals}
+	roundJokers, err := s.jokerRepo.ListByRound(ctx, bolaoID, round)
+	if err != nil {
+		return nil, err
+	}
+	jokers := indexJokers(roundJokers)
+	roundQuestions, err := s.questionRepo.ListByRound(ctx, bolaoID, round)
+	if err != nil {
+		return nil, err
 	}
+	roundAnswers, err := s.questionRepo.ListAnswersByRound(ctx, bolaoID, round)
+	if err != nil {
+		return nil, err
+	}
+	questions := indexRoundQuestions(roundQuestions, roundAnswers)
+	submissions := firstSubmissions(allPredictions, matchRounds(matches))
+	predByUserMatch := indexPredictions(allPredictions)
 
 	now := time.Now()
+	shares, err := LoadOutcomeShares(ctx, s.shareRepo, bolaoID, rulesets, matches, participantIDs(participants), allPredictions, now)
+	if err != nil {
+		return nil, err
+	}
+	favorites, err := LoadFavoriteTeams(ctx, s.userRepo, bolaoID, rulesets)
+	if err != nil {
+		return nil, err
+	}
+	ties, err := LoadTies(ctx, s.tieRepo, s.matchRepo, s.predictionRepo, bolaoID, rulesets)
+	if err != nil {
+		return nil, err
+	}
+	setMatchContext(matchesWithResults, shares, ties)
 
 	result := make([]models.UserWithStats, 0, len(participants))
+	scores := make(map[uuid.UUID]roundScore, len(participants))
 	for _, participant := range participants {
-		predByMatch := predByUserMatch[participant.ID]
-		rs := scoreParticipantRound(matchesWithResults, func(matchID uuid.UUID) (int, int, bool) {
-			// p is the zero value when !has; EffectivePredEntry ignores it then.
-			p, has := predByMatch[matchID]
-			return p.Home, p.Away, has
-		}, true, now)
+		lookup := ties.withFirstLegs(participant.ID, predByUserMatch.of(participant.ID))
+		rs := scoreParticipantRound(rules, matchesWithResults, lookup, jokers.match(participant.ID, round), favorites.forRound(participant.ID, matches, now), questions.items(participant.ID, round), true, now)
 		result = append(result, models.UserWithStats{
-			User:           participant.User,
-			AmountPaid:     participant.AmountPaid,
-			TotalPoints:    rs.points,
-			ExactScores:    rs.exactScores,
-			CorrectResults: rs.correctResults,
-			RoundsWon:      0,
+			User:              participant.User,
+			AmountPaid:        participant.AmountPaid,
+			TotalPoints:       rs.points,
+			ExactScores:       rs.exactScores,
+			CorrectResults:    rs.correctResults,
+			RoundsWon:         0,
+			MissedPredictions: rs.missed,
+			RoundTotalHits:    rs.roundTotalHits,
 		})
+		scores[participant.ID] = rs
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		a, b := result[i], result[j]
-		if a.TotalPoints != b.TotalPoints {
-			return a.TotalPoints > b.TotalPoints
-		}
-		if a.ExactScores != b.ExactScores {
-			return a.ExactScores > b.ExactScores
-		}
-		if a.CorrectResults != b.CorrectResults {
-			return a.CorrectResults > b.CorrectResults
-		}
-		return false
-	})
+	rankUsers(RankingFor(bolao.BolaoSettings), result, roundStandings(scores, submissions, round))
 	return result, nil
 }
 
@@ -315,7 +409,7 @@ func (s *ClassificationService) GetClassificationByPartials(ctx context.Context,
 	var scoredMatches []matchWithResult
 	for _, m := range matches {
 		if p, ok := partials[m.ID]; ok && p.HomeGoals != nil && p.AwayGoals != nil {
-			scoredMatches = append(scoredMatches, matchWithResult{m, *p.HomeGoals, *p.AwayGoals})
+			scoredMatches = append(scoredMatches, matchWithResult{m, *p.HomeGoals, *p.AwayGoals, nil, nil})
 		}
 	}
 	if len(scoredMatches) == 0 {
@@ -327,53 +421,74 @@ func (s *ClassificationService) GetClassificationByPartials(ctx context.Context,
 		return nil, err
 	}
 
+	rulesets, err := LoadRulesetHistory(ctx, s.rulesetRepo, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	rules := rulesets.ForRound(round)
+	bolao, err := s.bolaoRepo.GetByID(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+
 	allPredictions, err := s.predictionRepo.GetAllPredictionsForRound(ctx, bolaoID, round)
 	if err != nil {
 		return nil, err
 	}
-	predByUserMatch := make(map[uuid.UUID]map[uuid.UUID]struct{ Home, Away int })
-	for _, p := range allPredictions {
-		if predByUserMatch[p.UserID] == nil {
-			predByUserMatch[p.UserID] = make(map[uuid.UUID]struct{ Home, Away int })
-		}
-		predByUserMatch[p.UserID][p.MatchID] = struct{ Home, Away int }{p.HomeGoals, p.AwayGoals}
+	roundJokers, err := s.jokerRepo.ListByRound(ctx, bolaoID, round)
+	if err != nil {
+		return nil, err
 	}
+	jokers := indexJokers(roundJokers)
+	roundQuestions, err := s.questionRepo.ListByRound(ctx, bolaoID, round)
+	if err != nil {
+		return nil, err
+	}
+	roundAnswers, err := s.questionRepo.ListAnswersByRound(ctx, bolaoID, round)
+	if err != nil {
+		return nil, err
+	}
+	questions := indexRoundQuestions(roundQuestions, roundAnswers)
+	submissions := firstSubmissions(allPredictions, matchRounds(matches))
+	predByUserMatch := indexPredictions(allPredictions)
 
 	now := time.Now()
+	shares, err := LoadOutcomeShares(ctx, s.shareRepo, bolaoID, rulesets, matches, participantIDs(participants), allPredictions, now)
+	if err != nil {
+		return nil, err
+	}
+	favorites, err := LoadFavoriteTeams(ctx, s.userRepo, bolaoID, rulesets)
+	if err != nil {
+		return nil, err
+	}
+	ties, err := LoadTies(ctx, s.tieRepo, s.matchRepo, s.predictionRepo, bolaoID, rulesets)
+	if err != nil {
+		return nil, err
+	}
+	setMatchContext(scoredMatches, shares, ties)
 
 	result := make([]models.UserWithStats, 0, len(participants))
+	scores := make(map[uuid.UUID]roundScore, len(participants))
 	for _, participant := range participants {
-		predByMatch := predByUserMatch[participant.ID]
+		lookup := ties.withFirstLegs(participant.ID, predByUserMatch.of(participant.ID))
 
 		// awardRoundTotalBonus stays false: the predictions cover the whole round while
 		// the parciais only cover the matches played so far.
-		rs := scoreParticipantRound(scoredMatches, func(matchID uuid.UUID) (int, int, bool) {
-			p, has := predByMatch[matchID]
-			return p.Home, p.Away, has
-		}, false, now)
+		rs := scoreParticipantRound(rules, scoredMatches, lookup, jokers.match(participant.ID, round), favorites.forRound(participant.ID, matches, now), questions.items(participant.ID, round), false, now)
 
 		result = append(result, models.UserWithStats{
-			User:           participant.User,
-			AmountPaid:     participant.AmountPaid,
-			TotalPoints:    rs.points,
-			ExactScores:    rs.exactScores,
-			CorrectResults: rs.correctResults,
-			RoundsWon:      0,
+			User:              participant.User,
+			AmountPaid:        participant.AmountPaid,
+			TotalPoints:       rs.points,
+			ExactScores:       rs.exactScores,
+			CorrectResults:    rs.correctResults,
+			RoundsWon:         0,
+			MissedPredictions: rs.missed,
+			RoundTotalHits:    rs.roundTotalHits,
 		})
+		scores[participant.ID] = rs
 	}
 
-	sort.Slice(result, func(i, j int) bool {
-		a, b := result[i], result[j]
-		if a.TotalPoints != b.TotalPoints {
-			return a.TotalPoints > b.TotalPoints
-		}
-		if a.ExactScores != b.ExactScores {
-			return a.ExactScores > b.ExactScores
-		}
-		if a.CorrectResults != b.CorrectResults {
-			return a.CorrectResults > b.CorrectResults
-		}
-		return false
-	})
+	rankUsers(RankingFor(bolao.BolaoSettings), result, roundStandings(scores, submissions, round))
 	return result, nil
 }
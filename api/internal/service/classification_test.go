@@ -19,28 +19,28 @@ func openResult(homeGoals, awayGoals int) matchWithResult {
 	return matchWithResult{m: matchClosingAt(nil), home: homeGoals, away: awayGoals}
 }
 
-func noPredictions(uuid.UUID) (int, int, bool) { return 0, 0, false }
+func noPredictions(uuid.UUID) (storedPrediction, bool) { return storedPrediction{}, false }
 
 // predictions builds a lookup over a match-indexed table, where a missing entry means the
 // participant did not submit a prediction for that match.
-func predictions(matches []matchWithResult, preds map[int][2]int) func(uuid.UUID) (int, int, bool) {
+func predictions(matches []matchWithResult, preds map[int][2]int) func(uuid.UUID) (storedPrediction, bool) {
 	byID := make(map[uuid.UUID][2]int, len(preds))
 	for i, p := range preds {
 		byID[matches[i].m.ID] = p
 	}
-	return func(matchID uuid.UUID) (int, int, bool) {
+	return func(matchID uuid.UUID) (storedPrediction, bool) {
 		p, ok := byID[matchID]
 		if !ok {
-			return 0, 0, false
+			return storedPrediction{}, false
 		}
-		return p[0], p[1], true
+		return storedPrediction{Home: p[0], Away: p[1]}, true
 	}
 }
 
 func TestScoreParticipantRoundNoShowAfterClose(t *testing.T) {
 	matches := []matchWithResult{closedResult(0, 0), closedResult(0, 1)}
 
-	got := scoreParticipantRound(matches, noPredictions, true, testNow)
+	got := scoreParticipantRound(defaultRules, matches, noPredictions, uuid.Nil, "", nil, true, testNow)
 	if got.points != 21 || got.exactScores != 1 || got.correctResults != 1 {
 		t.Errorf("no-show on a closed round = %+v, want {21 1 1}", got)
 	}
@@ -49,7 +49,7 @@ func TestScoreParticipantRoundNoShowAfterClose(t *testing.T) {
 func TestScoreParticipantRoundNoShowOpenMarket(t *testing.T) {
 	matches := []matchWithResult{openResult(0, 0), openResult(0, 1)}
 
-	got := scoreParticipantRound(matches, noPredictions, true, testNow)
+	got := scoreParticipantRound(defaultRules, matches, noPredictions, uuid.Nil, "", nil, true, testNow)
 	if got.points != 0 || got.exactScores != 0 || got.correctResults != 0 {
 		t.Errorf("no-show while the market is open = %+v, want all zero", got)
 	}
@@ -65,7 +65,7 @@ func TestScoreParticipantRoundMixed(t *testing.T) {
 	}
 	lookup := predictions(matches, map[int][2]int{0: {2, 1}})
 
-	got := scoreParticipantRound(matches, lookup, true, testNow)
+	got := scoreParticipantRound(defaultRules, matches, lookup, uuid.Nil, "", nil, true, testNow)
 	// 18 exact + 18 from the synthesized 0×0, two exact-score types (2-1 and 0-0) = +10.
 	if got.points != 46 || got.exactScores != 2 || got.correctResults != 2 {
 		t.Errorf("partially filled round = %+v, want {46 2 2}", got)
@@ -78,8 +78,8 @@ func TestScoreParticipantRoundPartialsSkipRoundTotalBonus(t *testing.T) {
 	matches := []matchWithResult{closedResult(1, 0)}
 	lookup := predictions(matches, map[int][2]int{0: {1, 0}})
 
-	final := scoreParticipantRound(matches, lookup, true, testNow)
-	partial := scoreParticipantRound(matches, lookup, false, testNow)
+	final := scoreParticipantRound(defaultRules, matches, lookup, uuid.Nil, "", nil, true, testNow)
+	partial := scoreParticipantRound(defaultRules, matches, lookup, uuid.Nil, "", nil, false, testNow)
 
 	if final.points != partial.points+PointsRoundTotalGoals {
 		t.Errorf("final=%d partial=%d, want the final to be exactly %d higher",
@@ -89,12 +89,12 @@ func TestScoreParticipantRoundPartialsSkipRoundTotalBonus(t *testing.T) {
 
 func TestPickRoundWinnerHighestPoints(t *testing.T) {
 	ana, bruno := uuid.New(), uuid.New()
-	scores := map[uuid.UUID]roundScore{
-		ana:   {points: 30, exactScores: 1, correctResults: 2},
-		bruno: {points: 21, exactScores: 2, correctResults: 2},
+	scores := map[uuid.UUID]Standing{
+		ana:   {Points: 30, ExactScores: 1, CorrectResults: 2},
+		bruno: {Points: 21, ExactScores: 2, CorrectResults: 2},
 	}
 
-	winner, ok := pickRoundWinner(scores)
+	winner, ok := pickRoundWinner(defaultRanking, scores)
 	if !ok || winner != ana {
 		t.Errorf("winner = %v (ok=%v), want Ana on points", winner, ok)
 	}
@@ -104,18 +104,18 @@ func TestPickRoundWinnerTiebreakers(t *testing.T) {
 	ana, bruno := uuid.New(), uuid.New()
 
 	// Same points: more exact scores wins.
-	winner, ok := pickRoundWinner(map[uuid.UUID]roundScore{
-		ana:   {points: 21, exactScores: 1, correctResults: 3},
-		bruno: {points: 21, exactScores: 2, correctResults: 1},
+	winner, ok := pickRoundWinner(defaultRanking, map[uuid.UUID]Standing{
+		ana:   {Points: 21, ExactScores: 1, CorrectResults: 3},
+		bruno: {Points: 21, ExactScores: 2, CorrectResults: 1},
 	})
 	if !ok || winner != bruno {
 		t.Errorf("winner = %v (ok=%v), want Bruno on exact scores", winner, ok)
 	}
 
 	// Same points and exact scores: more correct results wins.
-	winner, ok = pickRoundWinner(map[uuid.UUID]roundScore{
-		ana:   {points: 21, exactScores: 1, correctResults: 3},
-		bruno: {points: 21, exactScores: 1, correctResults: 1},
+	winner, ok = pickRoundWinner(defaultRanking, map[uuid.UUID]Standing{
+		ana:   {Points: 21, ExactScores: 1, CorrectResults: 3},
+		bruno: {Points: 21, ExactScores: 1, CorrectResults: 1},
 	})
 	if !ok || winner != ana {
 		t.Errorf("winner = %v (ok=%v), want Ana on correct results", winner, ok)
@@ -126,18 +126,18 @@ func TestPickRoundWinnerTiebreakers(t *testing.T) {
 // every call. RoundsWon feeds the standings sort, which made the leaderboard flap between
 // page loads. No-shows in the same round always tie exactly, so this is now common.
 func TestPickRoundWinnerDeterministicOnFullTie(t *testing.T) {
-	tied := roundScore{points: 21, exactScores: 1, correctResults: 1}
-	scores := map[uuid.UUID]roundScore{}
+	tied := Standing{Points: 21, ExactScores: 1, CorrectResults: 1}
+	scores := map[uuid.UUID]Standing{}
 	for range 8 {
 		scores[uuid.New()] = tied
 	}
 
-	first, ok := pickRoundWinner(scores)
+	first, ok := pickRoundWinner(defaultRanking, scores)
 	if !ok {
 		t.Fatal("a fully tied round produced no winner")
 	}
 	for i := range 200 {
-		got, _ := pickRoundWinner(scores)
+		got, _ := pickRoundWinner(defaultRanking, scores)
 		if got != first {
 			t.Fatalf("call %d returned %v, first call returned %v — winner is not deterministic", i, got, first)
 		}
@@ -146,18 +146,18 @@ func TestPickRoundWinnerDeterministicOnFullTie(t *testing.T) {
 
 func TestPickRoundWinnerNobodyScored(t *testing.T) {
 	ana, bruno := uuid.New(), uuid.New()
-	scores := map[uuid.UUID]roundScore{
-		ana:   {points: 0},
-		bruno: {points: 0},
+	scores := map[uuid.UUID]Standing{
+		ana:   {Points: 0},
+		bruno: {Points: 0},
 	}
 
-	if winner, ok := pickRoundWinner(scores); ok {
+	if winner, ok := pickRoundWinner(defaultRanking, scores); ok {
 		t.Errorf("a round nobody scored in produced winner %v, want none", winner)
 	}
 }
 
 func TestPickRoundWinnerEmpty(t *testing.T) {
-	if _, ok := pickRoundWinner(map[uuid.UUID]roundScore{}); ok {
+	if _, ok := pickRoundWinner(defaultRanking, map[uuid.UUID]Standing{}); ok {
 		t.Error("empty round produced a winner, want none")
 	}
 }
@@ -169,11 +169,11 @@ func TestPickRoundWinnerNoShowCanWin(t *testing.T) {
 	matches := []matchWithResult{closedResult(0, 0), closedResult(1, 1)}
 	absent, present := uuid.New(), uuid.New()
 
-	absentScore := scoreParticipantRound(matches, noPredictions, true, testNow)
-	presentScore := scoreParticipantRound(matches, predictions(matches, map[int][2]int{
+	absentScore := scoreParticipantRound(defaultRules, matches, noPredictions, uuid.Nil, "", nil, true, testNow)
+	presentScore := scoreParticipantRound(defaultRules, matches, predictions(matches, map[int][2]int{
 		0: {3, 2}, // wrong
 		1: {4, 0}, // wrong
-	}), true, testNow)
+	}), uuid.Nil, "", nil, true, testNow)
 
 	if absentScore.points == 0 {
 		t.Fatal("the no-show scored nothing; this test no longer covers what it claims")
@@ -183,9 +183,9 @@ func TestPickRoundWinnerNoShowCanWin(t *testing.T) {
 			presentScore.points, absentScore.points)
 	}
 
-	winner, ok := pickRoundWinner(map[uuid.UUID]roundScore{
-		absent:  absentScore,
-		present: presentScore,
+	winner, ok := pickRoundWinner(defaultRanking, map[uuid.UUID]Standing{
+		absent:  absentScore.standing(nil),
+		present: presentScore.standing(nil),
 	})
 	if !ok || winner != absent {
 		t.Errorf("winner = %v (ok=%v), want the no-show to win the round", winner, ok)
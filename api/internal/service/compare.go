@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+var ErrNotParticipant = errors.New("jogador não participa deste bolão")
+
+// ComparedPrediction is one player's effective prediction for a match of a comparison.
+type ComparedPrediction struct {
+	Home int `json:"home"`
+	Away int `json:"away"`
+	// Missing marks the 0×0 filled in for a prediction never sent.
+	Missing bool `json:"missing,omitempty"`
+	Joker   bool `json:"joker,omitempty"`
+	// Points is what the match earned, nil while it has no result.
+	Points *int `json:"points"`
+}
+
+// MatchComparison is a closed match with both players' predictions.
+type MatchComparison struct {
+	MatchID   uuid.UUID          `json:"match_id"`
+	Round     int                `json:"round"`
+	HomeTeam  string             `json:"home_team"`
+	AwayTeam  string             `json:"away_team"`
+	HomeGoals *int               `json:"home_goals"`
+	AwayGoals *int               `json:"away_goals"`
+	UserA     ComparedPrediction `json:"user_a"`
+	UserB     ComparedPrediction `json:"user_b"`
+	// Diverged is set when the two predictions call different outcomes.
+	Diverged bool `json:"diverged"`
+}
+
+// RoundComparison is both players' points in a round whose market has closed. The points
+// are the round's own, bonuses included, before the round's weight.
+type RoundComparison struct {
+	Round   int `json:"round"`
+	PointsA int `json:"points_a"`
+	PointsB int `json:"points_b"`
+	// WinnerID is the player who scored more in the round, nil on a draw or before any
+	// result.
+	WinnerID *uuid.UUID        `json:"winner_id"`
+	Matches  []MatchComparison `json:"matches"`
+}
+
+// Comparison is a head-to-head between two players over the closed rounds of a bolão.
+type Comparison struct {
+	UserA       models.User       `json:"user_a"`
+	UserB       models.User       `json:"user_b"`
+	PointsA     int               `json:"points_a"`
+	PointsB     int               `json:"points_b"`
+	RoundsWonA  int               `json:"rounds_won_a"`
+	RoundsWonB  int               `json:"rounds_won_b"`
+	RoundsDrawn int               `json:"rounds_drawn"`
+	Rounds      []RoundComparison `json:"rounds"`
+	// Divergences are the matches of Rounds where the two called different outcomes.
+	Divergences []MatchComparison `json:"divergences"`
+}
+
+// compare puts a and b side by side over every round whose market has closed — the rounds
+// whose predictions GetByUserAndRound shows to other players.
+func (d *bolaoSnapshot) compare(a, b models.User, now time.Time) *Comparison {
+	rounds := make([]int, 0, len(d.byRound))
+	for round := range d.byRound {
+		rounds = append(rounds, round)
+	}
+	sort.Ints(rounds)
+
+	c := &Comparison{UserA: a, UserB: b, Rounds: []RoundComparison{}, Divergences: []MatchComparison{}}
+	for _, round := range rounds {
+		matches := d.byRound[round]
+		if !RoundMarketClosed(matches, now) {
+			continue
+		}
+		counted, awardRoundTotalBonus := d.countedMatches(round, finalResults)
+		explain := func(u models.User) (RoundBreakdown, map[uuid.UUID]int) {
+			rules := d.rulesets.ForRound(round)
+			rb := explainParticipantRound(rules, counted, d.predictions.of(u.ID), d.jokers.match(u.ID, round), d.favorites.forRound(u.ID, matches, now),
+				d.questions.items(u.ID, round), awardRoundTotalBonus, now)
+			points := make(map[uuid.UUID]int, len(counted))
+			for i, mwr := range counted {
+				points[mwr.m.ID] = sumItems(rb.Matches[i])
+			}
+			return rb, points
+		}
+		breakdownA, pointsA := explain(a)
+		breakdownB, pointsB := explain(b)
+
+		rc := RoundComparison{Round: round, Matches: make([]MatchComparison, 0, len(matches))}
+		if len(counted) > 0 {
+			rc.PointsA, rc.PointsB = breakdownA.Points, breakdownB.Points
+			switch {
+			case rc.PointsA > rc.PointsB:
+				rc.WinnerID = &a.ID
+				c.RoundsWonA++
+			case rc.PointsB > rc.PointsA:
+				rc.WinnerID = &b.ID
+				c.RoundsWonB++
+			default:
+				c.RoundsDrawn++
+			}
+			c.PointsA += rc.PointsA
+			c.PointsB += rc.PointsB
+		}
+
+		for _, m := range matches {
+			mc := MatchComparison{
+				MatchID:   m.ID,
+				Round:     round,
+				HomeTeam:  m.HomeTeam,
+				AwayTeam:  m.AwayTeam,
+				HomeGoals: m.HomeGoals,
+				AwayGoals: m.AwayGoals,
+				UserA:     d.comparedPrediction(a.ID, m, pointsA, now),
+				UserB:     d.comparedPrediction(b.ID, m, pointsB, now),
+			}
+			mc.Diverged = winnerSide(mc.UserA.Home, mc.UserA.Away) != winnerSide(mc.UserB.Home, mc.UserB.Away)
+			rc.Matches = append(rc.Matches, mc)
+			if mc.Diverged {
+				c.Divergences = append(c.Divergences, mc)
+			}
+		}
+		c.Rounds = append(c.Rounds, rc)
+	}
+	return c
+}
+
+// comparedPrediction is userID's effective prediction for m, a match whose market has
+// closed, with what it earned when m is in points.
+func (d *bolaoSnapshot) comparedPrediction(userID uuid.UUID, m models.Match, points map[uuid.UUID]int, now time.Time) ComparedPrediction {
+	p, has := d.predictions.of(userID)(m.ID)
+	home, away, _ := EffectivePrediction(m, p.Home, p.Away, has, now)
+	cp := ComparedPrediction{Home: home, Away: away, Missing: !has, Joker: d.jokers.match(userID, m.Round) == m.ID}
+	if pts, ok := points[m.ID]; ok {
+		cp.Points = &pts
+	}
+	return cp
+}
+
+// Compare returns the head-to-head between two participants of the bolão, or
+// ErrNotParticipant when either isn't one.
+func (s *ClassificationService) Compare(ctx context.Context, bolaoID, userA, userB uuid.UUID) (*Comparison, error) {
+	d, err := s.loadSnapshot(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	var a, b *models.User
+	for i := range d.participants {
+		switch d.participants[i].ID {
+		case userA:
+			a = &d.participants[i].User
+		case userB:
+			b = &d.participants[i].User
+		}
+	}
+	if a == nil || b == nil {
+		return nil, ErrNotParticipant
+	}
+	return d.compare(*a, *b, time.Now()), nil
+}
@@ -0,0 +1,59 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestCompare(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	played := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	other := exportMatch("Palmeiras", "Santos", 0, 0, closed)
+	pending := models.Match{ID: uuid.New(), Round: 2, HomeTeam: "Bahia", AwayTeam: "Remo", MarketClosesAt: closed}
+	open := models.Match{ID: uuid.New(), Round: 3, HomeTeam: "Grêmio", AwayTeam: "Inter", MarketClosesAt: timePtr(testNow.Add(time.Hour))}
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	d := testSnapshot([]models.User{ana, bruno}, []models.Match{played, other, pending, open}, []models.Prediction{
+		// Bruno never sent the first match: 0×0, a draw against Ana's home win.
+		testPrediction(ana, played, 2, 1),
+		testPrediction(ana, other, 1, 1), testPrediction(bruno, other, 0, 0),
+		testPrediction(ana, pending, 1, 0), testPrediction(bruno, pending, 2, 0),
+		testPrediction(ana, open, 0, 3), testPrediction(bruno, open, 3, 0),
+	})
+
+	c := d.compare(ana, bruno, testNow)
+	if len(c.Rounds) != 2 {
+		t.Fatalf("%d rounds compared, want 2 (round 3 is still open)", len(c.Rounds))
+	}
+
+	r1 := c.Rounds[0]
+	scores, _ := d.scoreRound(1, finalResults, testNow)
+	if r1.PointsA != scores[ana.ID].points || r1.PointsB != scores[bruno.ID].points {
+		t.Errorf("round 1 points = %d×%d, want the standings' %d×%d", r1.PointsA, r1.PointsB, scores[ana.ID].points, scores[bruno.ID].points)
+	}
+	if r1.WinnerID == nil || *r1.WinnerID != ana.ID {
+		t.Errorf("round 1 winner = %v, want Ana", r1.WinnerID)
+	}
+	first := r1.Matches[0]
+	if !first.UserB.Missing || first.UserB.Home != 0 || first.UserB.Away != 0 {
+		t.Errorf("Bruno's first match = %+v, want a missing 0×0", first.UserB)
+	}
+	if want := CalculateMatchPoints(defaultRules, 2, 1, 2, 1); first.UserA.Points == nil || *first.UserA.Points != want {
+		t.Errorf("Ana's first match points = %v, want %d", first.UserA.Points, want)
+	}
+
+	r2 := c.Rounds[1]
+	if r2.WinnerID != nil || r2.Matches[0].UserA.Points != nil {
+		t.Errorf("round 2 without results = %+v, want no winner nor points", r2)
+	}
+	if c.RoundsWonA != 1 || c.RoundsWonB != 0 || c.RoundsDrawn != 0 {
+		t.Errorf("rounds won = %d/%d/%d, want 1/0/0", c.RoundsWonA, c.RoundsWonB, c.RoundsDrawn)
+	}
+
+	// Only the first match splits them: both called a draw, then a home win.
+	if len(c.Divergences) != 1 || c.Divergences[0].MatchID != played.ID {
+		t.Errorf("divergences = %+v, want only %s x %s", c.Divergences, played.HomeTeam, played.AwayTeam)
+	}
+}
@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"sort"
+)
+
+var ErrInvalidDropWorstRounds = errors.New("número de rodadas descartadas inválido")
+
+// ValidateDropWorstRounds accepts 0 (keep every round) or a positive count.
+func ValidateDropWorstRounds(n int) error {
+	if n < 0 {
+		return ErrInvalidDropWorstRounds
+	}
+	return nil
+}
+
+// worstRounds picks the n rounds of roundPoints to discard (SCORING.md §3.6): the lowest
+// scores, the earlier round first among equal ones. At least one round always counts, so
+// early in the season the rule can't wipe a total out. The result is sorted by round.
+func worstRounds(roundPoints map[int]int, n int) []int {
+	n = min(n, len(roundPoints)-1)
+	if n <= 0 {
+		return nil
+	}
+	rounds := make([]int, 0, len(roundPoints))
+	for round := range roundPoints {
+		rounds = append(rounds, round)
+	}
+	sort.Slice(rounds, func(i, j int) bool {
+		if roundPoints[rounds[i]] != roundPoints[rounds[j]] {
+			return roundPoints[rounds[i]] < roundPoints[rounds[j]]
+		}
+		return rounds[i] < rounds[j]
+	})
+	discarded := rounds[:n]
+	slices.Sort(discarded)
+	return discarded
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+)
+
+func TestWorstRounds(t *testing.T) {
+	points := map[int]int{1: 20, 2: 0, 3: 35, 4: 0, 5: 12}
+	tests := []struct {
+		n    int
+		want []int
+	}{
+		{0, nil},
+		{1, []int{2}}, // the earlier of the two zeros
+		{2, []int{2, 4}},
+		{3, []int{2, 4, 5}},
+		{9, []int{1, 2, 4, 5}}, // one round always counts
+	}
+	for _, tt := range tests {
+		if got := worstRounds(points, tt.n); !slices.Equal(got, tt.want) {
+			t.Errorf("worstRounds(n=%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+	if got := worstRounds(map[int]int{1: 10}, 1); got != nil {
+		t.Errorf("single round discarded: %v", got)
+	}
+	if err := ValidateDropWorstRounds(-1); !errors.Is(err, ErrInvalidDropWorstRounds) {
+		t.Errorf("negative count = %v, want ErrInvalidDropWorstRounds", err)
+	}
+}
+
+// Ana misses round 2 while travelling and is filled in with 0×0; dropping her worst round
+// puts her back in front.
+func TestStandingsDropWorstRounds(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	first := exportMatch("Flamengo", "Vasco", 3, 1, closed)
+	second := exportMatch("Palmeiras", "Santos", 2, 1, closed)
+	second.Round = 2
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	d := testSnapshot([]models.User{ana, bruno}, []models.Match{first, second}, []models.Prediction{
+		testPrediction(ana, first, 3, 1),
+		testPrediction(bruno, first, 2, 1), testPrediction(bruno, second, 2, 1),
+	})
+
+	gross := d.standings(d.maxRound, finalResults, testNow)
+	if gross[0].ID != bruno.ID {
+		t.Fatalf("without the rule the leader is %s, want Bruno", gross[0].DisplayName)
+	}
+
+	d.settings.DropWorstRounds = 1
+	net := d.standings(d.maxRound, finalResults, testNow)
+	leader := net[0]
+	if leader.ID != ana.ID {
+		t.Fatalf("with the rule the leader is %s, want Ana", leader.DisplayName)
+	}
+	if !slices.Equal(leader.DiscardedRounds, []int{2}) {
+		t.Errorf("Ana's discarded rounds = %v, want [2]", leader.DiscardedRounds)
+	}
+	if leader.GrossPoints != gross[1].TotalPoints {
+		t.Errorf("Ana's gross points = %d, want her full total %d", leader.GrossPoints, gross[1].TotalPoints)
+	}
+	if leader.TotalPoints != d.roundTable(1, finalResults, testNow)[0].TotalPoints {
+		t.Errorf("Ana's net points = %d, want round 1 only", leader.TotalPoints)
+	}
+}
@@ -7,9 +7,58 @@ import (
 	"github.com/google/uuid"
 )
 
-// Aliases (=), not defined types: []PredEntry is the identical type to the anonymous
-// struct CalculateRoundPoints already takes, so naming them changes no signature.
-type PredEntry = struct{ PredHome, PredAway int }
+// PredEntry is one effective prediction as CalculateRoundPoints sees it. Joker marks the
+// match the participant picked as the round's coringa, whose match points are multiplied.
+type PredEntry struct {
+	PredHome, PredAway int
+	Joker              bool
+	// Shares is how the group split on the match's outcome, for the underdog bonus; nil
+	// when it doesn't apply.
+	Shares *models.OutcomeShares
+	// FavoriteTeam is the player's favorite team when the match is one of its games, ""
+	// otherwise (see FavoriteTeamIndex).
+	FavoriteTeam string
+	// Advances is the side the prediction sends through a knockout match, and Qualifier
+	// the side that went through; Qualifier is "" until the match is decided (see
+	// knockoutSides).
+	Advances, Qualifier string
+}
+
+// storedPrediction is a prediction as the scoring reads it back.
+type storedPrediction struct {
+	Home, Away int
+	// Advances is the side picked to go through a knockout match (SCORING.md §2.7), "" for
+	// none.
+	Advances string
+}
+
+func stored(p models.Prediction) storedPrediction {
+	sp := storedPrediction{Home: p.HomeGoals, Away: p.AwayGoals}
+	if p.Advances != nil {
+		sp.Advances = *p.Advances
+	}
+	return sp
+}
+
+// predictionIndex is user → match → stored prediction, as indexPredictions builds it.
+type predictionIndex map[uuid.UUID]map[uuid.UUID]storedPrediction
+
+// predictionLookup reports a participant's stored prediction for a match, with has=false
+// when they did not submit one.
+type predictionLookup func(matchID uuid.UUID) (p storedPrediction, has bool)
+
+// of is the lookup of userID's predictions.
+func (idx predictionIndex) of(userID uuid.UUID) predictionLookup {
+	byMatch := idx[userID]
+	return func(matchID uuid.UUID) (storedPrediction, bool) {
+		// p is the zero value when !has; EffectivePredEntry ignores it then.
+		p, has := byMatch[matchID]
+		return p, has
+	}
+}
+
+// MatchScore is an alias (=), not a defined type, so the anonymous struct literals the
+// scoring tests pass to CalculateRoundPoints still type-check.
 type MatchScore = struct{ HomeGoals, AwayGoals int }
 
 // Missing prediction while the market is still open. CalculateRoundPoints skips it.
@@ -21,6 +70,17 @@ func MarketClosed(m models.Match, now time.Time) bool {
 	return m.MarketClosesAt != nil && now.After(*m.MarketClosesAt)
 }
 
+// RoundMarketClosed reports whether every match of the round has a closing time in the
+// past — the condition for showing one player's predictions to the others.
+func RoundMarketClosed(matches []models.Match, now time.Time) bool {
+	for _, m := range matches {
+		if m.MarketClosesAt == nil || now.Before(*m.MarketClosesAt) {
+			return false
+		}
+	}
+	return true
+}
+
 // counts == false means ignore the match entirely: no prediction, market still open.
 func EffectivePrediction(m models.Match, predHome, predAway int, hasPred bool, now time.Time) (home, away int, counts bool) {
 	if hasPred {
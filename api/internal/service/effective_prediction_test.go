@@ -96,7 +96,7 @@ func TestNoShowMatchPoints(t *testing.T) {
 		{2, 1, 0},
 	}
 	for _, tt := range tests {
-		if got := CalculateMatchPoints(0, 0, tt.realHome, tt.realAway); got != tt.want {
+		if got := CalculateMatchPoints(defaultRules, 0, 0, tt.realHome, tt.realAway); got != tt.want {
 			t.Errorf("0x0 against %d-%d = %d, want %d", tt.realHome, tt.realAway, got, tt.want)
 		}
 	}
@@ -113,7 +113,7 @@ func TestNoShowRoundScoring(t *testing.T) {
 	for _, m := range closedMatches {
 		preds = append(preds, EffectivePredEntry(m, 0, 0, false, testNow))
 	}
-	points, exact, correct := CalculateRoundPoints(preds, results, true)
+	points, exact, correct := CalculateRoundPoints(defaultRules, preds, results, true)
 	// 18 + 3. No round-total bonus (0 predicted vs 1 actual) and a single exact-score
 	// type, which is worth 0.
 	if points != 21 || exact != 1 || correct != 1 {
@@ -125,7 +125,7 @@ func TestNoShowRoundScoring(t *testing.T) {
 	for _, m := range openMatches {
 		preds = append(preds, EffectivePredEntry(m, 0, 0, false, testNow))
 	}
-	points, exact, correct = CalculateRoundPoints(preds, results, true)
+	points, exact, correct = CalculateRoundPoints(defaultRules, preds, results, true)
 	if points != 0 || exact != 0 || correct != 0 {
 		t.Errorf("open round with no predictions = (%d,%d,%d), want (0,0,0)", points, exact, correct)
 	}
@@ -140,7 +140,7 @@ func TestRoundTotalBonusNeedsAtLeastOnePrediction(t *testing.T) {
 		{PredHome: noPredSentinel, PredAway: noPredSentinel},
 	}
 	results := []MatchScore{{HomeGoals: 0, AwayGoals: 0}, {HomeGoals: 0, AwayGoals: 0}}
-	if points, _, _ := CalculateRoundPoints(preds, results, true); points != 0 {
+	if points, _, _ := CalculateRoundPoints(defaultRules, preds, results, true); points != 0 {
 		t.Errorf("total no-show on an all-0-0 round = %d points, want 0", points)
 	}
 }
@@ -0,0 +1,310 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// bestExactGoals caps the scores bestExactScore tries: past it nothing scores more.
+const bestExactGoals = 9
+
+// bestExactScore is the score whose exact hit pays the most under rules, the fewest goals
+// among equals.
+func bestExactScore(rules models.ScoringRuleset) storedPrediction {
+	best, bestPoints := storedPrediction{}, -1
+	for total := 0; total <= 2*bestExactGoals; total++ {
+		for home := max(0, total-bestExactGoals); home <= min(total, bestExactGoals); home++ {
+			if p := CalculateMatchPoints(rules, home, total-home, home, total-home); p > bestPoints {
+				best, bestPoints = storedPrediction{Home: home, Away: total - home}, p
+			}
+		}
+	}
+	return best
+}
+
+// EliminationEntry is a participant's reach in the title race.
+type EliminationEntry struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	Position    int       `json:"position"`
+	Points      int       `json:"points"`
+	// MaxPoints is the most the participant can end the season with.
+	MaxPoints int `json:"max_points"`
+	// Eliminated is set when MaxPoints can't reach the leader's current total.
+	Eliminated bool `json:"eliminated"`
+}
+
+// EliminationReport is the deterministic side of the title race: who can still catch the
+// leader, and what the leader needs to be out of reach.
+type EliminationReport struct {
+	RemainingMatches int `json:"remaining_matches"`
+	// LeaderID is the player in first place now, nil when the bolão has no participants.
+	LeaderID *uuid.UUID `json:"leader_id"`
+	// ClinchPoints is the magic number: how many more points the leader needs to finish
+	// above everyone else's MaxPoints. 0 once the title is won.
+	ClinchPoints int  `json:"clinch_points"`
+	Clinched     bool `json:"clinched"`
+	// ClinchAlone is set when the leader can still score ClinchPoints; otherwise the
+	// title also depends on the chasers dropping points.
+	ClinchAlone bool               `json:"clinch_alone"`
+	Players     []EliminationEntry `json:"players"`
+}
+
+// openBonuses is what the bonus questions and season-long bets still unresolved can add:
+// the points of each one a participant answered, or can still answer.
+type openBonuses struct {
+	// Rounds maps user → round → the round's open bonus questions.
+	Rounds map[uuid.UUID]map[int]int
+	// Outrights maps user → the open season-long bets.
+	Outrights map[uuid.UUID]int
+}
+
+// findOpenBonuses adds up the bonus questions without a result and the season-long bets
+// without an outcome each participant answered, or can still answer before the close.
+func findOpenBonuses(participants []models.ParticipantView, byRound map[int][]models.Match,
+	questions []models.RoundQuestion, answers []models.RoundQuestionAnswer,
+	outrights []models.OutrightQuestion, outrightAnswers []models.OutrightAnswer, now time.Time) openBonuses {
+	pb := openBonuses{Rounds: make(map[uuid.UUID]map[int]int), Outrights: make(map[uuid.UUID]int)}
+	answered := make(map[uuid.UUID]map[uuid.UUID]bool)
+	for _, a := range answers {
+		if answered[a.QuestionID] == nil {
+			answered[a.QuestionID] = make(map[uuid.UUID]bool)
+		}
+		answered[a.QuestionID][a.UserID] = true
+	}
+	for _, q := range questions {
+		if q.Result != nil {
+			continue
+		}
+		open := !RoundMarketClosed(byRound[q.Round], now)
+		for _, p := range participants {
+			if !open && !answered[q.ID][p.ID] {
+				continue
+			}
+			if pb.Rounds[p.ID] == nil {
+				pb.Rounds[p.ID] = make(map[int]int)
+			}
+			pb.Rounds[p.ID][q.Round] += q.Points
+		}
+	}
+
+	picks := make(map[uuid.UUID]map[uuid.UUID]int)
+	for _, a := range outrightAnswers {
+		if picks[a.QuestionID] == nil {
+			picks[a.QuestionID] = make(map[uuid.UUID]int)
+		}
+		picks[a.QuestionID][a.UserID] = len(a.Teams)
+	}
+	for _, q := range outrights {
+		if q.Outcome != nil {
+			continue
+		}
+		for _, p := range participants {
+			n, ok := picks[q.ID][p.ID]
+			if now.Before(q.ClosesAt) {
+				n, ok = q.Picks, true
+			}
+			if ok {
+				pb.Outrights[p.ID] += n * q.PointsPerHit
+			}
+		}
+	}
+	return pb
+}
+
+// bestCaseRound is userID's round score if every match of the round still without a result
+// ends as they predicted: the stored prediction, the 0×0 a missing one becomes at the close,
+// or, while the market is open and they haven't bet, best. Until the coringa locks — its
+// match closes — it can still be moved to any open match, so it goes where it pays the most.
+// An open match has no outcome split yet, so it gets the one that pays the underdog bonus
+// the most: they alone called the outcome.
+func (d *bolaoSnapshot) bestCaseRound(round int, userID uuid.UUID, best storedPrediction, now time.Time) roundScore {
+	stored := d.predictions.of(userID)
+	lookup := func(matchID uuid.UUID) (storedPrediction, bool) {
+		if p, ok := stored(matchID); ok {
+			return p, true
+		}
+		for _, m := range d.byRound[round] {
+			if m.ID == matchID {
+				if _, final := finalResults(m); !final && !MarketClosed(m, now) {
+					return best, true
+				}
+			}
+		}
+		return storedPrediction{}, false
+	}
+
+	rules := d.rulesets.ForRound(round)
+	matches := d.byRound[round]
+	counted := make([]matchWithResult, 0, len(matches))
+	for _, m := range matches {
+		mwr := matchWithResult{m: m, shares: d.shares.of(m.ID), tie: d.ties[m.ID]}
+		if r, ok := finalResults(m); ok {
+			mwr.home, mwr.away = r.home, r.away
+		} else {
+			p, has := lookup(m.ID)
+			mwr.home, mwr.away, _ = EffectivePrediction(m, p.Home, p.Away, has, now)
+			if mwr.shares == nil && rules.UnderdogMultiplier > 1 && !MarketClosed(m, now) {
+				mwr.shares = loneCallShares(m.ID, matchResult(mwr.home, mwr.away), len(d.participants))
+			}
+		}
+		counted = append(counted, mwr)
+	}
+	joker := d.jokers.match(userID, round)
+	jokers := []uuid.UUID{joker}
+	if !jokerLocked(matches, joker, now) {
+		for _, m := range matches {
+			if m.ID != joker && !MarketClosed(m, now) {
+				jokers = append(jokers, m.ID)
+			}
+		}
+	}
+	favorite := d.favorites.forRound(userID, matches, now)
+	questions := d.questions.items(userID, round)
+	var top roundScore
+	for i, j := range jokers {
+		if rs := scoreParticipantRound(rules, counted, lookup, j, favorite, questions, true, now); i == 0 || rs.points > top.points {
+			top = rs
+		}
+	}
+	return top
+}
+
+// loneCallShares is the split of participants in which only one called outcome, the rest
+// another one.
+func loneCallShares(matchID uuid.UUID, outcome string, participants int) *models.OutcomeShares {
+	s := &models.OutcomeShares{MatchID: matchID}
+	others := max(participants-1, 0)
+	switch outcome {
+	case "home":
+		s.Home, s.Away = 1, others
+	case "draw":
+		s.Draw, s.Home = 1, others
+	default:
+		s.Away, s.Home = 1, others
+	}
+	return s
+}
+
+// jokerLocked reports whether the coringa picked among matches can no longer move: its
+// match has closed. No coringa yet is never locked.
+func jokerLocked(matches []models.Match, joker uuid.UUID, now time.Time) bool {
+	for _, m := range matches {
+		if m.ID == joker {
+			return MarketClosed(m, now)
+		}
+	}
+	return false
+}
+
+// elimination works out each participant's MaxPoints — the current standings with every
+// match still without a result ending as they predicted, and every open bonus paying — and
+// from it who is out of the title race and the leader's magic number. Rounds are weighted
+// and the worst ones discarded as in the standings.
+func (d *bolaoSnapshot) elimination(pending openBonuses, now time.Time) *EliminationReport {
+	report := &EliminationReport{Players: []EliminationEntry{}}
+	open := make(map[int]bool)
+	for round, matches := range d.byRound {
+		for _, m := range matches {
+			if _, ok := finalResults(m); !ok {
+				open[round] = true
+				report.RemainingMatches++
+			}
+		}
+	}
+
+	bestByVersion := make(map[int]storedPrediction)
+	maxStandings := *d
+	maxStandings.outrightPoints = make(map[uuid.UUID]int, len(d.participants))
+	for _, p := range d.participants {
+		maxStandings.outrightPoints[p.ID] = d.outrightPoints[p.ID] + pending.Outrights[p.ID]
+	}
+	maxTable := maxStandings.aggregate(d.maxRound, func(round int) (map[uuid.UUID]roundScore, bool) {
+		var scores map[uuid.UUID]roundScore
+		ok := true
+		if !open[round] {
+			scores, ok = d.scoreRound(round, finalResults, now)
+		} else {
+			rules := d.rulesets.ForRound(round)
+			best, known := bestByVersion[rules.Version]
+			if !known {
+				best = bestExactScore(rules)
+				bestByVersion[rules.Version] = best
+			}
+			scores = make(map[uuid.UUID]roundScore, len(d.participants))
+			for _, p := range d.participants {
+				scores[p.ID] = d.bestCaseRound(round, p.ID, best, now)
+			}
+		}
+		for _, p := range d.participants {
+			if extra := pending.Rounds[p.ID][round]; extra > 0 && scores != nil {
+				rs := scores[p.ID]
+				rs.points += extra
+				scores[p.ID] = rs
+			}
+		}
+		return scores, ok
+	})
+	maxPoints := make(map[uuid.UUID]int, len(maxTable))
+	for _, u := range maxTable {
+		maxPoints[u.ID] = u.TotalPoints
+	}
+
+	current := d.standings(d.maxRound, finalResults, now)
+	if len(current) == 0 {
+		return report
+	}
+	leader := current[0]
+	report.LeaderID = &leader.ID
+	chase := 0
+	for _, u := range current {
+		e := EliminationEntry{
+			UserID:      u.ID,
+			DisplayName: u.DisplayName,
+			Position:    u.Position,
+			Points:      u.TotalPoints,
+			MaxPoints:   maxPoints[u.ID],
+		}
+		if u.ID != leader.ID {
+			e.Eliminated = e.MaxPoints < leader.TotalPoints
+			chase = max(chase, e.MaxPoints)
+		}
+		report.Players = append(report.Players, e)
+	}
+	// Finishing above the chasers' best, not level with it: the tiebreakers could still go
+	// either way.
+	report.ClinchPoints = max(0, chase-leader.TotalPoints+1)
+	report.Clinched = report.ClinchPoints == 0
+	report.ClinchAlone = leader.TotalPoints+report.ClinchPoints <= maxPoints[leader.ID]
+	return report
+}
+
+// GetElimination returns who can still win the bolão, each participant's maximum and the
+// leader's magic number, over the matches still without a result.
+func (s *ClassificationService) GetElimination(ctx context.Context, bolaoID uuid.UUID) (*EliminationReport, error) {
+	d, err := s.loadSnapshot(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	questions, err := s.questionRepo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := s.questionRepo.ListAnswersByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	outrights, err := s.outrightRepo.ListQuestions(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	outrightAnswers, err := s.outrightRepo.ListAnswersByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return d.elimination(findOpenBonuses(d.participants, d.byRound, questions, answers, outrights, outrightAnswers, now), now), nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestBestExactScore(t *testing.T) {
+	best := bestExactScore(defaultRules)
+	points := CalculateMatchPoints(defaultRules, best.Home, best.Away, best.Home, best.Away)
+	for _, s := range [][2]int{{0, 0}, {1, 0}, {2, 2}, {3, 1}, {5, 4}} {
+		if p := CalculateMatchPoints(defaultRules, s[0], s[1], s[0], s[1]); p > points {
+			t.Errorf("%d×%d pays %d, more than the best exact score %+v (%d)", s[0], s[1], p, best, points)
+		}
+	}
+}
+
+// Ana hit all three matches of round 1. Round 2 has one match left: Bruno bet on it, Caio
+// didn't.
+func eliminationFixture(r2Closes time.Time) (d *bolaoSnapshot, ana, bruno, caio models.User) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	m1 := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	m2 := exportMatch("Bahia", "Remo", 1, 1, closed)
+	m3 := exportMatch("Santos", "Grêmio", 0, 2, closed)
+	r2 := models.Match{ID: uuid.New(), Round: 2, HomeTeam: "Palmeiras", AwayTeam: "Sport", MarketClosesAt: &r2Closes}
+	ana, bruno, caio = exportUser("Ana"), exportUser("Bruno"), exportUser("Caio")
+	d = testSnapshot([]models.User{ana, bruno, caio}, []models.Match{m1, m2, m3, r2}, []models.Prediction{
+		testPrediction(ana, m1, 2, 1), testPrediction(ana, m2, 1, 1), testPrediction(ana, m3, 0, 2),
+		testPrediction(bruno, m1, 0, 1), testPrediction(bruno, m2, 2, 0), testPrediction(bruno, m3, 1, 0),
+		testPrediction(caio, m1, 0, 1), testPrediction(caio, m2, 2, 0), testPrediction(caio, m3, 1, 0),
+		testPrediction(ana, r2, 0, 0), testPrediction(bruno, r2, 1, 0),
+	})
+	return d, ana, bruno, caio
+}
+
+func TestElimination(t *testing.T) {
+	d, ana, bruno, caio := eliminationFixture(testNow.Add(-time.Minute))
+	report := d.elimination(openBonuses{}, testNow)
+	entries := func(r *EliminationReport) map[uuid.UUID]EliminationEntry {
+		m := map[uuid.UUID]EliminationEntry{}
+		for _, e := range r.Players {
+			m[e.UserID] = e
+		}
+		return m
+	}
+	e := entries(report)
+	if report.RemainingMatches != 1 || report.LeaderID == nil || *report.LeaderID != ana.ID {
+		t.Fatalf("report = %+v, want one match left and Ana leading", report)
+	}
+	if want := e[bruno.ID].Points + d.bestCaseRound(2, bruno.ID, storedPrediction{}, testNow).points; e[bruno.ID].MaxPoints != want {
+		t.Errorf("Bruno's max = %d, want %d with his 1×0 coming true", e[bruno.ID].MaxPoints, want)
+	}
+	if !e[bruno.ID].Eliminated || !e[caio.ID].Eliminated || e[ana.ID].Eliminated {
+		t.Errorf("players = %+v, want Bruno and Caio out", report.Players)
+	}
+	if !report.Clinched || report.ClinchPoints != 0 {
+		t.Errorf("clinch = %d, want Ana already champion", report.ClinchPoints)
+	}
+
+	// With the market still open Caio can still bet, on the best-paying score.
+	open, _, _, openCaio := eliminationFixture(testNow.Add(time.Hour))
+	if got, closedMax := entries(open.elimination(openBonuses{}, testNow))[openCaio.ID].MaxPoints, e[caio.ID].MaxPoints; got <= closedMax {
+		t.Errorf("Caio's max with the market open = %d, want more than the %d of his 0×0", got, closedMax)
+	}
+
+	// An open season-long bet keeps Bruno in the race.
+	report = d.elimination(openBonuses{Outrights: map[uuid.UUID]int{bruno.ID: 200}}, testNow)
+	e = entries(report)
+	if e[bruno.ID].Eliminated || report.Clinched || report.ClinchPoints != e[bruno.ID].MaxPoints-e[ana.ID].Points+1 {
+		t.Errorf("report = %+v, want Bruno alive and Ana needing to pass his max", report)
+	}
+	if report.ClinchAlone != (e[ana.ID].Points+report.ClinchPoints <= e[ana.ID].MaxPoints) {
+		t.Errorf("clinch alone = %v with max %d", report.ClinchAlone, e[ana.ID].MaxPoints)
+	}
+}
+
+// Round 2 is open and Bruno's coringa, on one of its matches, can still move. Ana leads by
+// a point over what Bruno could reach without it: the coringa alone keeps him alive.
+func TestEliminationMovableJoker(t *testing.T) {
+	closed, open := timePtr(testNow.Add(-time.Hour)), timePtr(testNow.Add(time.Hour))
+	m1 := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	a := models.Match{ID: uuid.New(), Round: 2, HomeTeam: "Palmeiras", AwayTeam: "Sport", MarketClosesAt: open}
+	b := models.Match{ID: uuid.New(), Round: 2, HomeTeam: "Bahia", AwayTeam: "Remo", MarketClosesAt: open}
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	d := testSnapshot([]models.User{ana, bruno}, []models.Match{m1, a, b}, []models.Prediction{
+		testPrediction(ana, m1, 0, 0), testPrediction(bruno, m1, 0, 0),
+		testPrediction(ana, a, 0, 0), testPrediction(ana, b, 0, 0),
+		testPrediction(bruno, a, 3, 1), testPrediction(bruno, b, 1, 1),
+	})
+	entry := func(d *bolaoSnapshot, userID uuid.UUID) EliminationEntry {
+		for _, e := range d.elimination(openBonuses{}, testNow).Players {
+			if e.UserID == userID {
+				return e
+			}
+		}
+		t.Fatalf("no entry for %s", userID)
+		return EliminationEntry{}
+	}
+
+	noJoker := *d
+	flat := defaultRules
+	flat.JokerMultiplier = 1
+	noJoker.rulesets = RulesetHistory{flat}
+	without := entry(&noJoker, bruno.ID).MaxPoints
+	d.outrightPoints = map[uuid.UUID]int{ana.ID: without + 1}
+	noJoker.outrightPoints = d.outrightPoints
+	if e := entry(&noJoker, bruno.ID); !e.Eliminated {
+		t.Fatalf("Bruno without a coringa = %+v, want him out", e)
+	}
+
+	none := entry(d, bruno.ID)
+	if none.Eliminated || none.MaxPoints <= without {
+		t.Errorf("Bruno = %+v, want the coringa he can still pick to keep him alive over %d", none, without)
+	}
+	for _, m := range []models.Match{a, b} {
+		d.jokers = indexJokers([]models.Joker{{UserID: bruno.ID, Round: 2, MatchID: m.ID}})
+		if e := entry(d, bruno.ID); e.MaxPoints != none.MaxPoints {
+			t.Errorf("coringa on %s x %s: max = %d, want %d wherever it sits while it can move", m.HomeTeam, m.AwayTeam, e.MaxPoints, none.MaxPoints)
+		}
+	}
+}
+
+// Round 2's match is still open and the underdog bonus is on. Ana leads by a point over
+// what Bruno could reach without the bonus: only the split in which he alone calls the
+// outcome keeps him alive.
+func TestEliminationOpenUnderdog(t *testing.T) {
+	closed, open := timePtr(testNow.Add(-time.Hour)), timePtr(testNow.Add(time.Hour))
+	m1 := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	m2 := models.Match{ID: uuid.New(), Round: 2, HomeTeam: "Palmeiras", AwayTeam: "Sport", MarketClosesAt: open}
+	ana, bruno, caio := exportUser("Ana"), exportUser("Bruno"), exportUser("Caio")
+	d := testSnapshot([]models.User{ana, bruno, caio}, []models.Match{m1, m2}, []models.Prediction{
+		testPrediction(ana, m1, 2, 1), testPrediction(bruno, m1, 0, 0), testPrediction(caio, m1, 0, 0),
+		testPrediction(ana, m2, 0, 0), testPrediction(bruno, m2, 3, 1), testPrediction(caio, m2, 3, 1),
+	})
+	entry := func(d *bolaoSnapshot, userID uuid.UUID) EliminationEntry {
+		for _, e := range d.elimination(openBonuses{}, testNow).Players {
+			if e.UserID == userID {
+				return e
+			}
+		}
+		t.Fatalf("no entry for %s", userID)
+		return EliminationEntry{}
+	}
+
+	without := entry(d, bruno.ID).MaxPoints
+	d.outrightPoints = map[uuid.UUID]int{ana.ID: without + 1}
+	if e := entry(d, bruno.ID); !e.Eliminated {
+		t.Fatalf("Bruno without the underdog bonus = %+v, want him out", e)
+	}
+
+	underdog := defaultRules
+	underdog.UnderdogMultiplier = 2
+	d.rulesets = RulesetHistory{underdog}
+	e := entry(d, bruno.ID)
+	if e.Eliminated || e.MaxPoints <= without {
+		t.Errorf("Bruno = %+v, want the underdog bonus he can still earn to keep him alive over %d", e, without)
+	}
+}
+
+func TestFindOpenBonuses(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	r1 := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	participants := []models.ParticipantView{{User: ana}, {User: bruno}}
+	result := "Gabigol"
+	questions := []models.RoundQuestion{
+		{ID: uuid.New(), Round: 1, Points: 5},
+		{ID: uuid.New(), Round: 1, Points: 7, Result: &result},
+	}
+	outrights := []models.OutrightQuestion{
+		{ID: uuid.New(), Picks: 4, PointsPerHit: 10, ClosesAt: testNow.Add(-time.Hour)},
+		{ID: uuid.New(), Picks: 1, PointsPerHit: 30, ClosesAt: testNow.Add(time.Hour)},
+	}
+	ob := findOpenBonuses(participants, map[int][]models.Match{1: {r1}},
+		questions, []models.RoundQuestionAnswer{{QuestionID: questions[0].ID, UserID: ana.ID}},
+		outrights, []models.OutrightAnswer{{QuestionID: outrights[0].ID, UserID: bruno.ID, Teams: []string{"Flamengo", "Palmeiras"}}},
+		testNow)
+
+	// The round is closed: only Ana's answer to the open question can still pay.
+	if ob.Rounds[ana.ID][1] != 5 || ob.Rounds[bruno.ID][1] != 0 {
+		t.Errorf("round bonuses = %v, want Ana's 5", ob.Rounds)
+	}
+	// Bruno's two picks of the closed bet, and everyone's pick of the one still open.
+	if ob.Outrights[bruno.ID] != 2*10+30 || ob.Outrights[ana.ID] != 30 {
+		t.Errorf("outright bonuses = %v, want Bruno 50 and Ana 30", ob.Outrights)
+	}
+}
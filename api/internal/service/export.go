@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/csv"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bolao-app/api/internal/models"
@@ -16,17 +17,35 @@ type ExportService struct {
 	bolaoRepo      *repository.BolaoRepository
 	matchRepo      *repository.MatchRepository
 	predictionRepo *repository.PredictionRepository
+	rulesetRepo    *repository.ScoringRulesetRepository
+	jokerRepo      *repository.JokerRepository
+	questionRepo   *repository.RoundQuestionRepository
+	shareRepo      *repository.OutcomeShareRepository
+	userRepo       *repository.UserRepository
+	tieRepo        *repository.TieRepository
 }
 
 func NewExportService(
 	bolaoRepo *repository.BolaoRepository,
 	matchRepo *repository.MatchRepository,
 	predictionRepo *repository.PredictionRepository,
+	rulesetRepo *repository.ScoringRulesetRepository,
+	jokerRepo *repository.JokerRepository,
+	questionRepo *repository.RoundQuestionRepository,
+	shareRepo *repository.OutcomeShareRepository,
+	userRepo *repository.UserRepository,
+	tieRepo *repository.TieRepository,
 ) *ExportService {
 	return &ExportService{
 		bolaoRepo:      bolaoRepo,
 		matchRepo:      matchRepo,
 		predictionRepo: predictionRepo,
+		rulesetRepo:    rulesetRepo,
+		jokerRepo:      jokerRepo,
+		questionRepo:   questionRepo,
+		shareRepo:      shareRepo,
+		userRepo:       userRepo,
+		tieRepo:        tieRepo,
 	}
 }
 
@@ -47,7 +66,42 @@ func (s *ExportService) ExportRoundCSV(ctx context.Context, bolaoID uuid.UUID, r
 		return nil, err
 	}
 
-	return buildCSV([]int{round}, matches, users, predictions, time.Now())
+	rulesets, err := LoadRulesetHistory(ctx, s.rulesetRepo, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	bolao, err := s.bolaoRepo.GetByID(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+
+	jokers, err := s.jokerRepo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.loadQuestions(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	shares, err := LoadOutcomeShares(ctx, s.shareRepo, bolaoID, rulesets, matches, participantIDs(participants), predictions, now)
+	if err != nil {
+		return nil, err
+	}
+
+	favorites, err := LoadFavoriteTeams(ctx, s.userRepo, bolaoID, rulesets)
+	if err != nil {
+		return nil, err
+	}
+
+	ties, err := LoadTies(ctx, s.tieRepo, s.matchRepo, s.predictionRepo, bolaoID, rulesets)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCSV(rulesets, bolao.BolaoSettings, []int{round}, nil, matches, users, predictions, jokers, questions, shares, favorites, ties, now)
 }
 
 func (s *ExportService) ExportAllCSV(ctx context.Context, bolaoID uuid.UUID) ([]byte, error) {
@@ -72,7 +126,54 @@ func (s *ExportService) ExportAllCSV(ctx context.Context, bolaoID uuid.UUID) ([]
 		return nil, err
 	}
 
-	return buildCSV(rounds, allMatches, users, predictions, time.Now())
+	rulesets, err := LoadRulesetHistory(ctx, s.rulesetRepo, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	bolao, err := s.bolaoRepo.GetByID(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+
+	jokers, err := s.jokerRepo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+
+	questions, err := s.loadQuestions(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	shares, err := LoadOutcomeShares(ctx, s.shareRepo, bolaoID, rulesets, allMatches, participantIDs(participants), predictions, now)
+	if err != nil {
+		return nil, err
+	}
+
+	favorites, err := LoadFavoriteTeams(ctx, s.userRepo, bolaoID, rulesets)
+	if err != nil {
+		return nil, err
+	}
+
+	ties, err := LoadTies(ctx, s.tieRepo, s.matchRepo, s.predictionRepo, bolaoID, rulesets)
+	if err != nil {
+		return nil, err
+	}
+
+	return buildCSV(rulesets, bolao.BolaoSettings, rounds, bolao.RoundRanges, allMatches, users, predictions, jokers, questions, shares, favorites, ties, now)
+}
+
+func (s *ExportService) loadQuestions(ctx context.Context, bolaoID uuid.UUID) (questionIndex, error) {
+	questions, err := s.questionRepo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := s.questionRepo.ListAnswersByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	return indexRoundQuestions(questions, answers), nil
 }
 
 func participantUsers(participants []models.ParticipantView) []models.User {
@@ -83,21 +184,49 @@ func participantUsers(participants []models.ParticipantView) []models.User {
 	return users
 }
 
+func participantViews(users []models.User) []models.ParticipantView {
+	participants := make([]models.ParticipantView, 0, len(users))
+	for _, u := range users {
+		participants = append(participants, models.ParticipantView{User: u})
+	}
+	return participants
+}
+
 // indexPredictions groups predictions by user then match for O(1) lookup, avoiding a
 // query per match/round per user (see buildCSV and getRoundClassification below).
-func indexPredictions(predictions []models.Prediction) map[uuid.UUID]map[uuid.UUID]struct{ Home, Away int } {
-	index := make(map[uuid.UUID]map[uuid.UUID]struct{ Home, Away int })
+func indexPredictions(predictions []models.Prediction) predictionIndex {
+	index := make(predictionIndex)
 	for _, p := range predictions {
 		if index[p.UserID] == nil {
-			index[p.UserID] = make(map[uuid.UUID]struct{ Home, Away int })
+			index[p.UserID] = make(map[uuid.UUID]storedPrediction)
 		}
-		index[p.UserID][p.MatchID] = struct{ Home, Away int }{p.HomeGoals, p.AwayGoals}
+		index[p.UserID][p.MatchID] = stored(p)
 	}
 	return index
 }
 
-func buildCSV(rounds []int, matches []models.Match, users []models.User, predictions []models.Prediction, now time.Time) ([]byte, error) {
+func buildCSV(
+	rulesets RulesetHistory,
+	settings models.BolaoSettings,
+	rounds []int,
+	ranges []models.RoundRange,
+	matches []models.Match,
+	users []models.User,
+	predictions []models.Prediction,
+	jokers []models.Joker,
+	questions questionIndex,
+	shares OutcomeShareIndex,
+	favorites FavoriteTeamIndex,
+	ties TieIndex,
+	now time.Time,
+) ([]byte, error) {
 	predIndex := indexPredictions(predictions)
+	jokerIndex := indexJokers(jokers)
+	submissions := firstSubmissions(predictions, matchRounds(matches))
+	matchesByRound := make(map[int][]models.Match)
+	for _, m := range matches {
+		matchesByRound[m.Round] = append(matchesByRound[m.Round], m)
+	}
 
 	var buf bytes.Buffer
 	w := csv.NewWriter(&buf)
@@ -124,13 +253,15 @@ func buildCSV(rounds []int, matches []models.Match, users []models.User, predict
 	_ = w.Write(nil)
 
 	// PALPITES (apenas jogos com resultado)
-	_ = w.Write([]string{"Rodada", "Jogo", "Usuario", "Palpite_Mandante", "Palpite_Visitante", "Pontos"})
+	// Versao_Regras records which version of the rules scored the row (SCORING.md §1.4).
+	_ = w.Write([]string{"Rodada", "Jogo", "Usuario", "Palpite_Mandante", "Palpite_Visitante", "Pontos", "Coringa", "Versao_Regras"})
 	for _, m := range matches {
 		if m.HomeGoals == nil || m.AwayGoals == nil {
 			continue
 		}
 		hg, ag := *m.HomeGoals, *m.AwayGoals
 		jogo := m.HomeTeam + " x " + m.AwayTeam
+		rules := rulesets.ForRound(m.Round)
 
 		for _, u := range users {
 			// Two-value read: without it a real 0-0 prediction is indistinguishable from
@@ -139,10 +270,26 @@ func buildCSV(rounds []int, matches []models.Match, users []models.User, predict
 			stored, has := predIndex[u.ID][m.ID]
 			ph, pa, counts := EffectivePrediction(m, stored.Home, stored.Away, has, now)
 
-			palH, palA, pts := "-", "-", 0
+			joker := jokerIndex.match(u.ID, m.Round) == m.ID
+
+			palH, palA, pts, coringa := "-", "-", 0, ""
 			if counts {
 				palH, palA = strconv.Itoa(ph), strconv.Itoa(pa)
-				pts = CalculateMatchPoints(ph, pa, hg, ag)
+				items := MatchBreakdown(rules, ph, pa, hg, ag)
+				entry := PredEntry{PredHome: ph, PredAway: pa, Shares: shares.of(m.ID)}
+				entry.Advances, entry.Qualifier = knockoutSides(m, ties[m.ID], entry, stored.Advances, predIndex.of(u.ID), now)
+				if team := favorites.forRound(u.ID, matchesByRound[m.Round], now); playsFor(m, team) {
+					entry.FavoriteTeam = team
+				}
+				result := MatchScore{HomeGoals: hg, AwayGoals: ag}
+				items = applyUnderdog(rules, items, entry, result)
+				items = applyFavoriteTeam(rules, items, entry, result)
+				items = applyQualifier(rules, items, entry)
+				if joker {
+					items = applyJoker(rules, items)
+					coringa = "sim"
+				}
+				pts = sumItems(items)
 			}
 			_ = w.Write([]string{
 				strconv.Itoa(m.Round),
@@ -151,34 +298,64 @@ func buildCSV(rounds []int, matches []models.Match, users []models.User, predict
 				palH,
 				palA,
 				strconv.Itoa(pts),
+				coringa,
+				strconv.Itoa(rules.Version),
 			})
 		}
 	}
 	_ = w.Write(nil)
 
 	// CLASSIFICAÇÃO por rodada
-	_ = w.Write([]string{"Rodada", "Posicao", "Usuario", "Pontos", "Placares_Exatos", "Resultados_Corretos"})
-	matchesByRound := make(map[int][]models.Match)
-	for _, m := range matches {
-		matchesByRound[m.Round] = append(matchesByRound[m.Round], m)
-	}
+	// Pontos are the round's own points, which rank it; Pontos_Ponderados are what the round
+	// adds to the general standings after its Peso (SCORING.md §3.5).
+	_ = w.Write([]string{"Rodada", "Posicao", "Usuario", "Pontos", "Placares_Exatos", "Resultados_Corretos", "Versao_Regras", "Peso", "Pontos_Ponderados"})
 	for _, round := range rounds {
-		classification := getRoundClassification(matchesByRound[round], users, predIndex, now)
+		rules := rulesets.ForRound(round)
+		classification := getRoundClassification(rules, RankingFor(settings), matchesByRound[round], users, predIndex, jokerIndex, questions, shares, favorites, ties, submissions, now)
 		if len(classification) == 0 {
 			continue
 		}
-		for i, row := range classification {
+		weight := RoundWeightPercent(settings.RoundWeights, round)
+		for _, row := range classification {
 			_ = w.Write([]string{
 				strconv.Itoa(round),
-				strconv.Itoa(i + 1),
+				strconv.Itoa(row.position),
 				row.displayName,
 				strconv.Itoa(row.points),
 				strconv.Itoa(row.exactScores),
 				strconv.Itoa(row.correctResults),
+				strconv.Itoa(rules.Version),
+				formatWeight(weight),
+				strconv.Itoa(weighRoundPoints(row.points, weight)),
 			})
 		}
 	}
 
+	// The sections below read the bolão the way the JSON endpoints do.
+	d := &bolaoSnapshot{
+		settings:     settings,
+		participants: participantViews(users),
+		rulesets:     rulesets,
+		predictions:  predIndex,
+		submissions:  submissions,
+		jokers:       jokerIndex,
+		questions:    questions,
+		shares:       shares,
+		favorites:    favorites,
+		ties:         ties,
+	}
+	d.setMatches(matches)
+
+	// CLASSIFICAÇÃO por faixa de rodadas (turno, returno...), as GET /classification?range=
+	// returns it: weighted points and rounds won within the range, no season-long bets.
+	if len(ranges) > 0 {
+		writeRangeClassifications(w, ranges, d, now)
+	}
+
+	// ACERTOS por time, as GET /analytics/teams returns it: home and away games apart, the
+	// group's row before the players'.
+	writeTeamAccuracy(w, d.teamAccuracy(now))
+
 	w.Flush()
 	if err := w.Error(); err != nil {
 		return nil, err
@@ -189,17 +366,108 @@ func buildCSV(rounds []int, matches []models.Match, users []models.User, predict
 	return result, nil
 }
 
+// writeRangeClassifications writes the standings of each range with a result in any of
+// its rounds.
+func writeRangeClassifications(w *csv.Writer, ranges []models.RoundRange, d *bolaoSnapshot, now time.Time) {
+	_ = w.Write(nil)
+	_ = w.Write([]string{"Faixa", "Rodadas", "Posicao", "Usuario", "Pontos", "Rodadas_Vencidas", "Placares_Exatos", "Resultados_Corretos"})
+	for _, r := range ranges {
+		standings, played := d.rangeStandings(r, d.maxRound, func(round int) (map[uuid.UUID]roundScore, bool) {
+			return d.scoreRound(round, finalResults, now)
+		})
+		if !played {
+			continue
+		}
+		rounds := strconv.Itoa(r.FromRound) + "-" + strconv.Itoa(r.ToRound)
+		for _, u := range standings {
+			_ = w.Write([]string{
+				r.Name,
+				rounds,
+				strconv.Itoa(u.Position),
+				u.DisplayName,
+				strconv.Itoa(u.TotalPoints),
+				strconv.Itoa(u.RoundsWon),
+				strconv.Itoa(u.ExactScores),
+				strconv.Itoa(u.CorrectResults),
+			})
+		}
+	}
+}
+
+// teamGroupRow names the group's row of the team section, in parentheses so no player's
+// name clashes with it.
+const teamGroupRow = "(grupo)"
+
+// writeTeamAccuracy writes the accuracy on each team's games. Rates are percentages.
+func writeTeamAccuracy(w *csv.Writer, teams []TeamAccuracy) {
+	if len(teams) == 0 {
+		return
+	}
+	_ = w.Write(nil)
+	_ = w.Write([]string{"Time", "Mando", "Usuario", "Palpites", "Resultados_Corretos", "Pct_Resultados", "Placares_Exatos", "Pct_Exatos", "Erro_Medio_Gols"})
+	row := func(team, side, name string, a Accuracy) {
+		_ = w.Write([]string{
+			team,
+			side,
+			name,
+			strconv.Itoa(a.Predictions),
+			strconv.Itoa(a.CorrectResults),
+			formatDecimal(a.CorrectResultRate*100, 1),
+			strconv.Itoa(a.ExactScores),
+			formatDecimal(a.ExactScoreRate*100, 1),
+			formatDecimal(a.AvgGoalError, 2),
+		})
+	}
+	for _, t := range teams {
+		for _, side := range []struct {
+			name string
+			acc  SideAccuracy
+		}{{"Mandante", t.Home}, {"Visitante", t.Away}} {
+			if side.acc.Matches == 0 {
+				continue
+			}
+			row(t.Team, side.name, teamGroupRow, side.acc.Group)
+			for _, p := range side.acc.Players {
+				row(t.Team, side.name, p.DisplayName, p.Accuracy)
+			}
+		}
+	}
+}
+
+// formatDecimal writes v with prec decimals (-1 for as many as it takes) and a decimal
+// comma.
+func formatDecimal(v float64, prec int) string {
+	return strings.Replace(strconv.FormatFloat(v, 'f', prec, 64), ".", ",", 1)
+}
+
+// formatWeight writes a weight in percent as a multiplier with a decimal comma, the way
+// Excel in Portuguese reads it: 150 is "1,5".
+func formatWeight(percent int) string {
+	return formatDecimal(float64(percent)/fullWeight, -1)
+}
+
 type classRow struct {
+	position       int
 	displayName    string
 	points         int
 	exactScores    int
 	correctResults int
 }
 
+// getRoundClassification ranks one round like GetClassificationForRound does. submissions
+// are the first-submission times from firstSubmissions.
 func getRoundClassification(
+	rules models.ScoringRuleset,
+	ranking Ranking,
 	matches []models.Match,
 	users []models.User,
-	predIndex map[uuid.UUID]map[uuid.UUID]struct{ Home, Away int },
+	predIndex predictionIndex,
+	jokers jokerIndex,
+	questions questionIndex,
+	shares OutcomeShareIndex,
+	favorites FavoriteTeamIndex,
+	ties TieIndex,
+	submissions map[uuid.UUID]map[int]time.Time,
 	now time.Time,
 ) []classRow {
 	hasResults := len(matches) > 0
@@ -212,22 +480,34 @@ func getRoundClassification(
 	if !hasResults {
 		return nil
 	}
+	round := matches[0].Round
 
 	type userScore struct {
-		user           models.User
-		points         int
-		exactScores    int
-		correctResults int
+		user     models.User
+		standing Standing
 	}
 	scores := make([]userScore, 0, len(users))
 
 	for _, user := range users {
 		predByMatch := predIndex[user.ID]
+		lookup := predIndex.of(user.ID)
+		favoriteTeam := favorites.forRound(user.ID, matches, now)
 		var predList []PredEntry
 		var matchList []MatchScore
+		missed := 0
 		for _, m := range matches {
 			p, has := predByMatch[m.ID]
-			predList = append(predList, EffectivePredEntry(m, p.Home, p.Away, has, now))
+			entry := EffectivePredEntry(m, p.Home, p.Away, has, now)
+			entry.Joker = jokers.match(user.ID, m.Round) == m.ID
+			entry.Shares = shares.of(m.ID)
+			if playsFor(m, favoriteTeam) {
+				entry.FavoriteTeam = favoriteTeam
+			}
+			entry.Advances, entry.Qualifier = knockoutSides(m, ties[m.ID], entry, p.Advances, lookup, now)
+			if !has && entry.PredHome != noPredSentinel {
+				missed++
+			}
+			predList = append(predList, entry)
 
 			hg, ag := 0, 0
 			if m.HomeGoals != nil {
@@ -238,38 +518,33 @@ func getRoundClassification(
 			}
 			matchList = append(matchList, MatchScore{HomeGoals: hg, AwayGoals: ag})
 		}
-		pts, exact, correct := CalculateRoundPoints(predList, matchList, true)
-		scores = append(scores, userScore{user, pts, exact, correct})
-	}
-
-	// Sort by points desc, then exact, then correct
-	for i := 0; i < len(scores); i++ {
-		for j := i + 1; j < len(scores); j++ {
-			a, b := scores[i], scores[j]
-			swap := false
-			if b.points > a.points {
-				swap = true
-			} else if b.points == a.points && b.exactScores > a.exactScores {
-				swap = true
-			} else if b.points == a.points && b.exactScores == a.exactScores && b.correctResults > a.correctResults {
-				swap = true
-			}
-			if swap {
-				scores[i], scores[j] = scores[j], scores[i]
-			}
+		b := CalculateRoundBreakdown(rules, predList, matchList, true)
+		st := Standing{
+			Points:         b.Points + sumItems(questions.items(user.ID, round)),
+			ExactScores:    b.ExactScores,
+			CorrectResults: b.CorrectResults,
+			Missed:         missed,
+			RoundTotalHits: b.RoundTotalHits,
+		}
+		if t, ok := submissions[user.ID][round]; ok {
+			st.FirstSubmission = &t
 		}
+		scores = append(scores, userScore{user, st})
 	}
 
+	positions := rankEntries(ranking, scores, func(sc userScore) Standing { return sc.standing })
+
 	result := make([]classRow, 0, len(scores))
-	for _, sc := range scores {
-		if sc.points == 0 {
+	for i, sc := range scores {
+		if sc.standing.Points == 0 {
 			continue
 		}
 		result = append(result, classRow{
+			position:       positions[i],
 			displayName:    sc.user.DisplayName,
-			points:         sc.points,
-			exactScores:    sc.exactScores,
-			correctResults: sc.correctResults,
+			points:         sc.standing.Points,
+			exactScores:    sc.standing.ExactScores,
+			correctResults: sc.standing.CorrectResults,
 		})
 	}
 	return result
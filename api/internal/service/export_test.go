@@ -66,7 +66,7 @@ func sectionAfter(records [][]string, header string) [][]string {
 		return nil
 	}
 	for i := start; i < len(records); i++ {
-		if len(records[i]) > 0 && records[i][0] == "Rodada" {
+		if len(records[i]) > 0 && (records[i][0] == "Rodada" || records[i][0] == "Faixa" || records[i][0] == "Time") {
 			return records[start:i]
 		}
 	}
@@ -85,7 +85,7 @@ func TestBuildCSVPalpitesAgreeWithClassification(t *testing.T) {
 	}
 	ana := exportUser("Ana")
 
-	raw, err := buildCSV([]int{1}, matches, []models.User{ana}, nil, now)
+	raw, err := buildCSV(RulesetHistory{defaultRules}, models.BolaoSettings{}, []int{1}, nil, matches, []models.User{ana}, nil, nil, nil, nil, nil, nil, now)
 	if err != nil {
 		t.Fatalf("buildCSV: %v", err)
 	}
@@ -130,7 +130,7 @@ func TestBuildCSVOpenMarketPrintsDash(t *testing.T) {
 	m := exportMatch("Vitória", "Remo", 0, 0, nil)
 	ana := exportUser("Ana")
 
-	raw, err := buildCSV([]int{1}, []models.Match{m}, []models.User{ana}, nil, now)
+	raw, err := buildCSV(RulesetHistory{defaultRules}, models.BolaoSettings{}, []int{1}, nil, []models.Match{m}, []models.User{ana}, nil, nil, nil, nil, nil, nil, now)
 	if err != nil {
 		t.Fatalf("buildCSV: %v", err)
 	}
@@ -156,7 +156,7 @@ func TestBuildCSVKeepsRealPredictions(t *testing.T) {
 		ID: uuid.New(), UserID: ana.ID, MatchID: m.ID, HomeGoals: 2, AwayGoals: 1,
 	}
 
-	raw, err := buildCSV([]int{1}, []models.Match{m}, []models.User{ana}, []models.Prediction{pred}, now)
+	raw, err := buildCSV(RulesetHistory{defaultRules}, models.BolaoSettings{}, []int{1}, nil, []models.Match{m}, []models.User{ana}, []models.Prediction{pred}, nil, nil, nil, nil, nil, now)
 	if err != nil {
 		t.Fatalf("buildCSV: %v", err)
 	}
@@ -178,7 +178,7 @@ func TestGetRoundClassificationNoShowAfterClose(t *testing.T) {
 	}
 	ana := exportUser("Ana")
 
-	rows := getRoundClassification(matches, []models.User{ana}, indexPredictions(nil), now)
+	rows := getRoundClassification(defaultRules, defaultRanking, matches, []models.User{ana}, indexPredictions(nil), nil, nil, nil, nil, nil, nil, now)
 	if len(rows) != 1 {
 		t.Fatalf("classification has %d rows, want 1 (the no-show scores and is not filtered out)", len(rows))
 	}
@@ -195,8 +195,145 @@ func TestGetRoundClassificationNoShowOpenMarket(t *testing.T) {
 	}
 	ana := exportUser("Ana")
 
-	rows := getRoundClassification(matches, []models.User{ana}, indexPredictions(nil), now)
+	rows := getRoundClassification(defaultRules, defaultRanking, matches, []models.User{ana}, indexPredictions(nil), nil, nil, nil, nil, nil, nil, now)
 	if len(rows) != 0 {
 		t.Errorf("no-show with an open market appeared in the classification: %+v", rows)
 	}
 }
+
+// The coringa doubles the PALPITES row and the CLASSIFICAÇÃO total by the same amount, so
+// the two sections still agree.
+func TestBuildCSVJoker(t *testing.T) {
+	now := testNow
+	closed := timePtr(now.Add(-time.Hour))
+	matches := []models.Match{
+		exportMatch("Vitória", "Remo", 1, 0, closed),
+		exportMatch("Atlético-MG", "Palmeiras", 2, 2, closed),
+	}
+	ana := exportUser("Ana")
+	preds := []models.Prediction{
+		{ID: uuid.New(), UserID: ana.ID, MatchID: matches[0].ID, HomeGoals: 1, AwayGoals: 0},
+		{ID: uuid.New(), UserID: ana.ID, MatchID: matches[1].ID, HomeGoals: 0, AwayGoals: 3},
+	}
+	jokers := []models.Joker{{UserID: ana.ID, Round: 1, MatchID: matches[0].ID}}
+
+	raw, err := buildCSV(RulesetHistory{defaultRules}, models.BolaoSettings{}, []int{1}, nil, matches, []models.User{ana}, preds, jokers, nil, nil, nil, nil, now)
+	if err != nil {
+		t.Fatalf("buildCSV: %v", err)
+	}
+	records := parseCSV(t, raw)
+
+	palpite := findRow(sectionAfter(records, "Palpite_Mandante"), 1, "Vitória x Remo", 7)
+	if palpite == nil {
+		t.Fatal("no prediction row for the coringa match")
+	}
+	if palpite[5] != "36" || palpite[6] != "sim" {
+		t.Errorf("coringa row = %s points, Coringa=%q, want 36 and \"sim\"", palpite[5], palpite[6])
+	}
+	other := findRow(sectionAfter(records, "Palpite_Mandante"), 1, "Atlético-MG x Palmeiras", 7)
+	if other == nil || other[6] != "" {
+		t.Errorf("the other match is flagged as the coringa: %v", other)
+	}
+
+	// 36 for the doubled 1-0, nothing for the 0-3 against a 2-2. Predicted total 4 vs real 5.
+	classification := findRow(sectionAfter(records, "Posicao"), 2, "Ana", 6)
+	if classification == nil || classification[3] != "36" {
+		t.Errorf("classification row = %v, want 36 points", classification)
+	}
+}
+
+// Each round is scored, and labelled, with the rule version in force at that round.
+func TestBuildCSVRuleVersions(t *testing.T) {
+	now := testNow
+	closed := timePtr(now.Add(-time.Hour))
+	early := exportMatch("Vitória", "Remo", 2, 2, closed)
+	late := exportMatch("Atlético-MG", "Palmeiras", 2, 2, closed)
+	late.Round = 10
+	ana := exportUser("Ana")
+	preds := []models.Prediction{
+		{ID: uuid.New(), UserID: ana.ID, MatchID: early.ID, HomeGoals: 1, AwayGoals: 1},
+		{ID: uuid.New(), UserID: ana.ID, MatchID: late.ID, HomeGoals: 1, AwayGoals: 1},
+	}
+
+	raw, err := buildCSV(drawRuleChange(), models.BolaoSettings{}, []int{1, 10}, nil, []models.Match{early, late}, []models.User{ana}, preds, nil, nil, nil, nil, nil, now)
+	if err != nil {
+		t.Fatalf("buildCSV: %v", err)
+	}
+	records := parseCSV(t, raw)
+
+	// Same 1×1 against the same 2×2: 9 under version 1, 12 under version 2.
+	for _, tt := range []struct {
+		jogo, points, version string
+	}{
+		{"Vitória x Remo", "9", "1"},
+		{"Atlético-MG x Palmeiras", "12", "2"},
+	} {
+		row := findRow(sectionAfter(records, "Palpite_Mandante"), 1, tt.jogo, 8)
+		if row == nil || row[5] != tt.points || row[7] != tt.version {
+			t.Errorf("%s row = %v, want %s points under version %s", tt.jogo, row, tt.points, tt.version)
+		}
+	}
+	classification := findRow(sectionAfter(records, "Posicao"), 0, "10", 7)
+	if classification == nil || classification[3] != "12" || classification[6] != "2" {
+		t.Errorf("round 10 classification row = %v, want 12 points under version 2", classification)
+	}
+}
+
+// The classification rows carry the round's weight and what it adds to the standings.
+func TestBuildCSVRoundWeight(t *testing.T) {
+	now := testNow
+	closed := timePtr(now.Add(-time.Hour))
+	m := exportMatch("Vitória", "Remo", 2, 2, closed)
+	ana := exportUser("Ana")
+	preds := []models.Prediction{{ID: uuid.New(), UserID: ana.ID, MatchID: m.ID, HomeGoals: 1, AwayGoals: 1}}
+	settings := models.BolaoSettings{RoundWeights: []models.RoundWeight{{FromRound: 1, ToRound: 1, Percent: 150}}}
+
+	raw, err := buildCSV(RulesetHistory{defaultRules}, settings, []int{1}, nil, []models.Match{m}, []models.User{ana}, preds, nil, nil, nil, nil, nil, now)
+	if err != nil {
+		t.Fatalf("buildCSV: %v", err)
+	}
+	row := findRow(sectionAfter(parseCSV(t, raw), "Posicao"), 2, "Ana", 9)
+	// 12 for the draw, 18 weighted.
+	if row == nil || row[3] != "12" || row[7] != "1,5" || row[8] != "18" {
+		t.Errorf("classification row = %v, want 12 points, weight 1,5 and 18 weighted", row)
+	}
+}
+
+// Each range with a result gets its own standings; a range not yet played is left out.
+func TestBuildCSVRoundRanges(t *testing.T) {
+	now := testNow
+	closed := timePtr(now.Add(-time.Hour))
+	r1 := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	r2 := exportMatch("Bahia", "Remo", 1, 0, closed)
+	r2.Round = 2
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	preds := []models.Prediction{
+		testPrediction(ana, r1, 2, 1), testPrediction(bruno, r1, 0, 0),
+		testPrediction(ana, r2, 0, 0), testPrediction(bruno, r2, 1, 0),
+	}
+	ranges := []models.RoundRange{{Name: "turno", FromRound: 1, ToRound: 1}, {Name: "returno", FromRound: 2, ToRound: 2}, {Name: "final", FromRound: 3, ToRound: 3}}
+
+	raw, err := buildCSV(RulesetHistory{defaultRules}, models.BolaoSettings{}, []int{1, 2}, ranges, []models.Match{r1, r2}, []models.User{ana, bruno}, preds, nil, nil, nil, nil, nil, now)
+	if err != nil {
+		t.Fatalf("buildCSV: %v", err)
+	}
+	records := parseCSV(t, raw)
+	section := sectionAfter(records, "Rodadas_Vencidas")
+	// A one-round range scores what the round's own table does.
+	round := findRow(sectionAfter(records, "Pontos_Ponderados"), 2, "Ana", 9)
+	if row := findRow(section, 3, "Ana", 8); row == nil || round == nil || row[0] != "turno" || row[1] != "1-1" || row[2] != "1" || row[4] != round[3] || row[5] != "1" {
+		t.Errorf("Ana's first row = %v, want first in the turno with round 1's points (%v) and a round won", row, round)
+	}
+	var returno []string
+	for _, row := range section {
+		if row[0] == "final" {
+			t.Errorf("row %v for a range without results", row)
+		}
+		if row[0] == "returno" && row[2] == "1" {
+			returno = row
+		}
+	}
+	if returno == nil || returno[3] != "Bruno" || returno[5] != "1" {
+		t.Errorf("returno leader = %v, want Bruno with a round won", returno)
+	}
+}
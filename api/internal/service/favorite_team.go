@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/bolao-app/api/internal/repository"
+	"github.com/google/uuid"
+)
+
+// FavoriteTeamIndex maps a user to their favorite-team history, oldest first.
+type FavoriteTeamIndex map[uuid.UUID][]models.FavoriteTeamChange
+
+func indexFavoriteTeams(changes []models.FavoriteTeamChange) FavoriteTeamIndex {
+	index := make(FavoriteTeamIndex)
+	for _, ch := range changes {
+		index[ch.UserID] = append(index[ch.UserID], ch)
+	}
+	return index
+}
+
+// teamAt is the team the user had at t, "" when they had none.
+func (idx FavoriteTeamIndex) teamAt(userID uuid.UUID, t time.Time) string {
+	team := ""
+	for _, ch := range idx[userID] {
+		if ch.ChangedAt.After(t) {
+			break
+		}
+		team = ""
+		if ch.Team != nil {
+			team = *ch.Team
+		}
+	}
+	return team
+}
+
+// forRound is the favorite team the bonus uses for the user in the round whose matches are
+// matches: the one they had when the round's market closed, with its first match (SCORING.md
+// §2.6). Until then it is their current team, which is all a simulation of a round still
+// open can go on.
+func (idx FavoriteTeamIndex) forRound(userID uuid.UUID, matches []models.Match, now time.Time) string {
+	at := now
+	if closes := roundClosesAt(matches); closes != nil && closes.Before(now) {
+		at = *closes
+	}
+	return idx.teamAt(userID, at)
+}
+
+// roundClosesAt is when the round's first market closes, nil when no match has a closing time.
+func roundClosesAt(matches []models.Match) *time.Time {
+	var first *time.Time
+	for _, m := range matches {
+		if m.MarketClosesAt != nil && (first == nil || m.MarketClosesAt.Before(*first)) {
+			first = m.MarketClosesAt
+		}
+	}
+	return first
+}
+
+// playsFor reports whether team is one of the sides of m.
+func playsFor(m models.Match, team string) bool {
+	return team != "" && (m.HomeTeam == team || m.AwayTeam == team)
+}
+
+// usesFavoriteTeam reports whether any version of the rules pays a favorite-team bonus.
+func (h RulesetHistory) usesFavoriteTeam() bool {
+	for _, rs := range h {
+		if rs.FavoriteTeamExactBonus > 0 || rs.FavoriteTeamResultBonus > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadFavoriteTeams returns the favorite-team history of the bolão's participants. When no
+// version of the rules pays the bonus, it returns nil without touching the database.
+func LoadFavoriteTeams(ctx context.Context, repo *repository.UserRepository, bolaoID uuid.UUID, rulesets RulesetHistory) (FavoriteTeamIndex, error) {
+	if !rulesets.usesFavoriteTeam() {
+		return nil, nil
+	}
+	changes, err := repo.ListFavoriteTeamChanges(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	return indexFavoriteTeams(changes), nil
+}
+
+// applyFavoriteTeam pays the favorite-team bonus when p is on a match of the player's team:
+// FavoriteTeamExactBonus for an exact score, FavoriteTeamResultBonus for any other correct
+// result.
+func applyFavoriteTeam(rules models.ScoringRuleset, items []ScoreItem, p PredEntry, m MatchScore) []ScoreItem {
+	if p.FavoriteTeam == "" {
+		return items
+	}
+	item := ScoreItem{Rule: "favorite_team", Detail: p.FavoriteTeam}
+	switch {
+	case p.PredHome == m.HomeGoals && p.PredAway == m.AwayGoals:
+		item.Constant, item.Points = "FavoriteTeamExactBonus", rules.FavoriteTeamExactBonus
+	case matchResult(p.PredHome, p.PredAway) == matchResult(m.HomeGoals, m.AwayGoals):
+		item.Constant, item.Points = "FavoriteTeamResultBonus", rules.FavoriteTeamResultBonus
+	}
+	if item.Points <= 0 {
+		return items
+	}
+	return append(items, item)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+)
+
+func favoriteRules() models.ScoringRuleset {
+	rules := rulesWithEngine(EngineClassic)
+	rules.FavoriteTeamExactBonus = 5
+	rules.FavoriteTeamResultBonus = 2
+	return rules
+}
+
+func TestFavoriteTeamBonus(t *testing.T) {
+	result := []MatchScore{{HomeGoals: 2, AwayGoals: 1}}
+	tests := []struct {
+		name string
+		pred PredEntry
+		want int
+	}{
+		{"exact score", PredEntry{PredHome: 2, PredAway: 1, FavoriteTeam: "Bahia"}, ClassicPointsExactScore + 5},
+		{"correct result", PredEntry{PredHome: 3, PredAway: 0, FavoriteTeam: "Bahia"}, ClassicPointsCorrectResult + 2},
+		{"wrong result", PredEntry{PredHome: 0, PredAway: 1, FavoriteTeam: "Bahia"}, 0},
+		{"another team's match", PredEntry{PredHome: 2, PredAway: 1}, ClassicPointsExactScore},
+		// The coringa multiplies the match with its bonus.
+		{"on the coringa", PredEntry{PredHome: 2, PredAway: 1, Joker: true, FavoriteTeam: "Bahia"}, 2 * (ClassicPointsExactScore + 5)},
+	}
+	for _, tt := range tests {
+		if got := CalculateRoundBreakdown(favoriteRules(), []PredEntry{tt.pred}, result, true).Points; got != tt.want {
+			t.Errorf("%s: %d points, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFavoriteTeamFrozenAtRoundClose(t *testing.T) {
+	closes := testNow.Add(-24 * time.Hour)
+	matches := []models.Match{
+		{Round: 1, MarketClosesAt: timePtr(closes.Add(2 * time.Hour))},
+		{Round: 1, MarketClosesAt: timePtr(closes)},
+	}
+	ana := exportUser("Ana")
+	bahia, vitoria := "Bahia", "Vitória"
+	idx := indexFavoriteTeams([]models.FavoriteTeamChange{
+		{UserID: ana.ID, Team: &bahia, ChangedAt: closes.Add(-time.Hour)},
+		// Switched to the team of a match that already went well.
+		{UserID: ana.ID, Team: &vitoria, ChangedAt: closes.Add(time.Hour)},
+	})
+
+	if got := idx.forRound(ana.ID, matches, testNow); got != bahia {
+		t.Errorf("team for the closed round = %q, want %q, the one at the first close", got, bahia)
+	}
+	open := []models.Match{{Round: 2, MarketClosesAt: timePtr(testNow.Add(time.Hour))}}
+	if got := idx.forRound(ana.ID, open, testNow); got != vitoria {
+		t.Errorf("team for an open round = %q, want the current %q", got, vitoria)
+	}
+	if got := idx.teamAt(ana.ID, closes.Add(-2*time.Hour)); got != "" {
+		t.Errorf("team before any change = %q, want none", got)
+	}
+}
+
+// The standings pay the bonus on the team frozen for the round, not the current one.
+func TestStandingsFavoriteTeamBonus(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	m := exportMatch("Vitória", "Remo", 2, 1, closed)
+	ana := exportUser("Ana")
+	d := testSnapshot([]models.User{ana}, []models.Match{m}, []models.Prediction{testPrediction(ana, m, 2, 1)})
+	d.rulesets = RulesetHistory{favoriteRules()}
+	vitoria, bahia := "Vitória", "Bahia"
+
+	d.favorites = indexFavoriteTeams([]models.FavoriteTeamChange{{UserID: ana.ID, Team: &vitoria, ChangedAt: testNow.Add(-48 * time.Hour)}})
+	if got := d.standings(1, finalResults, testNow)[0].TotalPoints; got != ClassicPointsExactScore+5 {
+		t.Errorf("fan of the home side = %d points, want %d", got, ClassicPointsExactScore+5)
+	}
+
+	d.favorites = indexFavoriteTeams([]models.FavoriteTeamChange{
+		{UserID: ana.ID, Team: &bahia, ChangedAt: testNow.Add(-48 * time.Hour)},
+		{UserID: ana.ID, Team: &vitoria, ChangedAt: testNow.Add(-30 * time.Minute)},
+	})
+	if got := d.standings(1, finalResults, testNow)[0].TotalPoints; got != ClassicPointsExactScore {
+		t.Errorf("switched after the close = %d points, want %d without the bonus", got, ClassicPointsExactScore)
+	}
+}
@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// HistoryEntry is a participant's place in the cumulative standings after a round.
+type HistoryEntry struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	Position    int       `json:"position"`
+	// PositionChange is how many places the round moved the participant up (negative when
+	// it moved them down); 0 after the first round.
+	PositionChange int `json:"position_change"`
+	TotalPoints    int `json:"total_points"`
+	// RoundPoints is what the round added to the season, after its weight.
+	RoundPoints int `json:"round_points"`
+}
+
+// RoundHistory is the cumulative standings after a round, in position order. The last
+// round's is what GetClassification returns; the earlier ones leave out the season-long
+// bets, settled at the end of the season, and the discarded worst rounds, which only make
+// sense over the whole season.
+type RoundHistory struct {
+	Round int `json:"round"`
+	// WinnerID is the round's winner, nil when nobody scored.
+	WinnerID  *uuid.UUID     `json:"winner_id"`
+	Standings []HistoryEntry `json:"standings"`
+}
+
+// history is the standings after every round up to upToRound that scoresOf counts, added
+// up in a single pass.
+func (d *bolaoSnapshot) history(upToRound int, scoresOf func(round int) (map[uuid.UUID]roundScore, bool)) []RoundHistory {
+	var rounds []int
+	scoresByRound := make(map[int]map[uuid.UUID]roundScore)
+	for round := 1; round <= upToRound; round++ {
+		if scores, ok := scoresOf(round); ok {
+			rounds = append(rounds, round)
+			scoresByRound[round] = scores
+		}
+	}
+
+	t := d.newSeasonTotals()
+	history := make([]RoundHistory, 0, len(rounds))
+	previous := make(map[uuid.UUID]int, len(d.participants))
+	for i, round := range rounds {
+		h := RoundHistory{Round: round}
+		if winner, ok := t.add(round, scoresByRound[round]); ok {
+			h.WinnerID = &winner
+		}
+
+		t.rangeOnly = i < len(rounds)-1
+		table := t.table()
+		h.Standings = make([]HistoryEntry, 0, len(table))
+		for _, u := range table {
+			e := HistoryEntry{
+				UserID:      u.ID,
+				DisplayName: u.DisplayName,
+				Position:    u.Position,
+				TotalPoints: u.TotalPoints,
+				RoundPoints: t.standings[u.ID].RoundPoints[round],
+			}
+			if p, ok := previous[u.ID]; ok {
+				e.PositionChange = p - u.Position
+			}
+			previous[u.ID] = u.Position
+			h.Standings = append(h.Standings, e)
+		}
+		history = append(history, h)
+	}
+	return history
+}
+
+// GetClassificationHistory returns the cumulative standings after each round of the
+// bolão with a result, from the materialized round scores.
+func (s *ClassificationService) GetClassificationHistory(ctx context.Context, bolaoID uuid.UUID) ([]RoundHistory, error) {
+	d, rows, maxRound, err := s.loadRoundScores(ctx, bolaoID, 0)
+	if err != nil {
+		return nil, err
+	}
+	scores := d.scoresFromRows(rows)
+	return d.history(maxRound, func(round int) (map[uuid.UUID]roundScore, bool) {
+		s, ok := scores[round]
+		return s, ok
+	}), nil
+}
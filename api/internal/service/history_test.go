@@ -0,0 +1,88 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// Each round of the history is the cumulative standings up to that round.
+func TestHistory(t *testing.T) {
+	d, ana, bruno := roundScoreFixture()
+	history := d.history(3, func(round int) (map[uuid.UUID]roundScore, bool) {
+		return d.scoreRound(round, finalResults, testNow)
+	})
+
+	if len(history) != 2 {
+		t.Fatalf("%d rounds in the history, want 2 (round 3 has no result)", len(history))
+	}
+	for _, h := range history {
+		want := d.standings(h.Round, finalResults, testNow)
+		for i, e := range h.Standings {
+			if e.UserID != want[i].ID || e.Position != want[i].Position || e.TotalPoints != want[i].TotalPoints {
+				t.Errorf("round %d, #%d: %+v, want %s in position %d on %d", h.Round, i+1, e, want[i].DisplayName, want[i].Position, want[i].TotalPoints)
+			}
+		}
+	}
+
+	if w := history[0].WinnerID; w == nil || *w != ana.ID {
+		t.Errorf("round 1 winner = %v, want Ana", w)
+	}
+	if w := history[1].WinnerID; w == nil || *w != bruno.ID {
+		t.Errorf("round 2 winner = %v, want Bruno (earlier submission)", w)
+	}
+
+	change := map[uuid.UUID]int{}
+	for _, e := range history[1].Standings {
+		change[e.UserID] = e.PositionChange
+	}
+	for _, e := range history[0].Standings {
+		if e.PositionChange != 0 {
+			t.Errorf("round 1: %s moved %d places, want 0", e.DisplayName, e.PositionChange)
+		}
+		if got, want := change[e.UserID], e.Position-positionIn(history[1], e.UserID); got != want {
+			t.Errorf("round 2: %s moved %d places, want %d", e.DisplayName, got, want)
+		}
+	}
+}
+
+// The season-long bets and the discarded worst rounds only count in the last round of the
+// history, the one GetClassification matches: the earlier ones are the rounds' own sums.
+func TestHistorySeasonLongBets(t *testing.T) {
+	d, ana, _ := roundScoreFixture()
+	d.outrightPoints = map[uuid.UUID]int{ana.ID: 50}
+	d.settings.DropWorstRounds = 1
+	scoresOf := func(round int) (map[uuid.UUID]roundScore, bool) {
+		return d.scoreRound(round, finalResults, testNow)
+	}
+	history := d.history(3, scoresOf)
+	if len(history) != 2 {
+		t.Fatalf("%d rounds in the history, want 2", len(history))
+	}
+
+	first, _ := d.partStandings([]int{1}, scoresOf)
+	for i, e := range history[0].Standings {
+		if e.UserID != first[i].ID || e.TotalPoints != first[i].TotalPoints {
+			t.Errorf("round 1, #%d: %+v, want %s on %d", i+1, e, first[i].DisplayName, first[i].TotalPoints)
+		}
+		if e.UserID == ana.ID && e.TotalPoints >= 50 {
+			t.Errorf("round 1: Ana on %d, want her season-long bet left out", e.TotalPoints)
+		}
+	}
+
+	final := d.standings(3, finalResults, testNow)
+	for i, e := range history[1].Standings {
+		if e.UserID != final[i].ID || e.Position != final[i].Position || e.TotalPoints != final[i].TotalPoints {
+			t.Errorf("round 2, #%d: %+v, want %s in position %d on %d", i+1, e, final[i].DisplayName, final[i].Position, final[i].TotalPoints)
+		}
+	}
+}
+
+func positionIn(h RoundHistory, userID uuid.UUID) int {
+	for _, e := range h.Standings {
+		if e.UserID == userID {
+			return e.Position
+		}
+	}
+	return 0
+}
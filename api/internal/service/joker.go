@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// jokerIndex maps user → round → the match picked as that round's coringa.
+type jokerIndex map[uuid.UUID]map[int]uuid.UUID
+
+func indexJokers(jokers []models.Joker) jokerIndex {
+	index := make(jokerIndex)
+	for _, j := range jokers {
+		if index[j.UserID] == nil {
+			index[j.UserID] = make(map[int]uuid.UUID)
+		}
+		index[j.UserID][j.Round] = j.MatchID
+	}
+	return index
+}
+
+// match returns the user's coringa for round, or uuid.Nil when there is none. Safe on a
+// nil index.
+func (ix jokerIndex) match(userID uuid.UUID, round int) uuid.UUID {
+	return ix[userID][round]
+}
+
+// MarkJoker flags the prediction for the coringa match, for handlers returning a user's
+// predictions of a round.
+func MarkJoker(predictions []models.Prediction, jokerMatchID uuid.UUID) []models.Prediction {
+	for i := range predictions {
+		predictions[i].Joker = predictions[i].MatchID == jokerMatchID
+	}
+	return predictions
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+)
+
+// Sides of a knockout match, as stored in penalty_winner and predictions.advances.
+const (
+	SideHome = "home"
+	SideAway = "away"
+)
+
+var ErrInvalidKnockout = errors.New("mata-mata inválido")
+
+func validSide(side string) bool {
+	return side == SideHome || side == SideAway
+}
+
+// winnerSide is the side ahead on the score, "" for a draw.
+func winnerSide(home, away int) string {
+	switch {
+	case home > away:
+		return SideHome
+	case away > home:
+		return SideAway
+	}
+	return ""
+}
+
+// ValidateMatchResult checks a final result against the match: extra time and penalties
+// only on a knockout match level after 90 minutes, extra time never taking goals away, and
+// penalties only when extra time, if played, ended level too. The second leg of a tie is
+// checked by ValidateTieResult instead.
+func ValidateMatchResult(m models.Match, r models.MatchResult) error {
+	if !m.Knockout {
+		if r.ExtraTimeHomeGoals != nil || r.ExtraTimeAwayGoals != nil || r.PenaltyWinner != nil {
+			return fmt.Errorf("%w: prorrogação e pênaltis só valem para jogos de mata-mata", ErrInvalidKnockout)
+		}
+	}
+	return validateDecider(r, func(home, away int) bool { return home == away })
+}
+
+// validateDecider checks the extra time and penalties of r, level telling whether a
+// score leaves the match undecided.
+func validateDecider(r models.MatchResult, level func(home, away int) bool) error {
+	if r.HomeGoals < 0 || r.AwayGoals < 0 {
+		return fmt.Errorf("%w: gols não podem ser negativos", ErrInvalidKnockout)
+	}
+	undecided := level(r.HomeGoals, r.AwayGoals)
+	if r.ExtraTimeHomeGoals != nil || r.ExtraTimeAwayGoals != nil {
+		if r.ExtraTimeHomeGoals == nil || r.ExtraTimeAwayGoals == nil {
+			return fmt.Errorf("%w: informe o placar completo da prorrogação", ErrInvalidKnockout)
+		}
+		if !undecided {
+			return fmt.Errorf("%w: só há prorrogação quando o tempo normal não decide quem avança", ErrInvalidKnockout)
+		}
+		if *r.ExtraTimeHomeGoals < r.HomeGoals || *r.ExtraTimeAwayGoals < r.AwayGoals {
+			return fmt.Errorf("%w: o placar da prorrogação inclui os gols do tempo normal", ErrInvalidKnockout)
+		}
+		undecided = level(*r.ExtraTimeHomeGoals, *r.ExtraTimeAwayGoals)
+	}
+	if r.PenaltyWinner != nil {
+		if !validSide(*r.PenaltyWinner) {
+			return fmt.Errorf("%w: penalty_winner deve ser %q ou %q", ErrInvalidKnockout, SideHome, SideAway)
+		}
+		if !undecided {
+			return fmt.Errorf("%w: só há pênaltis quando o jogo não decide quem avança", ErrInvalidKnockout)
+		}
+	}
+	return nil
+}
+
+// ValidateAdvancePick checks the side a prediction says goes through. It only applies to
+// a knockout match, and a predicted score with a winner already picks that side. The
+// second leg of a tie is checked by ValidateTiePick instead.
+func ValidateAdvancePick(m models.Match, home, away int, advances *string) error {
+	if advances == nil {
+		return nil
+	}
+	if !m.Knockout {
+		return fmt.Errorf("%w: %s x %s não é jogo de mata-mata", ErrInvalidKnockout, m.HomeTeam, m.AwayTeam)
+	}
+	if !validSide(*advances) {
+		return fmt.Errorf("%w: advances deve ser %q ou %q", ErrInvalidKnockout, SideHome, SideAway)
+	}
+	if w := winnerSide(home, away); w != "" && w != *advances {
+		return fmt.Errorf("%w: quem avança não bate com o placar do palpite", ErrInvalidKnockout)
+	}
+	return nil
+}
+
+// Qualifier is the side that went through a knockout match: the winner of the 90
+// minutes, else of extra time, else of the shoot-out. "" when the match isn't a knockout
+// one or isn't decided yet.
+func Qualifier(m models.Match) string {
+	if !m.Knockout || m.HomeGoals == nil || m.AwayGoals == nil {
+		return ""
+	}
+	if w := winnerSide(*m.HomeGoals, *m.AwayGoals); w != "" {
+		return w
+	}
+	if m.ExtraTimeHomeGoals != nil && m.ExtraTimeAwayGoals != nil {
+		if w := winnerSide(*m.ExtraTimeHomeGoals, *m.ExtraTimeAwayGoals); w != "" {
+			return w
+		}
+	}
+	if m.PenaltyWinner != nil {
+		return *m.PenaltyWinner
+	}
+	return ""
+}
+
+// predictedQualifier is the side a prediction sends through: the winner of its score, or
+// its advances pick for a draw.
+func predictedQualifier(home, away int, advances string) string {
+	if w := winnerSide(home, away); w != "" {
+		return w
+	}
+	return advances
+}
+
+// knockoutSides is the side entry sends through m and the side that went through, for the
+// qualifier points: over the aggregate of both legs when m is the second leg of tie (nil
+// otherwise), reading the player's first-leg prediction with lookup. pick is the
+// prediction's advances pick.
+func knockoutSides(m models.Match, tie *tieLegs, entry PredEntry, pick string, lookup predictionLookup, now time.Time) (advances, qualifier string) {
+	if !m.Knockout || entry.PredHome == noPredSentinel {
+		return "", ""
+	}
+	if tie == nil {
+		return predictedQualifier(entry.PredHome, entry.PredAway, pick), Qualifier(m)
+	}
+	p, has := lookup(tie.first.ID)
+	if home, away, counts := EffectivePrediction(tie.first, p.Home, p.Away, has, now); counts {
+		advances = tie.predictedQualifier(home, away, entry.PredHome, entry.PredAway, pick)
+	}
+	return advances, tie.qualifier(m)
+}
+
+// applyQualifier pays QualifierPoints when p sent through the side that went through.
+func applyQualifier(rules models.ScoringRuleset, items []ScoreItem, p PredEntry) []ScoreItem {
+	if p.Qualifier == "" || rules.QualifierPoints <= 0 || p.Advances != p.Qualifier {
+		return items
+	}
+	return append(items, ScoreItem{Rule: "qualifier", Constant: "PointsCorrectQualifier", Points: rules.QualifierPoints})
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+)
+
+func sidePtr(side string) *string { return &side }
+
+func knockoutMatch(home, away int) models.Match {
+	m := exportMatch("Bahia", "Remo", home, away, timePtr(testNow.Add(-time.Hour)))
+	m.Knockout = true
+	return m
+}
+
+func TestQualifier(t *testing.T) {
+	level := knockoutMatch(1, 1)
+	extraTime := level
+	extraTime.ExtraTimeHomeGoals, extraTime.ExtraTimeAwayGoals = intPtr(1), intPtr(2)
+	penalties := level
+	penalties.ExtraTimeHomeGoals, penalties.ExtraTimeAwayGoals = intPtr(2), intPtr(2)
+	penalties.PenaltyWinner = sidePtr(SideHome)
+	league := knockoutMatch(2, 0)
+	league.Knockout = false
+
+	tests := []struct {
+		name  string
+		match models.Match
+		want  string
+	}{
+		{"won in regulation", knockoutMatch(2, 0), SideHome},
+		{"won in extra time", extraTime, SideAway},
+		{"won on penalties", penalties, SideHome},
+		{"level and undecided", level, ""},
+		{"no result yet", models.Match{Knockout: true}, ""},
+		{"not a knockout match", league, ""},
+	}
+	for _, tt := range tests {
+		if got := Qualifier(tt.match); got != tt.want {
+			t.Errorf("%s: qualifier = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateMatchResult(t *testing.T) {
+	knockout := models.Match{Knockout: true}
+	tests := []struct {
+		name  string
+		match models.Match
+		res   models.MatchResult
+		ok    bool
+	}{
+		{"regulation only", knockout, models.MatchResult{HomeGoals: 2, AwayGoals: 1}, true},
+		{"extra time then penalties", knockout, models.MatchResult{HomeGoals: 1, AwayGoals: 1,
+			ExtraTimeHomeGoals: intPtr(1), ExtraTimeAwayGoals: intPtr(1), PenaltyWinner: sidePtr(SideAway)}, true},
+		{"straight to penalties", knockout, models.MatchResult{HomeGoals: 0, AwayGoals: 0, PenaltyWinner: sidePtr(SideHome)}, true},
+		{"extra time after a winner", knockout, models.MatchResult{HomeGoals: 2, AwayGoals: 1,
+			ExtraTimeHomeGoals: intPtr(2), ExtraTimeAwayGoals: intPtr(1)}, false},
+		{"extra time losing goals", knockout, models.MatchResult{HomeGoals: 1, AwayGoals: 1,
+			ExtraTimeHomeGoals: intPtr(0), ExtraTimeAwayGoals: intPtr(1)}, false},
+		{"half an extra-time score", knockout, models.MatchResult{HomeGoals: 1, AwayGoals: 1, ExtraTimeHomeGoals: intPtr(2)}, false},
+		{"penalties after extra time was won", knockout, models.MatchResult{HomeGoals: 1, AwayGoals: 1,
+			ExtraTimeHomeGoals: intPtr(2), ExtraTimeAwayGoals: intPtr(1), PenaltyWinner: sidePtr(SideHome)}, false},
+		{"unknown penalty side", knockout, models.MatchResult{HomeGoals: 0, AwayGoals: 0, PenaltyWinner: sidePtr("visitante")}, false},
+		{"penalties on a league match", models.Match{}, models.MatchResult{HomeGoals: 0, AwayGoals: 0, PenaltyWinner: sidePtr(SideHome)}, false},
+	}
+	for _, tt := range tests {
+		err := ValidateMatchResult(tt.match, tt.res)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidKnockout) {
+			t.Errorf("%s: error = %v, want ErrInvalidKnockout", tt.name, err)
+		}
+	}
+}
+
+func TestValidateAdvancePick(t *testing.T) {
+	knockout := models.Match{Knockout: true}
+	tests := []struct {
+		name       string
+		match      models.Match
+		home, away int
+		advances   *string
+		ok         bool
+	}{
+		{"draw with a pick", knockout, 1, 1, sidePtr(SideAway), true},
+		{"winner without a pick", knockout, 2, 1, nil, true},
+		{"pick agrees with the score", knockout, 2, 1, sidePtr(SideHome), true},
+		{"pick contradicts the score", knockout, 2, 1, sidePtr(SideAway), false},
+		{"unknown side", knockout, 1, 1, sidePtr("mandante"), false},
+		{"pick on a league match", models.Match{}, 1, 1, sidePtr(SideHome), false},
+	}
+	for _, tt := range tests {
+		err := ValidateAdvancePick(tt.match, tt.home, tt.away, tt.advances)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidKnockout) {
+			t.Errorf("%s: error = %v, want ErrInvalidKnockout", tt.name, err)
+		}
+	}
+}
+
+func TestQualifierPoints(t *testing.T) {
+	rules := rulesWithEngine(EngineClassic)
+	// 1×1 after 90 minutes, the away side through on penalties.
+	result := []MatchScore{{HomeGoals: 1, AwayGoals: 1}}
+	tests := []struct {
+		name string
+		pred PredEntry
+		want int
+	}{
+		{"draw with the right pick", PredEntry{PredHome: 1, PredAway: 1, Advances: SideAway, Qualifier: SideAway}, ClassicPointsExactScore + PointsCorrectQualifier},
+		{"draw with the wrong pick", PredEntry{PredHome: 1, PredAway: 1, Advances: SideHome, Qualifier: SideAway}, ClassicPointsExactScore},
+		{"draw without a pick", PredEntry{PredHome: 0, PredAway: 0, Qualifier: SideAway}, ClassicPointsCorrectResult},
+		// The result is wrong on the 90 minutes, but the side that went through is right.
+		{"away win", PredEntry{PredHome: 0, PredAway: 2, Advances: SideAway, Qualifier: SideAway}, PointsCorrectQualifier},
+		{"not decided yet", PredEntry{PredHome: 1, PredAway: 1, Advances: SideAway}, ClassicPointsExactScore},
+		{"on the coringa", PredEntry{PredHome: 1, PredAway: 1, Advances: SideAway, Qualifier: SideAway, Joker: true},
+			2 * (ClassicPointsExactScore + PointsCorrectQualifier)},
+	}
+	for _, tt := range tests {
+		if got := CalculateRoundBreakdown(rules, []PredEntry{tt.pred}, result, true).Points; got != tt.want {
+			t.Errorf("%s: %d points, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	rules.QualifierPoints = 0
+	off := PredEntry{PredHome: 1, PredAway: 1, Advances: SideAway, Qualifier: SideAway}
+	if got := CalculateRoundBreakdown(rules, []PredEntry{off}, result, true).Points; got != ClassicPointsExactScore {
+		t.Errorf("qualifier_points 0 = %d points, want %d", got, ClassicPointsExactScore)
+	}
+}
+
+// The standings read the stored pick and the match's extra time and penalties.
+func TestStandingsQualifierPoints(t *testing.T) {
+	m := knockoutMatch(1, 1)
+	m.PenaltyWinner = sidePtr(SideAway)
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	anaPred := testPrediction(ana, m, 1, 1)
+	anaPred.Advances = sidePtr(SideAway)
+	brunoPred := testPrediction(bruno, m, 1, 1)
+	brunoPred.Advances = sidePtr(SideHome)
+	d := testSnapshot([]models.User{ana, bruno}, []models.Match{m}, []models.Prediction{anaPred, brunoPred})
+
+	points := map[string]int{}
+	for _, u := range d.standings(1, finalResults, testNow) {
+		points[u.DisplayName] = u.TotalPoints
+	}
+	if points["Ana"] != points["Bruno"]+PointsCorrectQualifier {
+		t.Errorf("Ana = %d, Bruno = %d: want Ana ahead by the qualifier's %d", points["Ana"], points["Bruno"], PointsCorrectQualifier)
+	}
+}
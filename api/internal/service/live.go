@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// LiveEntry is a participant's row of the live standings.
+type LiveEntry struct {
+	models.UserWithStats
+	// PreviousPosition is the position after the last finished round, 0 when no round is
+	// finished yet.
+	PreviousPosition int `json:"previous_position"`
+	// PositionChange is how many places the matches since the last finished round move the
+	// participant up (negative when down); 0 when no round is finished yet.
+	PositionChange int `json:"position_change"`
+	// ProvisionalPoints is the part of TotalPoints that rests on parciais and can still
+	// change: the live total less the total on final results alone.
+	ProvisionalPoints int `json:"provisional_points"`
+}
+
+// LiveStandings is the cumulative standings as they stand now: every final result, plus the
+// parcial of each match in play.
+type LiveStandings struct {
+	// Provisional is set when a parcial counts, so the positions and totals aren't final.
+	Provisional bool `json:"provisional"`
+	// ProvisionalRounds are the rounds with a match counted on its parcial.
+	ProvisionalRounds []int `json:"provisional_rounds"`
+	// LastFinishedRound is the round the position changes are measured against: the latest
+	// one whose every match has a final result, 0 when there is none.
+	LastFinishedRound int         `json:"last_finished_round"`
+	Standings         []LiveEntry `json:"standings"`
+}
+
+// lastFinishedRound is the latest round whose every match has a final result, 0 when none
+// has.
+func (d *bolaoSnapshot) lastFinishedRound() int {
+	last := 0
+	for round, matches := range d.byRound {
+		finished := true
+		for _, m := range matches {
+			if _, ok := finalResults(m); !ok {
+				finished = false
+				break
+			}
+		}
+		if finished && round > last {
+			last = round
+		}
+	}
+	return last
+}
+
+// live projects the cumulative standings over the final results and partials, with each
+// participant's move since the last finished round.
+func (d *bolaoSnapshot) live(partials map[uuid.UUID]models.MatchPartial, now time.Time) *LiveStandings {
+	source := simulatedResults(nil, partials)
+	ls := &LiveStandings{ProvisionalRounds: []int{}, LastFinishedRound: d.lastFinishedRound()}
+	for round, matches := range d.byRound {
+		for _, m := range matches {
+			if r, ok := source(m); ok && r.partial {
+				ls.ProvisionalRounds = append(ls.ProvisionalRounds, round)
+				break
+			}
+		}
+	}
+	sort.Ints(ls.ProvisionalRounds)
+	ls.Provisional = len(ls.ProvisionalRounds) > 0
+
+	confirmed := make(map[uuid.UUID]int, len(d.participants))
+	for _, u := range d.standings(d.maxRound, finalResults, now) {
+		confirmed[u.ID] = u.TotalPoints
+	}
+	previous := make(map[uuid.UUID]int, len(d.participants))
+	if ls.LastFinishedRound > 0 {
+		for _, u := range d.standings(ls.LastFinishedRound, finalResults, now) {
+			previous[u.ID] = u.Position
+		}
+	}
+
+	projected := d.standings(d.maxRound, source, now)
+	ls.Standings = make([]LiveEntry, 0, len(projected))
+	for _, u := range projected {
+		e := LiveEntry{UserWithStats: u, ProvisionalPoints: u.TotalPoints - confirmed[u.ID]}
+		if p, ok := previous[u.ID]; ok {
+			e.PreviousPosition = p
+			e.PositionChange = p - u.Position
+		}
+		ls.Standings = append(ls.Standings, e)
+	}
+	return ls
+}
+
+// GetLiveStandings returns the cumulative standings as they stand during a round: the
+// finished rounds on their final results and the matches in play on their parciais.
+func (s *ClassificationService) GetLiveStandings(ctx context.Context, bolaoID uuid.UUID) (*LiveStandings, error) {
+	d, err := s.loadSnapshot(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	partials, err := s.partialRepo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	return d.live(partials, time.Now()), nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Ana took round 1; in round 2 Bruno called the 3×1 that is on the board right now.
+func TestLive(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	r1 := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	r2 := models.Match{ID: uuid.New(), Round: 2, HomeTeam: "Bahia", AwayTeam: "Remo", MarketClosesAt: closed}
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	d := testSnapshot([]models.User{ana, bruno}, []models.Match{r1, r2}, []models.Prediction{
+		testPrediction(ana, r1, 2, 1), testPrediction(bruno, r1, 1, 0),
+		testPrediction(ana, r2, 0, 2), testPrediction(bruno, r2, 3, 1),
+	})
+
+	final := d.live(nil, testNow)
+	if final.Provisional || len(final.ProvisionalRounds) != 0 || final.LastFinishedRound != 1 {
+		t.Errorf("without parciais = %+v, want nothing provisional after round 1", final)
+	}
+	for _, e := range final.Standings {
+		if e.ProvisionalPoints != 0 || e.PositionChange != 0 {
+			t.Errorf("%s = %+v, want no provisional points or move", e.DisplayName, e)
+		}
+	}
+
+	partials := map[uuid.UUID]models.MatchPartial{r2.ID: {MatchID: r2.ID, HomeGoals: intPtr(3), AwayGoals: intPtr(1)}}
+	live := d.live(partials, testNow)
+	if !live.Provisional || len(live.ProvisionalRounds) != 1 || live.ProvisionalRounds[0] != 2 {
+		t.Errorf("provisional rounds = %v, want round 2", live.ProvisionalRounds)
+	}
+	entry := map[uuid.UUID]LiveEntry{}
+	for _, e := range live.Standings {
+		entry[e.ID] = e
+	}
+	b := entry[bruno.ID]
+	// The round-total bonus waits for the final results (SCORING.md §3.1).
+	want := CalculateMatchPoints(defaultRules, 3, 1, 3, 1)
+	if b.PreviousPosition != 2 || b.PositionChange != 1 || b.ProvisionalPoints != want {
+		t.Errorf("Bruno = %+v, want up from 2nd with %d provisional points", b, want)
+	}
+	if a := entry[ana.ID]; a.PreviousPosition != 1 || a.PositionChange != -1 || a.ProvisionalPoints != 0 {
+		t.Errorf("Ana = %+v, want down from 1st with no provisional points", a)
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidMonthlyPrize = errors.New("prêmio mensal inválido")
+	ErrMonthNotFound       = errors.New("nenhuma rodada neste mês")
+)
+
+// MonthLayout is how a month is written: "2026-05".
+const MonthLayout = "2006-01"
+
+// saoPaulo is the time zone the months are counted in. Without tzdata it falls back to
+// UTC−3, Brazil's offset all year since it dropped daylight saving in 2019.
+var saoPaulo = func() *time.Location {
+	if loc, err := time.LoadLocation("America/Sao_Paulo"); err == nil {
+		return loc
+	}
+	return time.FixedZone("-03", -3*3600)
+}()
+
+// ValidateMonthlyPrize accepts 0 (no monthly prize) or a positive amount.
+func ValidateMonthlyPrize(amount float64) error {
+	if amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return ErrInvalidMonthlyPrize
+	}
+	return nil
+}
+
+// roundMonth is the month a round belongs to: that of its first market close, in São
+// Paulo time. ok=false when none of its matches has a close set.
+func roundMonth(matches []models.Match) (month string, ok bool) {
+	var first *time.Time
+	for _, m := range matches {
+		if c := m.MarketClosesAt; c != nil && (first == nil || c.Before(*first)) {
+			first = c
+		}
+	}
+	if first == nil {
+		return "", false
+	}
+	return first.In(saoPaulo).Format(MonthLayout), true
+}
+
+// MonthlyWinner is a player of the month.
+type MonthlyWinner struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	Points      int       `json:"points"`
+	// Prize is the winner's share of the bolão's monthly prize, 0 until the month is
+	// decided.
+	Prize float64 `json:"prize"`
+}
+
+// MonthlyClassification is the standings over the rounds of a calendar month.
+type MonthlyClassification struct {
+	Month  string `json:"month"`
+	Rounds []int  `json:"rounds"`
+	// Decided is set once every match of the month's rounds has a result.
+	Decided bool `json:"decided"`
+	// Winners are the players in first place, more than one only when they share it
+	// (SharedPositions); empty while nobody has scored.
+	Winners []MonthlyWinner `json:"winners"`
+	// Standings is left out of the list of months (GetMonthlyWinners).
+	Standings []models.UserWithStats `json:"standings,omitempty"`
+}
+
+// months is the classification of every month with a round, in calendar order. A round
+// without any market close set belongs to no month.
+func (d *bolaoSnapshot) months(scoresOf func(round int) (map[uuid.UUID]roundScore, bool)) []MonthlyClassification {
+	roundsOf := make(map[string][]int)
+	for round, matches := range d.byRound {
+		if month, ok := roundMonth(matches); ok {
+			roundsOf[month] = append(roundsOf[month], round)
+		}
+	}
+	keys := make([]string, 0, len(roundsOf))
+	for month := range roundsOf {
+		keys = append(keys, month)
+	}
+	sort.Strings(keys)
+
+	months := make([]MonthlyClassification, 0, len(keys))
+	for _, month := range keys {
+		rounds := roundsOf[month]
+		sort.Ints(rounds)
+		mc := MonthlyClassification{Month: month, Rounds: rounds, Decided: true, Winners: []MonthlyWinner{}}
+		for _, round := range rounds {
+			for _, m := range d.byRound[round] {
+				if m.HomeGoals == nil || m.AwayGoals == nil {
+					mc.Decided = false
+				}
+			}
+		}
+		var played bool
+		mc.Standings, played = d.partStandings(rounds, scoresOf)
+		if played {
+			mc.Winners = monthlyWinners(mc.Standings, mc.Decided, d.settings.MonthlyPrize)
+		}
+		months = append(months, mc)
+	}
+	return months
+}
+
+// monthlyWinners is the players in first place of a month's ranked standings, nobody when
+// the leader has no points. A decided month splits prize among them, rounded to the cent.
+func monthlyWinners(standings []models.UserWithStats, decided bool, prize float64) []MonthlyWinner {
+	winners := []MonthlyWinner{}
+	if len(standings) == 0 || standings[0].TotalPoints == 0 {
+		return winners
+	}
+	for _, u := range standings {
+		if u.Position != standings[0].Position {
+			break
+		}
+		winners = append(winners, MonthlyWinner{UserID: u.ID, DisplayName: u.DisplayName, Points: u.TotalPoints})
+	}
+	if decided {
+		share := math.Round(prize/float64(len(winners))*100) / 100
+		for i := range winners {
+			winners[i].Prize = share
+		}
+	}
+	return winners
+}
+
+// loadMonths returns the classification of every month of the bolão, from the
+// materialized round scores.
+func (s *ClassificationService) loadMonths(ctx context.Context, bolaoID uuid.UUID) ([]MonthlyClassification, error) {
+	d, rows, _, err := s.loadRoundScores(ctx, bolaoID, 0)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := s.matchRepo.ListAllByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	d.setMatches(matches)
+	scores := d.scoresFromRows(rows)
+	return d.months(func(round int) (map[uuid.UUID]roundScore, bool) {
+		s, ok := scores[round]
+		return s, ok
+	}), nil
+}
+
+// GetMonthlyClassification returns the standings of month ("2026-05"), or ErrMonthNotFound
+// when no round of the bolão falls in it.
+func (s *ClassificationService) GetMonthlyClassification(ctx context.Context, bolaoID uuid.UUID, month string) (*MonthlyClassification, error) {
+	months, err := s.loadMonths(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range months {
+		if months[i].Month == month {
+			return &months[i], nil
+		}
+	}
+	return nil, ErrMonthNotFound
+}
+
+// GetMonthlyWinners returns every month of the bolão with its winners and their prize,
+// without the full standings.
+func (s *ClassificationService) GetMonthlyWinners(ctx context.Context, bolaoID uuid.UUID) ([]MonthlyClassification, error) {
+	months, err := s.loadMonths(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range months {
+		months[i].Standings = nil
+	}
+	return months, nil
+}
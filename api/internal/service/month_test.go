@@ -0,0 +1,70 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestRoundMonth(t *testing.T) {
+	// 23:30 on the last day of May in São Paulo is already June in UTC.
+	lastOfMay := time.Date(2026, 6, 1, 2, 30, 0, 0, time.UTC)
+	june := lastOfMay.Add(48 * time.Hour)
+	if month, ok := roundMonth([]models.Match{matchClosingAt(&june), matchClosingAt(&lastOfMay), matchClosingAt(nil)}); !ok || month != "2026-05" {
+		t.Errorf("month = %q, %v, want 2026-05 from the first close", month, ok)
+	}
+	if _, ok := roundMonth([]models.Match{matchClosingAt(nil)}); ok {
+		t.Error("a round without closes got a month")
+	}
+}
+
+// Round 1 is June's and decided; July has round 2 played and round 3 still open.
+func TestMonths(t *testing.T) {
+	june := time.Date(2026, 6, 20, 19, 0, 0, 0, time.UTC)
+	july := time.Date(2026, 7, 5, 19, 0, 0, 0, time.UTC)
+	r1 := exportMatch("Flamengo", "Vasco", 2, 1, &june)
+	r2 := exportMatch("Bahia", "Remo", 1, 0, &july)
+	r2.Round = 2
+	r3 := models.Match{ID: uuid.New(), Round: 3, HomeTeam: "Palmeiras", AwayTeam: "Santos", MarketClosesAt: timePtr(testNow.Add(time.Hour))}
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	d := testSnapshot([]models.User{ana, bruno}, []models.Match{r1, r2, r3}, []models.Prediction{
+		testPrediction(ana, r1, 2, 1), testPrediction(bruno, r1, 0, 2),
+		testPrediction(ana, r2, 0, 3), testPrediction(bruno, r2, 1, 0),
+	})
+	d.settings.MonthlyPrize = 100
+	d.outrightPoints = map[uuid.UUID]int{bruno.ID: 500}
+
+	months := d.months(func(round int) (map[uuid.UUID]roundScore, bool) {
+		return d.scoreRound(round, finalResults, testNow)
+	})
+	if len(months) != 2 || months[0].Month != "2026-06" || months[1].Month != "2026-07" {
+		t.Fatalf("months = %+v, want June and July", months)
+	}
+	if m := months[0]; !m.Decided || len(m.Winners) != 1 || m.Winners[0].UserID != ana.ID || m.Winners[0].Prize != 100 {
+		t.Errorf("June = %+v, want Ana winning the decided month's 100", m)
+	}
+	m := months[1]
+	if m.Decided || len(m.Rounds) != 2 || len(m.Winners) != 1 || m.Winners[0].UserID != bruno.ID || m.Winners[0].Prize != 0 {
+		t.Errorf("July = %+v, want Bruno leading rounds 2–3 with no prize yet", m)
+	}
+	// The season-long bets aren't a month's.
+	if m.Standings[0].TotalPoints != m.Winners[0].Points || m.Standings[0].OutrightPoints != 0 {
+		t.Errorf("July leader = %+v, want only the month's points", m.Standings[0])
+	}
+}
+
+func TestMonthlyWinners(t *testing.T) {
+	row := func(position, points int) models.UserWithStats {
+		return models.UserWithStats{User: exportUser("P"), Position: position, TotalPoints: points}
+	}
+	shared := []models.UserWithStats{row(1, 30), row(1, 30), row(1, 30), row(4, 10)}
+	winners := monthlyWinners(shared, true, 100)
+	if len(winners) != 3 || winners[0].Prize != 33.33 {
+		t.Errorf("winners = %+v, want three sharing 33.33 each", winners)
+	}
+	if got := monthlyWinners([]models.UserWithStats{row(1, 0), row(2, 0)}, true, 100); len(got) != 0 {
+		t.Errorf("winners = %+v with nobody scoring, want none", got)
+	}
+}
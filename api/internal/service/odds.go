@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sort"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidOdds = errors.New("simulação de chances inválida")
+
+const (
+	// DefaultOddsSimulations and MaxOddsSimulations bound how many seasons Odds plays out.
+	DefaultOddsSimulations = 5000
+	MaxOddsSimulations     = 20000
+
+	// GoalModelPoisson draws each side's goals from a Poisson distribution with the teams'
+	// attack and defence rates; GoalModelAverage gives every team the bolão's average.
+	GoalModelPoisson = "poisson"
+	GoalModelAverage = "average"
+
+	// Before any result, a Brasileirão-like 1.4 home and 1.1 away goals a game.
+	defaultHomeGoals = 1.4
+	defaultAwayGoals = 1.1
+	// priorGames is how many average games each team's rates start from, so a team with
+	// two results isn't rated on them alone.
+	priorGames = 5
+)
+
+// GoalModel draws the score of a match still without a result.
+type GoalModel interface {
+	Score(rng *rand.Rand, m models.Match) (home, away int)
+}
+
+// poissonModel draws each side's goals from a Poisson distribution whose mean is the
+// average for its side scaled by its attack and the opponent's defence. A team without a
+// rate is average.
+type poissonModel struct {
+	homeGoals, awayGoals float64
+	attack, defence      map[string]float64
+}
+
+func (p *poissonModel) Score(rng *rand.Rand, m models.Match) (home, away int) {
+	return poisson(rng, p.homeGoals*p.rate(p.attack, m.HomeTeam)*p.rate(p.defence, m.AwayTeam)),
+		poisson(rng, p.awayGoals*p.rate(p.attack, m.AwayTeam)*p.rate(p.defence, m.HomeTeam))
+}
+
+func (p *poissonModel) rate(rates map[string]float64, team string) float64 {
+	if r, ok := rates[team]; ok {
+		return r
+	}
+	return 1
+}
+
+// poisson draws from a Poisson distribution of mean lambda (Knuth's method, fine for the
+// handful of goals of a football match).
+func poisson(rng *rand.Rand, lambda float64) int {
+	limit, k, p := math.Exp(-lambda), 0, rng.Float64()
+	for p > limit {
+		k++
+		p *= rng.Float64()
+	}
+	return k
+}
+
+// NewGoalModel builds the goal model called name from the bolão's finished matches; the
+// others are ignored.
+func NewGoalModel(name string, matches []models.Match) (GoalModel, error) {
+	p := &poissonModel{homeGoals: defaultHomeGoals, awayGoals: defaultAwayGoals}
+	type tally struct{ games, scored, conceded int }
+	teams := make(map[string]*tally)
+	games, homeGoals, awayGoals := 0, 0, 0
+	for _, m := range matches {
+		r, ok := finalResults(m)
+		if !ok {
+			continue
+		}
+		games++
+		homeGoals += r.home
+		awayGoals += r.away
+		for _, side := range []struct {
+			team             string
+			scored, conceded int
+		}{{m.HomeTeam, r.home, r.away}, {m.AwayTeam, r.away, r.home}} {
+			t := teams[side.team]
+			if t == nil {
+				t = &tally{}
+				teams[side.team] = t
+			}
+			t.games++
+			t.scored += side.scored
+			t.conceded += side.conceded
+		}
+	}
+	if games > 0 {
+		p.homeGoals, p.awayGoals = float64(homeGoals)/float64(games), float64(awayGoals)/float64(games)
+	}
+
+	switch name {
+	case GoalModelAverage:
+		return p, nil
+	case GoalModelPoisson:
+	default:
+		return nil, fmt.Errorf("%w: modelo %q", ErrInvalidOdds, name)
+	}
+	// A team's rates are its goals per game over the average per team, shrunk towards 1
+	// by priorGames average games. A goalless league leaves every team average.
+	perTeam := (p.homeGoals + p.awayGoals) / 2
+	if perTeam == 0 {
+		return p, nil
+	}
+	p.attack = make(map[string]float64, len(teams))
+	p.defence = make(map[string]float64, len(teams))
+	for team, t := range teams {
+		n := float64(t.games + priorGames)
+		p.attack[team] = (float64(t.scored) + priorGames*perTeam) / (n * perTeam)
+		p.defence[team] = (float64(t.conceded) + priorGames*perTeam) / (n * perTeam)
+	}
+	return p, nil
+}
+
+// PlayerOdds is a participant's chances at the end of the season, from 0 to 1.
+type PlayerOdds struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	// Position is the current one, to list the players in.
+	Position int     `json:"position"`
+	First    float64 `json:"first"`
+	Top3     float64 `json:"top3"`
+	Last     float64 `json:"last"`
+}
+
+// OddsReport is the outcome of playing out the rest of the season many times.
+type OddsReport struct {
+	Simulations      int          `json:"simulations"`
+	Model            string       `json:"model"`
+	RemainingMatches int          `json:"remaining_matches"`
+	Players          []PlayerOdds `json:"players"`
+}
+
+// odds plays the matches without a result out simulations times with model and counts
+// where each participant finishes. Every prediction is scored as it would be once the
+// markets close, so a prediction not sent yet counts as the 0×0 it would become (SCORING.md
+// §4). Positions shared under SharedPositions count for every player sharing them.
+func (d *bolaoSnapshot) odds(ctx context.Context, model GoalModel, simulations int, rng *rand.Rand, now time.Time) (*OddsReport, error) {
+	rounds := make([]int, 0, len(d.byRound))
+	for round := range d.byRound {
+		rounds = append(rounds, round)
+	}
+	sort.Ints(rounds)
+
+	// Scored past every market close; rounds with every result in are scored only once.
+	horizon := now
+	var pending []models.Match
+	open := make(map[int]bool)
+	for _, round := range rounds {
+		for _, m := range d.byRound[round] {
+			if c := m.MarketClosesAt; c != nil && c.After(horizon) {
+				horizon = *c
+			}
+			if _, ok := finalResults(m); !ok {
+				pending = append(pending, m)
+				open[round] = true
+			}
+		}
+	}
+	horizon = horizon.Add(time.Second)
+	fixed := make(map[int]map[uuid.UUID]roundScore)
+	for _, round := range rounds {
+		if !open[round] {
+			if scores, ok := d.scoreRound(round, finalResults, horizon); ok {
+				fixed[round] = scores
+			}
+		}
+	}
+
+	hypothetical := make(map[uuid.UUID]HypotheticalResult, len(pending))
+	source := simulatedResults(hypothetical, nil)
+	scoresOf := func(round int) (map[uuid.UUID]roundScore, bool) {
+		if open[round] {
+			return d.scoreRound(round, source, horizon)
+		}
+		scores, ok := fixed[round]
+		return scores, ok
+	}
+
+	type tally struct{ first, top3, last int }
+	counts := make(map[uuid.UUID]*tally, len(d.participants))
+	for _, p := range d.participants {
+		counts[p.ID] = &tally{}
+	}
+	for i := 0; i < simulations; i++ {
+		if i%256 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		for _, m := range pending {
+			home, away := model.Score(rng, m)
+			hypothetical[m.ID] = HypotheticalResult{MatchID: m.ID, HomeGoals: home, AwayGoals: away}
+		}
+		standings := d.aggregate(d.maxRound, scoresOf)
+		if len(standings) == 0 {
+			break
+		}
+		last := standings[len(standings)-1].Position
+		for _, u := range standings {
+			c := counts[u.ID]
+			if u.Position == 1 {
+				c.first++
+			}
+			if u.Position <= 3 {
+				c.top3++
+			}
+			if u.Position == last {
+				c.last++
+			}
+		}
+	}
+
+	report := &OddsReport{Simulations: simulations, RemainingMatches: len(pending), Players: make([]PlayerOdds, 0, len(d.participants))}
+	for _, u := range d.aggregate(d.maxRound, func(round int) (map[uuid.UUID]roundScore, bool) {
+		return d.scoreRound(round, finalResults, now)
+	}) {
+		c := counts[u.ID]
+		report.Players = append(report.Players, PlayerOdds{
+			UserID:      u.ID,
+			DisplayName: u.DisplayName,
+			Position:    u.Position,
+			First:       float64(c.first) / float64(simulations),
+			Top3:        float64(c.top3) / float64(simulations),
+			Last:        float64(c.last) / float64(simulations),
+		})
+	}
+	return report, nil
+}
+
+// Odds estimates each participant's chances of finishing first, in the top 3 and last by
+// playing the rest of the season out simulations times with the goal model called model,
+// rated on the bolão's own results.
+func (s *ClassificationService) Odds(ctx context.Context, bolaoID uuid.UUID, simulations int, model string) (*OddsReport, error) {
+	if simulations < 1 || simulations > MaxOddsSimulations {
+		return nil, fmt.Errorf("%w: de 1 a %d simulações", ErrInvalidOdds, MaxOddsSimulations)
+	}
+	d, err := s.loadSnapshot(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	var matches []models.Match
+	for _, ms := range d.byRound {
+		matches = append(matches, ms...)
+	}
+	goals, err := NewGoalModel(model, matches)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	rng := rand.New(rand.NewPCG(uint64(now.UnixNano()), uint64(len(matches))))
+	report, err := d.odds(ctx, goals, simulations, rng, now)
+	if err != nil {
+		return nil, err
+	}
+	report.Model = model
+	return report, nil
+}
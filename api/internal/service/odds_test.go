@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// fixedScore is a goal model that always draws the same score.
+type fixedScore struct{ home, away int }
+
+func (f fixedScore) Score(*rand.Rand, models.Match) (int, int) { return f.home, f.away }
+
+func TestPoisson(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	const n, lambda = 20000, 1.5
+	sum := 0
+	for i := 0; i < n; i++ {
+		sum += poisson(rng, lambda)
+	}
+	if mean := float64(sum) / n; math.Abs(mean-lambda) > 0.05 {
+		t.Errorf("mean = %.3f, want about %.1f", mean, lambda)
+	}
+}
+
+func TestNewGoalModel(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	matches := []models.Match{
+		exportMatch("Flamengo", "Vasco", 4, 0, closed),
+		exportMatch("Vasco", "Bahia", 0, 3, closed),
+		{ID: uuid.New(), HomeTeam: "Flamengo", AwayTeam: "Bahia"},
+	}
+	model, err := NewGoalModel(GoalModelPoisson, matches)
+	if err != nil {
+		t.Fatalf("NewGoalModel: %v", err)
+	}
+	p := model.(*poissonModel)
+	if p.homeGoals != 2 || p.awayGoals != 1.5 {
+		t.Errorf("averages = %v home, %v away, want 2 and 1.5 from the two results", p.homeGoals, p.awayGoals)
+	}
+	if p.attack["Flamengo"] <= 1 || p.defence["Vasco"] <= 1 || p.attack["Vasco"] >= 1 {
+		t.Errorf("rates = attack %v, defence %v, want Flamengo scoring and Vasco conceding above average", p.attack, p.defence)
+	}
+	if p.rate(p.attack, "Santos") != 1 {
+		t.Error("a team without results isn't average")
+	}
+
+	average, _ := NewGoalModel(GoalModelAverage, nil)
+	if a := average.(*poissonModel); a.homeGoals != defaultHomeGoals || a.attack != nil {
+		t.Errorf("average model = %+v, want the default averages and no rates", a)
+	}
+	if _, err := NewGoalModel("dados", matches); !errors.Is(err, ErrInvalidOdds) {
+		t.Errorf("err = %v, want ErrInvalidOdds", err)
+	}
+}
+
+// Ana leads after round 1. In round 2, still open, Bruno bet on 3×1 and Ana hasn't bet:
+// she'll be on the 0×0 she gets at the close.
+func TestOdds(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	r1 := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	r2 := models.Match{ID: uuid.New(), Round: 2, HomeTeam: "Bahia", AwayTeam: "Remo", MarketClosesAt: timePtr(testNow.Add(time.Hour))}
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	d := testSnapshot([]models.User{ana, bruno}, []models.Match{r1, r2}, []models.Prediction{
+		testPrediction(ana, r1, 2, 1), testPrediction(bruno, r1, 1, 0),
+		testPrediction(bruno, r2, 3, 1),
+	})
+
+	for _, tt := range []struct {
+		score fixedScore
+		first models.User
+	}{
+		{fixedScore{3, 1}, bruno},
+		{fixedScore{0, 0}, ana},
+	} {
+		report, err := d.odds(context.Background(), tt.score, 10, rand.New(rand.NewPCG(1, 2)), testNow)
+		if err != nil {
+			t.Fatalf("odds: %v", err)
+		}
+		if report.RemainingMatches != 1 || report.Players[0].UserID != ana.ID {
+			t.Errorf("report = %+v, want one match left and Ana listed first", report)
+		}
+		for _, p := range report.Players {
+			want := PlayerOdds{First: 0, Top3: 1, Last: 1}
+			if p.UserID == tt.first.ID {
+				want = PlayerOdds{First: 1, Top3: 1, Last: 0}
+			}
+			if p.First != want.First || p.Top3 != want.Top3 || p.Last != want.Last {
+				t.Errorf("%d×%d: %s = %+v, want first %v, last %v", tt.score.home, tt.score.away, p.DisplayName, p, want.First, want.Last)
+			}
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/bolao-app/api/internal/constants"
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidTeamPicks = errors.New("seleção de times inválida")
+	ErrOutrightOpen     = errors.New("o resultado só pode ser registrado após o prazo da aposta")
+)
+
+// ErrUnresolvedOutrights is returned by FinishActive when season-long bets still have no
+// outcome and force wasn't requested.
+type ErrUnresolvedOutrights struct {
+	Questions []string
+	Count     int
+}
+
+func (e *ErrUnresolvedOutrights) Error() string {
+	return fmt.Sprintf("%d aposta(s) de temporada sem resultado definido", e.Count)
+}
+
+// ValidateTeamPicks checks an answer or an outcome of an outright question: exactly picks
+// distinct teams, all from constants.Teams.
+func ValidateTeamPicks(teams []string, picks int) error {
+	if len(teams) != picks {
+		return fmt.Errorf("%w: escolha exatamente %d time(s)", ErrInvalidTeamPicks, picks)
+	}
+	seen := make(map[string]bool, len(teams))
+	for _, t := range teams {
+		if !slices.Contains(constants.Teams, t) {
+			return fmt.Errorf("%w: time inválido: %s", ErrInvalidTeamPicks, t)
+		}
+		if seen[t] {
+			return fmt.Errorf("%w: time repetido: %s", ErrInvalidTeamPicks, t)
+		}
+		seen[t] = true
+	}
+	return nil
+}
+
+// CheckOutrightOutcome refuses an outcome before the question closes: List shows it to every
+// player, who could still change their picks to match it.
+func CheckOutrightOutcome(q models.OutrightQuestion, now time.Time) error {
+	if !now.After(q.ClosesAt) {
+		return ErrOutrightOpen
+	}
+	return nil
+}
+
+// ScoreOutright pays PointsPerHit for every picked team that is in the outcome. The order
+// of the picks does not matter: picking the G4 is about who is in it, not the positions.
+// An unresolved question scores nothing yet.
+func ScoreOutright(q models.OutrightQuestion, teams []string) int {
+	if q.Outcome == nil {
+		return 0
+	}
+	hits := 0
+	for _, t := range teams {
+		if slices.Contains(q.Outcome, t) {
+			hits++
+		}
+	}
+	return hits * q.PointsPerHit
+}
+
+// outrightPointsByUser totals every participant's season-long bets.
+func outrightPointsByUser(questions []models.OutrightQuestion, answers []models.OutrightAnswer) map[uuid.UUID]int {
+	byID := make(map[uuid.UUID]models.OutrightQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+	points := make(map[uuid.UUID]int)
+	for _, a := range answers {
+		if q, ok := byID[a.QuestionID]; ok {
+			points[a.UserID] += ScoreOutright(q, a.Teams)
+		}
+	}
+	return points
+}
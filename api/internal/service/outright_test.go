@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestValidateTeamPicks(t *testing.T) {
+	tests := []struct {
+		name  string
+		teams []string
+		picks int
+		ok    bool
+	}{
+		{"one champion", []string{"Flamengo"}, 1, true},
+		{"four relegated", []string{"Remo", "Mirassol", "Chapecoense", "Coritiba"}, 4, true},
+		{"too few", []string{"Remo"}, 4, false},
+		{"too many", []string{"Flamengo", "Palmeiras"}, 1, false},
+		{"unknown team", []string{"Real Madrid"}, 1, false},
+		{"repeated team", []string{"Remo", "Remo"}, 2, false},
+	}
+	for _, tt := range tests {
+		err := ValidateTeamPicks(tt.teams, tt.picks)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: ValidateTeamPicks = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidTeamPicks) {
+			t.Errorf("%s: error %v does not wrap ErrInvalidTeamPicks", tt.name, err)
+		}
+	}
+}
+
+func TestCheckOutrightOutcome(t *testing.T) {
+	q := models.OutrightQuestion{Picks: 1, PointsPerHit: 20, ClosesAt: testNow}
+	if err := CheckOutrightOutcome(q, testNow.Add(-time.Minute)); !errors.Is(err, ErrOutrightOpen) {
+		t.Errorf("outcome before the close: err = %v, want ErrOutrightOpen", err)
+	}
+	if err := CheckOutrightOutcome(q, testNow); !errors.Is(err, ErrOutrightOpen) {
+		t.Errorf("outcome at the close: err = %v, want ErrOutrightOpen", err)
+	}
+	if err := CheckOutrightOutcome(q, testNow.Add(time.Minute)); err != nil {
+		t.Errorf("outcome after the close: err = %v, want nil", err)
+	}
+}
+
+func TestScoreOutright(t *testing.T) {
+	g4 := models.OutrightQuestion{Picks: 4, PointsPerHit: 5, Outcome: []string{"Flamengo", "Palmeiras", "Botafogo", "Cruzeiro"}}
+
+	// Order does not matter, only membership: three of the four are in the G4.
+	got := ScoreOutright(g4, []string{"Cruzeiro", "Flamengo", "São Paulo", "Palmeiras"})
+	if got != 15 {
+		t.Errorf("3 hits at 5 points = %d, want 15", got)
+	}
+
+	unresolved := models.OutrightQuestion{Picks: 1, PointsPerHit: 20}
+	if got := ScoreOutright(unresolved, []string{"Flamengo"}); got != 0 {
+		t.Errorf("an unresolved question scored %d, want 0", got)
+	}
+}
+
+func TestOutrightPointsByUser(t *testing.T) {
+	champion := models.OutrightQuestion{ID: uuid.New(), Picks: 1, PointsPerHit: 20, Outcome: []string{"Palmeiras"}}
+	relegated := models.OutrightQuestion{ID: uuid.New(), Picks: 2, PointsPerHit: 5, Outcome: []string{"Remo", "Mirassol"}}
+	ana, bruno := uuid.New(), uuid.New()
+
+	points := outrightPointsByUser([]models.OutrightQuestion{champion, relegated}, []models.OutrightAnswer{
+		{QuestionID: champion.ID, UserID: ana, Teams: []string{"Palmeiras"}},
+		{QuestionID: relegated.ID, UserID: ana, Teams: []string{"Remo", "Santos"}},
+		{QuestionID: champion.ID, UserID: bruno, Teams: []string{"Flamengo"}},
+		{QuestionID: uuid.New(), UserID: bruno, Teams: []string{"Flamengo"}}, // unknown question
+	})
+
+	if points[ana] != 25 || points[bruno] != 0 {
+		t.Errorf("points = ana %d, bruno %d, want 25 and 0", points[ana], points[bruno])
+	}
+}
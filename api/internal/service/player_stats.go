@@ -0,0 +1,218 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// favoriteScoresShown is how many of a player's most predicted scores PlayerStats lists.
+const favoriteScoresShown = 5
+
+// ScoreFrequency is a score and how many times a player predicted it.
+type ScoreFrequency struct {
+	Home  int `json:"home"`
+	Away  int `json:"away"`
+	Count int `json:"count"`
+}
+
+// RoundPoints is a player's points in a round, bonuses included, before the round's weight.
+type RoundPoints struct {
+	Round  int `json:"round"`
+	Points int `json:"points"`
+}
+
+// Streak is a run of consecutive finished rounds; Length is 0 when there is none.
+type Streak struct {
+	Length    int `json:"length"`
+	FromRound int `json:"from_round"`
+	ToRound   int `json:"to_round"`
+}
+
+// PlayerStats is one participant's analytics over a bolão.
+type PlayerStats struct {
+	User models.User `json:"user"`
+	// Predictions counts the matches with a final result the player has a prediction for,
+	// the 0×0 filled in for a missing one included: the base of the two rates.
+	Predictions    int     `json:"predictions"`
+	ExactScores    int     `json:"exact_scores"`
+	ExactScoreRate float64 `json:"exact_score_rate"`
+	// CorrectResults counts the predictions that called the outcome, exact scores included.
+	CorrectResults    int     `json:"correct_results"`
+	CorrectResultRate float64 `json:"correct_result_rate"`
+	// FavoriteScores are the scores the player predicted most on closed markets, most
+	// frequent first; the 0×0 filled in for them doesn't count.
+	FavoriteScores []ScoreFrequency `json:"favorite_scores"`
+	// AvgGoalsPredicted and AvgGoalsActual are the goals a match the player predicted
+	// themselves and the goals it ended with, over the matches with a final result.
+	AvgGoalsPredicted float64 `json:"avg_goals_predicted"`
+	AvgGoalsActual    float64 `json:"avg_goals_actual"`
+	// RoundTotalHits counts the finished rounds that paid the total-goals bonus
+	// (PointsRoundTotalGoals), out of RoundsFinished.
+	RoundTotalHits int `json:"round_total_hits"`
+	RoundsFinished int `json:"rounds_finished"`
+	// AutoFilled counts the 0×0 filled in for the predictions never sent on closed markets.
+	AutoFilled int `json:"auto_filled"`
+	// BestRound and WorstRound are over the finished rounds, the earliest among equals;
+	// nil before any.
+	BestRound  *RoundPoints `json:"best_round"`
+	WorstRound *RoundPoints `json:"worst_round"`
+	// AboveMedianStreak is the longest run of finished rounds in which the player scored
+	// more than the group's median, the earliest among equals.
+	AboveMedianStreak Streak `json:"above_median_streak"`
+}
+
+// median is the middle of values, the mean of the two middle ones for an even count.
+func median(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]int(nil), values...)
+	sort.Ints(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return float64(sorted[mid])
+	}
+	return float64(sorted[mid-1]+sorted[mid]) / 2
+}
+
+// playerStats works out user's analytics. The rates and goal averages read the matches with
+// a final result; the rounds are the finished ones, every match with a final result.
+func (d *bolaoSnapshot) playerStats(user models.User, now time.Time) *PlayerStats {
+	ps := &PlayerStats{User: user, FavoriteScores: []ScoreFrequency{}}
+
+	var matches []models.Match
+	for _, ms := range d.byRound {
+		matches = append(matches, ms...)
+	}
+	existing := make([]models.Prediction, 0, len(d.predictions[user.ID]))
+	for matchID, p := range d.predictions[user.ID] {
+		existing = append(existing, models.Prediction{UserID: user.ID, MatchID: matchID, HomeGoals: p.Home, AwayGoals: p.Away})
+	}
+	byID := make(map[uuid.UUID]models.Match, len(matches))
+	for _, m := range matches {
+		byID[m.ID] = m
+	}
+
+	frequency := make(map[[2]int]int)
+	predictedGoals, actualGoals, ownPredictions := 0, 0, 0
+	for _, p := range FillMissingPredictions(matches, user.ID, existing, now) {
+		if p.AutoFilled {
+			ps.AutoFilled++
+		} else if MarketClosed(byID[p.MatchID], now) {
+			// Open markets stay out: the other players can't see those picks yet.
+			frequency[[2]int{p.HomeGoals, p.AwayGoals}]++
+		}
+		r, ok := finalResults(byID[p.MatchID])
+		if !ok {
+			continue
+		}
+		ps.Predictions++
+		if p.HomeGoals == r.home && p.AwayGoals == r.away {
+			ps.ExactScores++
+		}
+		if winnerSide(p.HomeGoals, p.AwayGoals) == winnerSide(r.home, r.away) {
+			ps.CorrectResults++
+		}
+		if !p.AutoFilled {
+			ownPredictions++
+			predictedGoals += p.HomeGoals + p.AwayGoals
+			actualGoals += r.home + r.away
+		}
+	}
+	if ps.Predictions > 0 {
+		ps.ExactScoreRate = float64(ps.ExactScores) / float64(ps.Predictions)
+		ps.CorrectResultRate = float64(ps.CorrectResults) / float64(ps.Predictions)
+	}
+	if ownPredictions > 0 {
+		ps.AvgGoalsPredicted = float64(predictedGoals) / float64(ownPredictions)
+		ps.AvgGoalsActual = float64(actualGoals) / float64(ownPredictions)
+	}
+	for score, n := range frequency {
+		ps.FavoriteScores = append(ps.FavoriteScores, ScoreFrequency{Home: score[0], Away: score[1], Count: n})
+	}
+	sort.Slice(ps.FavoriteScores, func(i, j int) bool {
+		a, b := ps.FavoriteScores[i], ps.FavoriteScores[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Home+a.Away != b.Home+b.Away {
+			return a.Home+a.Away < b.Home+b.Away
+		}
+		return a.Home > b.Home
+	})
+	if len(ps.FavoriteScores) > favoriteScoresShown {
+		ps.FavoriteScores = ps.FavoriteScores[:favoriteScoresShown]
+	}
+
+	rounds := make([]int, 0, len(d.byRound))
+	for round, ms := range d.byRound {
+		finished := true
+		for _, m := range ms {
+			if _, ok := finalResults(m); !ok {
+				finished = false
+				break
+			}
+		}
+		if finished {
+			rounds = append(rounds, round)
+		}
+	}
+	sort.Ints(rounds)
+
+	run := Streak{}
+	for _, round := range rounds {
+		scores, ok := d.scoreRound(round, finalResults, now)
+		if !ok {
+			continue
+		}
+		mine := scores[user.ID]
+		ps.RoundsFinished++
+		ps.RoundTotalHits += mine.roundTotalHits
+		rp := RoundPoints{Round: round, Points: mine.points}
+		if ps.BestRound == nil || rp.Points > ps.BestRound.Points {
+			best := rp
+			ps.BestRound = &best
+		}
+		if ps.WorstRound == nil || rp.Points < ps.WorstRound.Points {
+			worst := rp
+			ps.WorstRound = &worst
+		}
+
+		group := make([]int, 0, len(d.participants))
+		for _, p := range d.participants {
+			group = append(group, scores[p.ID].points)
+		}
+		if float64(mine.points) <= median(group) {
+			run = Streak{}
+			continue
+		}
+		if run.Length == 0 {
+			run.FromRound = round
+		}
+		run.Length++
+		run.ToRound = round
+		if run.Length > ps.AboveMedianStreak.Length {
+			ps.AboveMedianStreak = run
+		}
+	}
+	return ps
+}
+
+// GetPlayerStats returns userID's analytics over the bolão, or ErrNotParticipant when they
+// aren't one of its participants.
+func (s *ClassificationService) GetPlayerStats(ctx context.Context, bolaoID, userID uuid.UUID) (*PlayerStats, error) {
+	d, err := s.loadSnapshot(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range d.participants {
+		if p.ID == userID {
+			return d.playerStats(p.User, time.Now()), nil
+		}
+	}
+	return nil, ErrNotParticipant
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Three finished rounds of one match and a fourth closed without a result. Ana gets rounds
+// 1 and 2 exactly, misses round 3's prediction and bets 2×1 again in round 4. Round 5 is
+// still open.
+func TestPlayerStats(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	r1 := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	r2 := exportMatch("Bahia", "Remo", 1, 1, closed)
+	r2.Round = 2
+	r3 := exportMatch("Palmeiras", "Santos", 3, 0, closed)
+	r3.Round = 3
+	r4 := models.Match{ID: uuid.New(), Round: 4, HomeTeam: "Grêmio", AwayTeam: "Inter", MarketClosesAt: closed}
+	r5 := models.Match{ID: uuid.New(), Round: 5, HomeTeam: "Bahia", AwayTeam: "Vitória", MarketClosesAt: timePtr(testNow.Add(time.Hour))}
+	ana, bruno, caio := exportUser("Ana"), exportUser("Bruno"), exportUser("Caio")
+	d := testSnapshot([]models.User{ana, bruno, caio}, []models.Match{r1, r2, r3, r4, r5}, []models.Prediction{
+		testPrediction(ana, r1, 2, 1), testPrediction(bruno, r1, 1, 0), testPrediction(caio, r1, 0, 2),
+		testPrediction(ana, r2, 1, 1), testPrediction(bruno, r2, 0, 0), testPrediction(caio, r2, 2, 0),
+		testPrediction(bruno, r3, 3, 0), testPrediction(caio, r3, 1, 0),
+		testPrediction(ana, r4, 2, 1), testPrediction(ana, r5, 3, 3),
+	})
+
+	ps := d.playerStats(ana, testNow)
+	if ps.Predictions != 3 || ps.ExactScores != 2 || ps.CorrectResults != 2 || ps.AutoFilled != 1 {
+		t.Errorf("counts = %d predictions, %d exact, %d correct, %d auto-filled; want 3, 2, 2, 1",
+			ps.Predictions, ps.ExactScores, ps.CorrectResults, ps.AutoFilled)
+	}
+	if want := 2.0 / 3; ps.ExactScoreRate != want || ps.CorrectResultRate != want {
+		t.Errorf("rates = %v, %v, want %v", ps.ExactScoreRate, ps.CorrectResultRate, want)
+	}
+	if len(ps.FavoriteScores) != 2 || ps.FavoriteScores[0] != (ScoreFrequency{Home: 2, Away: 1, Count: 2}) {
+		t.Errorf("favorite scores = %+v, want 2×1 twice first, no auto-filled 0×0 and no open-market 3×3", ps.FavoriteScores)
+	}
+	// Over rounds 1 and 2, the ones with a result Ana predicted herself.
+	if ps.AvgGoalsPredicted != 2.5 || ps.AvgGoalsActual != 2.5 {
+		t.Errorf("average goals = %v predicted, %v actual, want 2.5 and 2.5", ps.AvgGoalsPredicted, ps.AvgGoalsActual)
+	}
+
+	hits := 0
+	points := make(map[int]int)
+	for round := 1; round <= 3; round++ {
+		scores, _ := d.scoreRound(round, finalResults, testNow)
+		hits += scores[ana.ID].roundTotalHits
+		points[round] = scores[ana.ID].points
+	}
+	if ps.RoundsFinished != 3 || ps.RoundTotalHits != hits {
+		t.Errorf("rounds = %d finished, %d total-goals hits, want 3 and %d", ps.RoundsFinished, ps.RoundTotalHits, hits)
+	}
+	best := 1
+	if points[2] > points[1] {
+		best = 2
+	}
+	if ps.BestRound == nil || ps.BestRound.Round != best || ps.WorstRound == nil || *ps.WorstRound != (RoundPoints{Round: 3, Points: points[3]}) {
+		t.Errorf("best, worst = %+v, %+v, want round %d and round 3", ps.BestRound, ps.WorstRound, best)
+	}
+	if ps.AboveMedianStreak != (Streak{Length: 2, FromRound: 1, ToRound: 2}) {
+		t.Errorf("streak = %+v, want rounds 1–2", ps.AboveMedianStreak)
+	}
+
+	if ps := d.playerStats(caio, testNow); ps.AboveMedianStreak.Length != 0 || ps.AutoFilled != 1 {
+		t.Errorf("Caio = %+v, want no streak and round 4 auto-filled", ps)
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if got := median([]int{7, 1, 4}); got != 4 {
+		t.Errorf("median = %v, want 4", got)
+	}
+	if got := median([]int{10, 1, 4, 3}); got != 3.5 {
+		t.Errorf("median = %v, want 3.5", got)
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Tiebreak criteria a bolão can order in models.BolaoSettings.Tiebreakers. Points always
+// come first; these only separate players on the same points.
+const (
+	TiebreakExactScores        = "exact_scores"        // mais placares exatos
+	TiebreakCorrectResults     = "correct_results"     // mais resultados certos
+	TiebreakRoundsWon          = "rounds_won"          // mais rodadas vencidas
+	TiebreakHeadToHead         = "head_to_head"        // mais rodadas com mais pontos que o outro
+	TiebreakFewestMissed       = "fewest_missed"       // menos palpites preenchidos com 0×0
+	TiebreakRoundTotalHits     = "round_total_hits"    // mais acertos do total de gols da rodada
+	TiebreakEarliestSubmission = "earliest_submission" // primeiro palpite enviado mais cedo
+)
+
+var tiebreakCriteria = []string{
+	TiebreakExactScores, TiebreakCorrectResults, TiebreakRoundsWon, TiebreakHeadToHead,
+	TiebreakFewestMissed, TiebreakRoundTotalHits, TiebreakEarliestSubmission,
+}
+
+var ErrInvalidTiebreakers = errors.New("critérios de desempate inválidos")
+
+// ValidateTiebreakers accepts an ordered list of known criteria without repeats. An empty
+// list is valid: it means the default chain.
+func ValidateTiebreakers(criteria []string) error {
+	seen := make(map[string]bool, len(criteria))
+	for _, c := range criteria {
+		if !slices.Contains(tiebreakCriteria, c) {
+			return fmt.Errorf("%w: critério desconhecido: %s", ErrInvalidTiebreakers, c)
+		}
+		if seen[c] {
+			return fmt.Errorf("%w: critério repetido: %s", ErrInvalidTiebreakers, c)
+		}
+		seen[c] = true
+	}
+	return nil
+}
+
+// Standing is what the ranking looks at. When ranking a single round, RoundsWon is 0 and
+// RoundPoints is nil, so those criteria never separate anyone there.
+type Standing struct {
+	Points         int
+	ExactScores    int
+	CorrectResults int
+	RoundsWon      int
+	// Missed counts the predictions filled in with 0×0 because the player didn't send one.
+	Missed int
+	// RoundTotalHits counts the rounds whose total goals the player got right.
+	RoundTotalHits int
+	// FirstSubmission is when the player's first prediction in scope was sent; nil when
+	// they sent none.
+	FirstSubmission *time.Time
+	// RoundPoints is the player's points per round, for head-to-head.
+	RoundPoints map[int]int
+	// HeadToHead is what head-to-head compares: the rounds the player outscored each of the
+	// players tied with them before it, added up. rankEntries fills it in.
+	HeadToHead int
+}
+
+// Ranking orders standings: points, then the bolão's tiebreak chain. With Shared, players
+// the chain can't separate get the same position.
+type Ranking struct {
+	Criteria []string
+	Shared   bool
+}
+
+// RankingFor is the ranking a bolão uses. Its own chain wins; without one, the default
+// chain applies, whatever the scoring engine.
+func RankingFor(settings models.BolaoSettings) Ranking {
+	criteria := settings.Tiebreakers
+	if len(criteria) == 0 {
+		criteria = defaultTiebreakers()
+	}
+	return Ranking{Criteria: criteria, Shared: settings.SharedPositions}
+}
+
+// Compare is negative when a ranks above b and 0 when the chain can't separate them.
+func (r Ranking) Compare(a, b Standing) int {
+	if a.Points != b.Points {
+		return b.Points - a.Points
+	}
+	for _, c := range r.Criteria {
+		if d := compareCriterion(c, a, b); d != 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func compareCriterion(criterion string, a, b Standing) int {
+	switch criterion {
+	case TiebreakExactScores:
+		return b.ExactScores - a.ExactScores
+	case TiebreakCorrectResults:
+		return b.CorrectResults - a.CorrectResults
+	case TiebreakRoundsWon:
+		return b.RoundsWon - a.RoundsWon
+	case TiebreakHeadToHead:
+		return b.HeadToHead - a.HeadToHead
+	case TiebreakFewestMissed:
+		return a.Missed - b.Missed
+	case TiebreakRoundTotalHits:
+		return b.RoundTotalHits - a.RoundTotalHits
+	case TiebreakEarliestSubmission:
+		switch {
+		case a.FirstSubmission == nil && b.FirstSubmission == nil:
+			return 0
+		case a.FirstSubmission == nil:
+			return 1
+		case b.FirstSubmission == nil:
+			return -1
+		}
+		return a.FirstSubmission.Compare(*b.FirstSubmission)
+	}
+	return 0
+}
+
+// roundsOutscored counts the rounds a scored more than b in. A round missing from b counts
+// as 0 points there; one missing from a can't be won.
+func roundsOutscored(a, b map[int]int) int {
+	wins := 0
+	for round, pa := range a {
+		if pa > b[round] {
+			wins++
+		}
+	}
+	return wins
+}
+
+// headToHeadLeague fills in HeadToHead as a mini-league: each player's rounds won against
+// every other player tied with them on points and on the criteria before head-to-head.
+// Comparing players pair by pair isn't transitive once three are tied: A can beat B, B
+// beat C and C beat A.
+func (r Ranking) headToHeadLeague(standings []Standing) {
+	i := slices.Index(r.Criteria, TiebreakHeadToHead)
+	if i < 0 {
+		return
+	}
+	before := Ranking{Criteria: r.Criteria[:i]}
+	for a := range standings {
+		standings[a].HeadToHead = 0
+		for b := range standings {
+			if a != b && before.Compare(standings[a], standings[b]) == 0 {
+				standings[a].HeadToHead += roundsOutscored(standings[a].RoundPoints, standings[b].RoundPoints)
+			}
+		}
+	}
+}
+
+// defaultTiebreakers is the chain of SCORING.md §5.
+func defaultTiebreakers() []string {
+	return []string{TiebreakExactScores, TiebreakCorrectResults, TiebreakRoundsWon}
+}
+
+// rankEntries sorts entries by the ranking and returns each one's position. The sort is
+// stable: players the chain can't separate keep the order they came in — by display name,
+// as ListParticipants returns them — and, without Shared, get consecutive positions.
+func rankEntries[T any](r Ranking, entries []T, standing func(T) Standing) []int {
+	standings := make([]Standing, len(entries))
+	order := make([]int, len(entries))
+	for i, e := range entries {
+		standings[i] = standing(e)
+		order[i] = i
+	}
+	r.headToHeadLeague(standings)
+	sort.SliceStable(order, func(i, j int) bool {
+		return r.Compare(standings[order[i]], standings[order[j]]) < 0
+	})
+
+	sorted := make([]T, len(entries))
+	positions := make([]int, len(entries))
+	for i, k := range order {
+		sorted[i] = entries[k]
+		positions[i] = i + 1
+		if r.Shared && i > 0 && r.Compare(standings[order[i-1]], standings[k]) == 0 {
+			positions[i] = positions[i-1]
+		}
+	}
+	copy(entries, sorted)
+	return positions
+}
+
+// rankUsers sorts standings rows in place and fills in Position. standings holds what the
+// ranking needs beyond the row's own counters; a user missing from it has only those.
+func rankUsers(r Ranking, result []models.UserWithStats, standings map[uuid.UUID]Standing) {
+	standing := func(u models.UserWithStats) Standing {
+		s := standings[u.ID]
+		s.Points, s.ExactScores, s.CorrectResults, s.RoundsWon = u.TotalPoints, u.ExactScores, u.CorrectResults, u.RoundsWon
+		s.Missed, s.RoundTotalHits = u.MissedPredictions, u.RoundTotalHits
+		return s
+	}
+	positions := rankEntries(r, result, standing)
+	for i := range result {
+		result[i].Position = positions[i]
+	}
+}
+
+// firstSubmissions indexes, per user and round, when the user's first prediction of the
+// round was sent. roundOf maps a match to its round; predictions of other matches are
+// ignored.
+func firstSubmissions(predictions []models.Prediction, roundOf map[uuid.UUID]int) map[uuid.UUID]map[int]time.Time {
+	index := make(map[uuid.UUID]map[int]time.Time)
+	for _, p := range predictions {
+		round, ok := roundOf[p.MatchID]
+		if !ok {
+			continue
+		}
+		if index[p.UserID] == nil {
+			index[p.UserID] = make(map[int]time.Time)
+		}
+		if t, ok := index[p.UserID][round]; !ok || p.CreatedAt.Before(t) {
+			index[p.UserID][round] = p.CreatedAt
+		}
+	}
+	return index
+}
+
+// earliest returns the earliest of t and current, treating a nil current as none yet.
+func earliest(current *time.Time, t time.Time) *time.Time {
+	if current == nil || t.Before(*current) {
+		return &t
+	}
+	return current
+}
@@ -0,0 +1,206 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+var defaultRanking = RankingFor(models.BolaoSettings{})
+
+func TestDefaultRankingCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Standing
+		want int // sign
+	}{
+		{"points", Standing{Points: 30}, Standing{Points: 20, ExactScores: 5}, -1},
+		{"exact scores", Standing{Points: 20, ExactScores: 1}, Standing{Points: 20, ExactScores: 2}, 1},
+		{"correct results", Standing{Points: 20, ExactScores: 1, CorrectResults: 3}, Standing{Points: 20, ExactScores: 1, CorrectResults: 2}, -1},
+		{"rounds won", Standing{Points: 20, RoundsWon: 1}, Standing{Points: 20, RoundsWon: 2}, 1},
+		{"full tie", Standing{Points: 20, RoundsWon: 1, Missed: 3}, Standing{Points: 20, RoundsWon: 1}, 0},
+	}
+	for _, tt := range tests {
+		got := defaultRanking.Compare(tt.a, tt.b)
+		if (got < 0) != (tt.want < 0) || (got > 0) != (tt.want > 0) {
+			t.Errorf("%s: Compare = %d, want sign %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRankingCustomChain(t *testing.T) {
+	early := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	late := early.Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		criteria []string
+		a, b     Standing
+		want     int // sign
+	}{
+		// The bolão's chain replaces the default: exact scores no longer come first.
+		{"fewest missed before exact scores", []string{TiebreakFewestMissed, TiebreakExactScores},
+			Standing{Points: 20, Missed: 0}, Standing{Points: 20, Missed: 2, ExactScores: 4}, -1},
+		{"round total hits", []string{TiebreakRoundTotalHits},
+			Standing{Points: 20, RoundTotalHits: 1}, Standing{Points: 20, RoundTotalHits: 2}, 1},
+		{"earliest submission", []string{TiebreakEarliestSubmission},
+			Standing{Points: 20, FirstSubmission: &early}, Standing{Points: 20, FirstSubmission: &late}, -1},
+		{"never submitted ranks last", []string{TiebreakEarliestSubmission},
+			Standing{Points: 20}, Standing{Points: 20, FirstSubmission: &late}, 1},
+		{"points always first", []string{TiebreakFewestMissed},
+			Standing{Points: 21, Missed: 5}, Standing{Points: 20}, -1},
+	}
+	for _, tt := range tests {
+		r := RankingFor(models.BolaoSettings{Tiebreakers: tt.criteria})
+		got := r.Compare(tt.a, tt.b)
+		if (got < 0) != (tt.want < 0) || (got > 0) != (tt.want > 0) {
+			t.Errorf("%s: Compare = %d, want sign %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRoundsOutscored(t *testing.T) {
+	a := map[int]int{1: 10, 2: 5, 3: 15}
+	b := map[int]int{1: 5, 2: 10, 3: 10, 4: 5}
+	if got := roundsOutscored(a, b); got != 2 {
+		t.Errorf("a outscored b in %d rounds, want 2", got)
+	}
+	// Round 4 is b's only: it counts as 0 for a.
+	if got := roundsOutscored(b, a); got != 2 {
+		t.Errorf("b outscored a in %d rounds, want 2", got)
+	}
+}
+
+// rankNames ranks users on points and round points alone and returns their names in order
+// with their positions.
+func rankNames(r Ranking, users []models.UserWithStats, rounds map[uuid.UUID]map[int]int) ([]string, []int) {
+	standings := make(map[uuid.UUID]Standing, len(rounds))
+	for id, rp := range rounds {
+		standings[id] = Standing{RoundPoints: rp}
+	}
+	result := append([]models.UserWithStats(nil), users...)
+	rankUsers(r, result, standings)
+	names, positions := make([]string, len(result)), make([]int, len(result))
+	for i, u := range result {
+		names[i], positions[i] = u.DisplayName, u.Position
+	}
+	return names, positions
+}
+
+func TestHeadToHead(t *testing.T) {
+	user := func(name string, exact int) models.UserWithStats {
+		return models.UserWithStats{User: models.User{ID: uuid.New(), DisplayName: name}, TotalPoints: 30, ExactScores: exact}
+	}
+	ana, bruno := user("Ana", 1), user("Bruno", 2)
+	// Ana beat Bruno in rounds 1 and 3, Bruno beat her in round 2; round 4 is Bruno's only,
+	// so it counts as 0 for Ana: 2×2, a tie the next criterion breaks.
+	r := RankingFor(models.BolaoSettings{Tiebreakers: []string{TiebreakHeadToHead, TiebreakExactScores}})
+	names, _ := rankNames(r, []models.UserWithStats{ana, bruno}, map[uuid.UUID]map[int]int{
+		ana.ID: {1: 10, 2: 5, 3: 15}, bruno.ID: {1: 5, 2: 10, 3: 10, 4: 5},
+	})
+	if names[0] != "Bruno" {
+		t.Errorf("order = %v, want Bruno first on exact scores", names)
+	}
+	names, _ = rankNames(r, []models.UserWithStats{ana, bruno}, map[uuid.UUID]map[int]int{
+		ana.ID: {1: 10, 2: 5, 3: 15}, bruno.ID: {1: 5, 2: 10, 3: 10},
+	})
+	if names[0] != "Ana" {
+		t.Errorf("order = %v, want Ana first, 2 rounds to 1", names)
+	}
+}
+
+// Bruno beat Ana 2 rounds to 1, Caio beat Bruno and Ana beat Caio: pair by pair there is no
+// order. As a mini-league each won 3 rounds, so the next criterion decides, whatever order
+// they come in.
+func TestHeadToHeadThreeWayCycle(t *testing.T) {
+	ana := models.UserWithStats{User: models.User{ID: uuid.New(), DisplayName: "Ana"}, TotalPoints: 6, ExactScores: 1}
+	bruno := models.UserWithStats{User: models.User{ID: uuid.New(), DisplayName: "Bruno"}, TotalPoints: 6}
+	caio := models.UserWithStats{User: models.User{ID: uuid.New(), DisplayName: "Caio"}, TotalPoints: 6, ExactScores: 2}
+	dora := models.UserWithStats{User: models.User{ID: uuid.New(), DisplayName: "Dora"}, TotalPoints: 9}
+	rounds := map[uuid.UUID]map[int]int{
+		ana.ID:   {1: 3, 2: 2, 3: 1},
+		bruno.ID: {1: 1, 2: 3, 3: 2},
+		caio.ID:  {1: 2, 2: 1, 3: 3},
+		dora.ID:  {1: 0, 2: 0, 3: 9},
+	}
+
+	r := RankingFor(models.BolaoSettings{Tiebreakers: []string{TiebreakHeadToHead, TiebreakExactScores}})
+	for _, users := range [][]models.UserWithStats{{ana, bruno, caio, dora}, {caio, bruno, ana, dora}, {bruno, dora, caio, ana}} {
+		names, _ := rankNames(r, users, rounds)
+		want := []string{"Dora", "Caio", "Ana", "Bruno"}
+		for i := range want {
+			if names[i] != want[i] {
+				t.Errorf("order from %s first = %v, want %v", users[0].DisplayName, names, want)
+				break
+			}
+		}
+	}
+
+	shared := RankingFor(models.BolaoSettings{Tiebreakers: []string{TiebreakHeadToHead}, SharedPositions: true})
+	if _, positions := rankNames(shared, []models.UserWithStats{bruno, ana, dora, caio}, rounds); positions[1] != 2 || positions[2] != 2 || positions[3] != 2 {
+		t.Errorf("positions = %v, want the three tied players sharing 2nd", positions)
+	}
+}
+
+func TestValidateTiebreakers(t *testing.T) {
+	if err := ValidateTiebreakers(nil); err != nil {
+		t.Errorf("empty chain rejected: %v", err)
+	}
+	if err := ValidateTiebreakers([]string{TiebreakHeadToHead, TiebreakEarliestSubmission}); err != nil {
+		t.Errorf("valid chain rejected: %v", err)
+	}
+	if err := ValidateTiebreakers([]string{"goal_difference"}); !errors.Is(err, ErrInvalidTiebreakers) {
+		t.Errorf("unknown criterion = %v, want ErrInvalidTiebreakers", err)
+	}
+	if err := ValidateTiebreakers([]string{TiebreakRoundsWon, TiebreakRoundsWon}); !errors.Is(err, ErrInvalidTiebreakers) {
+		t.Errorf("repeated criterion = %v, want ErrInvalidTiebreakers", err)
+	}
+}
+
+func TestRankUsersPositions(t *testing.T) {
+	ana := models.UserWithStats{User: models.User{ID: uuid.New(), DisplayName: "Ana"}, TotalPoints: 20, ExactScores: 1}
+	bruno := models.UserWithStats{User: models.User{ID: uuid.New(), DisplayName: "Bruno"}, TotalPoints: 20, ExactScores: 1}
+	caio := models.UserWithStats{User: models.User{ID: uuid.New(), DisplayName: "Caio"}, TotalPoints: 30}
+	dora := models.UserWithStats{User: models.User{ID: uuid.New(), DisplayName: "Dora"}, TotalPoints: 10}
+
+	result := []models.UserWithStats{ana, bruno, caio, dora}
+	rankUsers(defaultRanking, result, nil)
+	want := []struct {
+		name     string
+		position int
+	}{{"Caio", 1}, {"Ana", 2}, {"Bruno", 3}, {"Dora", 4}}
+	for i, w := range want {
+		if result[i].DisplayName != w.name || result[i].Position != w.position {
+			t.Errorf("row %d = %s at %d, want %s at %d", i, result[i].DisplayName, result[i].Position, w.name, w.position)
+		}
+	}
+
+	// Shared positions: Ana and Bruno tie on every criterion and share 2nd; Dora is 4th,
+	// not 3rd, as in any table with ties.
+	shared := RankingFor(models.BolaoSettings{SharedPositions: true})
+	result = []models.UserWithStats{ana, bruno, caio, dora}
+	rankUsers(shared, result, nil)
+	for i, w := range []int{1, 2, 2, 4} {
+		if result[i].Position != w {
+			t.Errorf("shared row %d (%s) at %d, want %d", i, result[i].DisplayName, result[i].Position, w)
+		}
+	}
+}
+
+func TestFirstSubmissions(t *testing.T) {
+	ana := uuid.New()
+	m1, m2, other := uuid.New(), uuid.New(), uuid.New()
+	first := time.Date(2026, 4, 1, 10, 0, 0, 0, time.UTC)
+	preds := []models.Prediction{
+		{UserID: ana, MatchID: m2, CreatedAt: first.Add(time.Hour)},
+		{UserID: ana, MatchID: m1, CreatedAt: first},
+		{UserID: ana, MatchID: other, CreatedAt: first.Add(-time.Hour)},
+	}
+	got := firstSubmissions(preds, map[uuid.UUID]int{m1: 1, m2: 1})
+	if len(got[ana]) != 1 || !got[ana][1].Equal(first) {
+		t.Errorf("first submissions = %v, want round 1 at %v only", got[ana], first)
+	}
+}
@@ -19,6 +19,9 @@ type RoundsSummary struct {
 	// Deliberately different from the unresolved count in BolaoService.FinishActive, which
 	// ignores market_closes_at because finishing a bolão needs every match resolved.
 	PendingResults int `json:"pending_results"`
+	// Weights maps each existing round that doesn't weigh 100% to its weight in percent
+	// (SCORING.md §3.5), so the UI can flag the boosted rounds.
+	Weights map[int]int `json:"weights"`
 }
 
 // SummarizeRounds lists the rounds of a bolão and picks the active one: the first round
@@ -26,9 +29,9 @@ type RoundsSummary struct {
 // result. Falls back to the last round when they are all finished, and to the first round
 // when none is.
 //
-// now is a parameter rather than a time.Now() call so PendingResults stays testable, the
-// same way scoreParticipantRound takes it.
-func SummarizeRounds(matches []models.Match, now time.Time) RoundsSummary {
+// weights are the bolão's round weights. now is a parameter rather than a time.Now() call
+// so PendingResults stays testable, the same way scoreParticipantRound takes it.
+func SummarizeRounds(matches []models.Match, weights []models.RoundWeight, now time.Time) RoundsSummary {
 	finished := make(map[int]bool)
 	pending := 0
 	for _, m := range matches {
@@ -50,7 +53,7 @@ func SummarizeRounds(matches []models.Match, now time.Time) RoundsSummary {
 	sort.Ints(rounds)
 
 	if len(rounds) == 0 {
-		return RoundsSummary{Rounds: []int{}, Active: 0, PendingResults: 0}
+		return RoundsSummary{Rounds: []int{}, Active: 0, PendingResults: 0, Weights: map[int]int{}}
 	}
 
 	// Latest finished round, then the first round after it. Scanning for the *latest*
@@ -71,5 +74,12 @@ func SummarizeRounds(matches []models.Match, now time.Time) RoundsSummary {
 		}
 	}
 
-	return RoundsSummary{Rounds: rounds, Active: active, PendingResults: pending}
+	boosted := make(map[int]int)
+	for _, round := range rounds {
+		if w := RoundWeightPercent(weights, round); w != fullWeight {
+			boosted[round] = w
+		}
+	}
+
+	return RoundsSummary{Rounds: rounds, Active: active, PendingResults: pending, Weights: boosted}
 }
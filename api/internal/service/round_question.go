@@ -0,0 +1,137 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+const (
+	RoundQuestionChoice = "choice"
+	RoundQuestionNumber = "number"
+)
+
+var (
+	ErrInvalidRoundQuestion  = errors.New("pergunta inválida")
+	ErrInvalidQuestionAnswer = errors.New("resposta inválida")
+	ErrRoundQuestionsOpen    = errors.New("o resultado só pode ser registrado após o fechamento do mercado da rodada")
+)
+
+// ValidateRoundQuestion checks a question an admin is creating: a choice question needs at
+// least two distinct options, a number question takes none.
+func ValidateRoundQuestion(q models.RoundQuestion) error {
+	if strings.TrimSpace(q.Prompt) == "" {
+		return fmt.Errorf("%w: o enunciado é obrigatório", ErrInvalidRoundQuestion)
+	}
+	if q.Round < 1 {
+		return fmt.Errorf("%w: rodada inválida", ErrInvalidRoundQuestion)
+	}
+	if q.Points < 0 {
+		return fmt.Errorf("%w: points não pode ser negativo", ErrInvalidRoundQuestion)
+	}
+	switch q.Kind {
+	case RoundQuestionChoice:
+		if len(q.Options) < 2 {
+			return fmt.Errorf("%w: informe pelo menos 2 opções", ErrInvalidRoundQuestion)
+		}
+		seen := make(map[string]bool, len(q.Options))
+		for _, o := range q.Options {
+			if strings.TrimSpace(o) == "" || seen[o] {
+				return fmt.Errorf("%w: opções vazias ou repetidas", ErrInvalidRoundQuestion)
+			}
+			seen[o] = true
+		}
+	case RoundQuestionNumber:
+		if len(q.Options) > 0 {
+			return fmt.Errorf("%w: perguntas numéricas não têm opções", ErrInvalidRoundQuestion)
+		}
+	default:
+		return fmt.Errorf("%w: kind deve ser %q ou %q", ErrInvalidRoundQuestion, RoundQuestionChoice, RoundQuestionNumber)
+	}
+	return nil
+}
+
+// NormalizeQuestionAnswer validates an answer (a player's or the admin's result) against
+// the question and returns it in the form it is stored and compared in: the option as
+// written, or the number without sign noise or leading zeros.
+func NormalizeQuestionAnswer(q models.RoundQuestion, raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if q.Kind == RoundQuestionNumber {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("%w: %q não é um número inteiro", ErrInvalidQuestionAnswer, raw)
+		}
+		return strconv.Itoa(n), nil
+	}
+	if !slices.Contains(q.Options, raw) {
+		return "", fmt.Errorf("%w: %q não é uma das opções", ErrInvalidQuestionAnswer, raw)
+	}
+	return raw, nil
+}
+
+// RoundQuestionsClosed reports whether answers to the round's questions are locked. They
+// share the round's market: the first match whose market closes locks them all, since a
+// question can be about any match of the round.
+func RoundQuestionsClosed(matches []models.Match, now time.Time) bool {
+	for _, m := range matches {
+		if MarketClosed(m, now) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckRoundQuestionResult refuses a result while the round's questions still take answers:
+// the question players list carries it, and they could answer to match.
+func CheckRoundQuestionResult(matches []models.Match, now time.Time) error {
+	if !RoundQuestionsClosed(matches, now) {
+		return ErrRoundQuestionsOpen
+	}
+	return nil
+}
+
+// ScoreRoundQuestion is the item a right answer earns, or ok=false when the question has no
+// result yet or the answer is wrong.
+func ScoreRoundQuestion(q models.RoundQuestion, answer string) (ScoreItem, bool) {
+	if q.Result == nil || *q.Result != answer || q.Points == 0 {
+		return ScoreItem{}, false
+	}
+	return ScoreItem{Rule: "bonus_question", Constant: "points", Points: q.Points, Detail: q.Prompt}, true
+}
+
+// questionIndex maps user → round → the items the user's answers earned in that round.
+type questionIndex map[uuid.UUID]map[int][]ScoreItem
+
+func indexRoundQuestions(questions []models.RoundQuestion, answers []models.RoundQuestionAnswer) questionIndex {
+	byQuestion := make(map[uuid.UUID][]models.RoundQuestionAnswer)
+	for _, a := range answers {
+		byQuestion[a.QuestionID] = append(byQuestion[a.QuestionID], a)
+	}
+	// Walk the questions rather than the answers so every user's items come out in the
+	// questions' order.
+	index := make(questionIndex)
+	for _, q := range questions {
+		for _, a := range byQuestion[q.ID] {
+			item, ok := ScoreRoundQuestion(q, a.Answer)
+			if !ok {
+				continue
+			}
+			if index[a.UserID] == nil {
+				index[a.UserID] = make(map[int][]ScoreItem)
+			}
+			index[a.UserID][q.Round] = append(index[a.UserID][q.Round], item)
+		}
+	}
+	return index
+}
+
+// items returns what userID's answers earned in round. Safe on a nil index.
+func (ix questionIndex) items(userID uuid.UUID, round int) []ScoreItem {
+	return ix[userID][round]
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestValidateRoundQuestion(t *testing.T) {
+	tests := []struct {
+		name string
+		q    models.RoundQuestion
+		ok   bool
+	}{
+		{"choice", models.RoundQuestion{Round: 1, Prompt: "Cartão vermelho no Fla x Flu?", Kind: RoundQuestionChoice, Options: []string{"Sim", "Não"}, Points: 5}, true},
+		{"number", models.RoundQuestion{Round: 1, Prompt: "Gols do Palmeiras?", Kind: RoundQuestionNumber, Points: 5}, true},
+		{"no prompt", models.RoundQuestion{Round: 1, Kind: RoundQuestionNumber, Points: 5}, false},
+		{"one option", models.RoundQuestion{Round: 1, Prompt: "?", Kind: RoundQuestionChoice, Options: []string{"Sim"}, Points: 5}, false},
+		{"repeated option", models.RoundQuestion{Round: 1, Prompt: "?", Kind: RoundQuestionChoice, Options: []string{"Sim", "Sim"}, Points: 5}, false},
+		{"number with options", models.RoundQuestion{Round: 1, Prompt: "?", Kind: RoundQuestionNumber, Options: []string{"1", "2"}, Points: 5}, false},
+		{"unknown kind", models.RoundQuestion{Round: 1, Prompt: "?", Kind: "text", Points: 5}, false},
+		{"negative points", models.RoundQuestion{Round: 1, Prompt: "?", Kind: RoundQuestionNumber, Points: -1}, false},
+	}
+	for _, tt := range tests {
+		err := ValidateRoundQuestion(tt.q)
+		if (err == nil) != tt.ok {
+			t.Errorf("%s: ValidateRoundQuestion = %v, want ok=%v", tt.name, err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidRoundQuestion) {
+			t.Errorf("%s: error %v does not wrap ErrInvalidRoundQuestion", tt.name, err)
+		}
+	}
+}
+
+func TestNormalizeQuestionAnswer(t *testing.T) {
+	number := models.RoundQuestion{Kind: RoundQuestionNumber}
+	choice := models.RoundQuestion{Kind: RoundQuestionChoice, Options: []string{"Sim", "Não"}}
+
+	// "02" and "2" must compare equal, or a player loses points over formatting.
+	if got, err := NormalizeQuestionAnswer(number, " 02 "); err != nil || got != "2" {
+		t.Errorf("number \" 02 \" = (%q, %v), want \"2\"", got, err)
+	}
+	if _, err := NormalizeQuestionAnswer(number, "-1"); !errors.Is(err, ErrInvalidQuestionAnswer) {
+		t.Errorf("negative number accepted: %v", err)
+	}
+	if _, err := NormalizeQuestionAnswer(number, "dois"); !errors.Is(err, ErrInvalidQuestionAnswer) {
+		t.Errorf("non-number accepted: %v", err)
+	}
+	if got, err := NormalizeQuestionAnswer(choice, "Não"); err != nil || got != "Não" {
+		t.Errorf("choice \"Não\" = (%q, %v), want \"Não\"", got, err)
+	}
+	if _, err := NormalizeQuestionAnswer(choice, "Talvez"); !errors.Is(err, ErrInvalidQuestionAnswer) {
+		t.Errorf("answer outside the options accepted: %v", err)
+	}
+}
+
+func TestRoundQuestionsClosed(t *testing.T) {
+	open := matchClosingAt(timePtr(testNow.Add(time.Hour)))
+	closed := matchClosingAt(timePtr(testNow.Add(-time.Hour)))
+
+	if RoundQuestionsClosed([]models.Match{open, matchClosingAt(nil)}, testNow) {
+		t.Error("questions locked while every market is still open")
+	}
+	// The first match to close locks the questions, which may be about any match.
+	if !RoundQuestionsClosed([]models.Match{open, closed}, testNow) {
+		t.Error("questions still open after a match of the round closed")
+	}
+}
+
+func TestCheckRoundQuestionResult(t *testing.T) {
+	open := matchClosingAt(timePtr(testNow.Add(time.Hour)))
+	closed := matchClosingAt(timePtr(testNow.Add(-time.Hour)))
+
+	if err := CheckRoundQuestionResult([]models.Match{open}, testNow); !errors.Is(err, ErrRoundQuestionsOpen) {
+		t.Errorf("result while the questions take answers: err = %v, want ErrRoundQuestionsOpen", err)
+	}
+	if err := CheckRoundQuestionResult([]models.Match{open, closed}, testNow); err != nil {
+		t.Errorf("result once the questions are locked: err = %v, want nil", err)
+	}
+}
+
+func TestIndexRoundQuestions(t *testing.T) {
+	sim, two := "Sim", "2"
+	redCard := models.RoundQuestion{ID: uuid.New(), Round: 1, Prompt: "Cartão vermelho?", Kind: RoundQuestionChoice, Options: []string{"Sim", "Não"}, Points: 5, Result: &sim}
+	goals := models.RoundQuestion{ID: uuid.New(), Round: 1, Prompt: "Gols do Palmeiras?", Kind: RoundQuestionNumber, Points: 8, Result: &two}
+	pending := models.RoundQuestion{ID: uuid.New(), Round: 2, Prompt: "Pênalti?", Kind: RoundQuestionChoice, Options: []string{"Sim", "Não"}, Points: 5}
+	ana, bruno := uuid.New(), uuid.New()
+
+	ix := indexRoundQuestions([]models.RoundQuestion{redCard, goals, pending}, []models.RoundQuestionAnswer{
+		{QuestionID: goals.ID, UserID: ana, Answer: "2"},
+		{QuestionID: redCard.ID, UserID: ana, Answer: "Sim"},
+		{QuestionID: pending.ID, UserID: ana, Answer: "Sim"},
+		{QuestionID: redCard.ID, UserID: bruno, Answer: "Não"},
+	})
+
+	items := ix.items(ana, 1)
+	if len(items) != 2 || items[0].Detail != redCard.Prompt || items[1].Detail != goals.Prompt {
+		t.Fatalf("Ana's round 1 items = %+v, want the red card then the goals question", items)
+	}
+	if sumItems(items) != 13 {
+		t.Errorf("Ana's round 1 bonus = %d, want 13", sumItems(items))
+	}
+	if got := ix.items(ana, 2); len(got) != 0 {
+		t.Errorf("an unresolved question paid %+v", got)
+	}
+	if got := ix.items(bruno, 1); len(got) != 0 {
+		t.Errorf("a wrong answer paid %+v", got)
+	}
+}
+
+// Bonus question points are part of the round total, so they also decide the round winner.
+func TestScoreParticipantRoundAddsQuestions(t *testing.T) {
+	matches := []matchWithResult{closedResult(2, 1)}
+	lookup := predictions(matches, map[int][2]int{0: {2, 1}})
+	questions := []ScoreItem{{Rule: "bonus_question", Constant: "points", Points: 5, Detail: "Cartão vermelho?"}}
+
+	without := scoreParticipantRound(defaultRules, matches, lookup, uuid.Nil, "", nil, true, testNow)
+	with := scoreParticipantRound(defaultRules, matches, lookup, uuid.Nil, "", questions, true, testNow)
+	if with.points != without.points+5 {
+		t.Errorf("round with a right answer = %d, want %d", with.points, without.points+5)
+	}
+	if with.exactScores != without.exactScores || with.correctResults != without.correctResults {
+		t.Errorf("a bonus question changed the tiebreaker counts: %+v vs %+v", with, without)
+	}
+}
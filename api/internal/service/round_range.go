@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidRoundRanges = errors.New("faixas de rodadas inválidas")
+	ErrInvalidRoundRange  = errors.New("faixa de rodadas inválida")
+	ErrRoundRangeNotFound = errors.New("faixa de rodadas não encontrada")
+)
+
+// DefaultRoundRanges are the turno and the returno of a 38-round league, the ranges a new
+// bolão starts with.
+func DefaultRoundRanges() []models.RoundRange {
+	return []models.RoundRange{
+		{Name: "turno", FromRound: 1, ToRound: 19},
+		{Name: "returno", FromRound: 20, ToRound: 38},
+	}
+}
+
+// ValidateRoundRanges accepts ranges with distinct, non-empty names, each from a round to
+// the same or a later one. Unlike the round weights they may overlap: a bolão can pay the
+// returno and its last four rounds.
+func ValidateRoundRanges(ranges []models.RoundRange) error {
+	for i, r := range ranges {
+		if r.Name == "" {
+			return fmt.Errorf("%w: toda faixa precisa de um nome", ErrInvalidRoundRanges)
+		}
+		if !validRoundRange(r.FromRound, r.ToRound) {
+			return fmt.Errorf("%w: faixa %q de %d a %d", ErrInvalidRoundRanges, r.Name, r.FromRound, r.ToRound)
+		}
+		for _, other := range ranges[:i] {
+			if other.Name == r.Name {
+				return fmt.Errorf("%w: nome %q repetido", ErrInvalidRoundRanges, r.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func validRoundRange(fromRound, toRound int) bool {
+	return fromRound >= 1 && toRound >= fromRound
+}
+
+// FindRoundRange returns the range of ranges called name.
+func FindRoundRange(ranges []models.RoundRange, name string) (models.RoundRange, bool) {
+	for _, r := range ranges {
+		if r.Name == name {
+			return r, true
+		}
+	}
+	return models.RoundRange{}, false
+}
+
+// RangeClassification is the standings over a range of rounds. Name is empty for a range
+// that isn't one of the bolão's presets.
+type RangeClassification struct {
+	models.RoundRange
+	Standings []models.UserWithStats `json:"standings"`
+}
+
+// rangeStandings is partStandings over rounds r.FromRound..r.ToRound, up to lastRound, the
+// bolão's last: nothing past it has a score, and ToRound comes unbounded from the query.
+func (d *bolaoSnapshot) rangeStandings(r models.RoundRange, lastRound int, scoresOf func(round int) (map[uuid.UUID]roundScore, bool)) (standings []models.UserWithStats, played bool) {
+	toRound := min(r.ToRound, lastRound)
+	rounds := make([]int, 0, max(0, toRound-r.FromRound+1))
+	for round := r.FromRound; round <= toRound; round++ {
+		rounds = append(rounds, round)
+	}
+	return d.partStandings(rounds, scoresOf)
+}
+
+// partStandings adds up the round scores scoresOf returns for rounds alone: the points,
+// weighted like in the season, and the rounds won among them. Season-long bets and
+// discarded rounds belong to the whole season and stay out. played reports whether any of
+// the rounds was counted.
+func (d *bolaoSnapshot) partStandings(rounds []int, scoresOf func(round int) (map[uuid.UUID]roundScore, bool)) (standings []models.UserWithStats, played bool) {
+	t := d.newSeasonTotals()
+	t.rangeOnly = true
+	for _, round := range rounds {
+		if scores, ok := scoresOf(round); ok {
+			t.add(round, scores)
+			played = true
+		}
+	}
+	return t.table(), played
+}
+
+// GetClassificationRange returns the standings over rounds fromRound..toRound, added up
+// from the materialized round scores.
+func (s *ClassificationService) GetClassificationRange(ctx context.Context, bolaoID uuid.UUID, fromRound, toRound int) (*RangeClassification, error) {
+	if !validRoundRange(fromRound, toRound) {
+		return nil, fmt.Errorf("%w: de %d a %d", ErrInvalidRoundRange, fromRound, toRound)
+	}
+	return s.classifyRange(ctx, bolaoID, models.RoundRange{FromRound: fromRound, ToRound: toRound})
+}
+
+// GetClassificationPreset returns the standings over the bolão's range called name, or
+// ErrRoundRangeNotFound when it has none by that name.
+func (s *ClassificationService) GetClassificationPreset(ctx context.Context, bolaoID uuid.UUID, name string) (*RangeClassification, error) {
+	bolao, err := s.bolaoRepo.GetByID(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := FindRoundRange(bolao.RoundRanges, name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrRoundRangeNotFound, name)
+	}
+	return s.classifyRange(ctx, bolaoID, r)
+}
+
+func (s *ClassificationService) classifyRange(ctx context.Context, bolaoID uuid.UUID, r models.RoundRange) (*RangeClassification, error) {
+	d, rows, maxRound, err := s.loadRoundScores(ctx, bolaoID, r.ToRound)
+	if err != nil {
+		return nil, err
+	}
+	scores := d.scoresFromRows(rows)
+	standings, _ := d.rangeStandings(r, maxRound, func(round int) (map[uuid.UUID]roundScore, bool) {
+		s, ok := scores[round]
+		return s, ok
+	})
+	return &RangeClassification{RoundRange: r, Standings: standings}, nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestValidateRoundRanges(t *testing.T) {
+	valid := append(DefaultRoundRanges(), models.RoundRange{Name: "reta final", FromRound: 35, ToRound: 38})
+	if err := ValidateRoundRanges(valid); err != nil {
+		t.Errorf("valid ranges rejected: %v", err)
+	}
+	for name, ranges := range map[string][]models.RoundRange{
+		"no name":        {{FromRound: 1, ToRound: 19}},
+		"inverted range": {{Name: "turno", FromRound: 19, ToRound: 1}},
+		"round zero":     {{Name: "turno", FromRound: 0, ToRound: 19}},
+		"repeated name":  {{Name: "turno", FromRound: 1, ToRound: 19}, {Name: "turno", FromRound: 20, ToRound: 38}},
+	} {
+		if err := ValidateRoundRanges(ranges); !errors.Is(err, ErrInvalidRoundRanges) {
+			t.Errorf("%s: err = %v, want ErrInvalidRoundRanges", name, err)
+		}
+	}
+}
+
+// The standings of a range count its rounds alone, weighted, with the rounds won in it,
+// and leave the season-long bets and the discarded rounds to the season.
+func TestRangeStandings(t *testing.T) {
+	d, ana, bruno := roundScoreFixture()
+	d.outrightPoints = map[uuid.UUID]int{ana.ID: 50}
+	scoresOf := func(round int) (map[uuid.UUID]roundScore, bool) {
+		return d.scoreRound(round, finalResults, testNow)
+	}
+
+	byUser := func(standings []models.UserWithStats, _ bool) map[uuid.UUID]models.UserWithStats {
+		m := make(map[uuid.UUID]models.UserWithStats, len(standings))
+		for _, u := range standings {
+			m[u.ID] = u
+		}
+		return m
+	}
+	// Round 2 alone: both hit the 1×0, weighted 150%, and Bruno takes it on his earlier
+	// submission.
+	returno := byUser(d.rangeStandings(models.RoundRange{FromRound: 2, ToRound: 3}, d.maxRound, scoresOf))
+	round2, _ := scoresOf(2)
+	want := weighRoundPoints(round2[ana.ID].points, 150)
+	for _, u := range []models.User{ana, bruno} {
+		if got := returno[u.ID]; got.TotalPoints != want || got.OutrightPoints != 0 || got.DiscardedRounds != nil {
+			t.Errorf("%s = %+v, want %d points and no outright or discarded rounds", u.DisplayName, got, want)
+		}
+	}
+	if returno[bruno.ID].Position != 1 || returno[bruno.ID].RoundsWon != 1 || returno[ana.ID].RoundsWon != 0 {
+		t.Errorf("standings = %+v, want Bruno first with the round won", returno)
+	}
+
+	// Rounds 1–2: no round is discarded, though the bolão drops one from the season.
+	whole := byUser(d.rangeStandings(models.RoundRange{FromRound: 1, ToRound: 2}, d.maxRound, scoresOf))
+	season := byUser(d.standings(2, finalResults, testNow), true)
+	if got := whole[ana.ID]; got.TotalPoints != season[ana.ID].GrossPoints-50 || got.RoundsWon != 1 {
+		t.Errorf("Ana = %+v, want every round of the season without the outright and a round won", got)
+	}
+
+	// A range past the last round stops at it instead of walking up to ToRound.
+	asked := 0
+	_, played := d.rangeStandings(models.RoundRange{FromRound: 2, ToRound: 2_000_000_000}, d.maxRound, func(round int) (map[uuid.UUID]roundScore, bool) {
+		asked++
+		return scoresOf(round)
+	})
+	if !played || asked != d.maxRound-1 {
+		t.Errorf("asked %d rounds, played %v, want rounds 2–%d only", asked, played, d.maxRound)
+	}
+	if _, played := d.rangeStandings(models.RoundRange{FromRound: d.maxRound + 1, ToRound: d.maxRound + 5}, d.maxRound, scoresOf); played {
+		t.Error("a range past the last round was played")
+	}
+}
+
+func TestFindRoundRange(t *testing.T) {
+	if r, ok := FindRoundRange(DefaultRoundRanges(), "returno"); !ok || r.FromRound != 20 || r.ToRound != 38 {
+		t.Errorf("returno = %+v, %v, want rounds 20–38", r, ok)
+	}
+	if _, ok := FindRoundRange(DefaultRoundRanges(), "copa"); ok {
+		t.Error("found a range the bolão doesn't have")
+	}
+}
@@ -0,0 +1,311 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// The cumulative standings add up materialized round scores (round_scores) instead of
+// rescoring the whole bolão on every request. A write that can change a round's scores
+// invalidates the round after it commits; the admin writes recompute it straight away
+// (RefreshRounds), the others leave it to the next read. A round's scores also change
+// with no write when one of its markets closes — a missing prediction becomes 0×0, the
+// favorite team and the outcome split settle (RefreshRounds freezes the split) — so each
+// computed round expires at its next market close.
+//
+// Predictions, jokers, answers and favorite teams can't change a round once its market
+// has closed, so a round is only ever stale between a write and the next read.
+
+// roundRows is the round's materialized scores: one row per participant, none when no
+// match of the round has a result.
+func (d *bolaoSnapshot) roundRows(round int, now time.Time) []models.RoundScoreRow {
+	scores, ok := d.scoreRound(round, finalResults, now)
+	if !ok {
+		return nil
+	}
+	rows := make([]models.RoundScoreRow, 0, len(d.participants))
+	for _, p := range d.participants {
+		rs := scores[p.ID]
+		row := models.RoundScoreRow{
+			UserID:         p.ID,
+			Round:          round,
+			Points:         rs.points,
+			ExactScores:    rs.exactScores,
+			CorrectResults: rs.correctResults,
+			Missed:         rs.missed,
+			RoundTotalHits: rs.roundTotalHits,
+		}
+		if t, ok := d.submissions[p.ID][round]; ok {
+			row.FirstSubmission = &t
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// roundValidUntil is the first market of matches to close after now, when the round's
+// scores change on their own; nil when none is still open.
+func roundValidUntil(matches []models.Match, now time.Time) *time.Time {
+	var next *time.Time
+	for _, m := range matches {
+		if c := m.MarketClosesAt; c != nil && c.After(now) && (next == nil || c.Before(*next)) {
+			next = c
+		}
+	}
+	return next
+}
+
+// standingsFromRows is standings over materialized round scores.
+func (d *bolaoSnapshot) standingsFromRows(upToRound int, rows map[int][]models.RoundScoreRow) []models.UserWithStats {
+	scores := d.scoresFromRows(rows)
+	return d.aggregate(upToRound, func(round int) (map[uuid.UUID]roundScore, bool) {
+		s, ok := scores[round]
+		return s, ok
+	})
+}
+
+// scoresFromRows turns materialized rows into round scores by round, and the first
+// submissions they carry into d.submissions. Rows of users who are no longer participants
+// are ignored.
+func (d *bolaoSnapshot) scoresFromRows(rows map[int][]models.RoundScoreRow) map[int]map[uuid.UUID]roundScore {
+	participants := make(map[uuid.UUID]bool, len(d.participants))
+	for _, p := range d.participants {
+		participants[p.ID] = true
+	}
+	d.submissions = make(map[uuid.UUID]map[int]time.Time)
+	scores := make(map[int]map[uuid.UUID]roundScore, len(rows))
+	for round, roundRows := range rows {
+		for _, r := range roundRows {
+			if !participants[r.UserID] {
+				continue
+			}
+			if scores[round] == nil {
+				scores[round] = make(map[uuid.UUID]roundScore, len(roundRows))
+			}
+			scores[round][r.UserID] = roundScore{r.Points, r.ExactScores, r.CorrectResults, r.Missed, r.RoundTotalHits}
+			if r.FirstSubmission != nil {
+				if d.submissions[r.UserID] == nil {
+					d.submissions[r.UserID] = make(map[int]time.Time)
+				}
+				d.submissions[r.UserID][round] = *r.FirstSubmission
+			}
+		}
+	}
+	return scores
+}
+
+// roundScoreCache is a bolão's materialized round scores as stored.
+type roundScoreCache struct {
+	states map[int]models.RoundScoreState
+	rows   map[int][]models.RoundScoreRow
+}
+
+func (s *ClassificationService) loadRoundScoreCache(ctx context.Context, bolaoID uuid.UUID) (*roundScoreCache, error) {
+	states, err := s.roundScoreRepo.ListStates(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.roundScoreRepo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	c := &roundScoreCache{
+		states: make(map[int]models.RoundScoreState, len(states)),
+		rows:   make(map[int][]models.RoundScoreRow),
+	}
+	for _, st := range states {
+		c.states[st.Round] = st
+	}
+	for _, r := range rows {
+		c.rows[r.Round] = append(c.rows[r.Round], r)
+	}
+	return c, nil
+}
+
+// fresh reports whether the stored scores of round can be used as they are: computed since
+// the round was last invalidated, not expired, and with a row for every participant if the
+// round has results — someone who joined after it was computed has none.
+func (c *roundScoreCache) fresh(round int, participants []models.ParticipantView, now time.Time) bool {
+	st, ok := c.states[round]
+	if !ok || st.ComputedGeneration == nil || *st.ComputedGeneration != st.Generation {
+		return false
+	}
+	if st.ValidUntil != nil && !now.Before(*st.ValidUntil) {
+		return false
+	}
+	rows := c.rows[round]
+	if len(rows) == 0 {
+		return true
+	}
+	has := make(map[uuid.UUID]bool, len(rows))
+	for _, r := range rows {
+		has[r.UserID] = true
+	}
+	for _, p := range participants {
+		if !has[p.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// generation is the generation a recompute of round starts from: 0 for a round never
+// invalidated.
+func (c *roundScoreCache) generation(round int) int64 {
+	return c.states[round].Generation
+}
+
+// recompute scores the rounds in generations from d, which holds at least those rounds
+// (see loadRounds), and stores each one that wasn't invalidated again meanwhile. It
+// returns the rows computed, stored or not: they are at least as recent as the
+// generation they started from.
+func (s *ClassificationService) recompute(ctx context.Context, d *bolaoSnapshot, bolaoID uuid.UUID, generations map[int]int64, now time.Time) (map[int][]models.RoundScoreRow, error) {
+	computed := make(map[int][]models.RoundScoreRow, len(generations))
+	for round, generation := range generations {
+		rows := d.roundRows(round, now)
+		if _, err := s.roundScoreRepo.Replace(ctx, bolaoID, round, generation, roundValidUntil(d.byRound[round], now), rows); err != nil {
+			return nil, err
+		}
+		computed[round] = rows
+	}
+	return computed, nil
+}
+
+// AffectedRounds returns rounds plus the rounds whose scores depend on them: those of the
+// second legs of ties whose first leg is in rounds. Call it before a write that can
+// delete a tie, and refresh what it returns after.
+func (s *ClassificationService) AffectedRounds(ctx context.Context, bolaoID uuid.UUID, rounds ...int) ([]int, error) {
+	return s.roundScoreRepo.AffectedRounds(ctx, bolaoID, rounds)
+}
+
+// RefreshRounds invalidates the materialized scores of rounds, freezes the outcome split
+// of their closed matches and recomputes them. Call it after the write that changed them.
+func (s *ClassificationService) RefreshRounds(ctx context.Context, bolaoID uuid.UUID, rounds ...int) error {
+	generations, err := s.roundScoreRepo.Invalidate(ctx, bolaoID, rounds)
+	if err != nil {
+		return err
+	}
+	base, err := s.loadStandingsBase(ctx, bolaoID)
+	if err != nil {
+		return err
+	}
+	d, err := s.loadRounds(ctx, base, bolaoID, rounds)
+	if err != nil {
+		return err
+	}
+	var matches []models.Match
+	for _, round := range rounds {
+		matches = append(matches, d.byRound[round]...)
+	}
+	if err := FreezeOutcomeShares(ctx, s.shareRepo, d.shares, matches); err != nil {
+		return err
+	}
+	_, err = s.recompute(ctx, d, bolaoID, generations, time.Now())
+	return err
+}
+
+// InvalidateRounds marks the materialized scores of rounds stale, for the next read to
+// recompute. Call it after the write that changed them.
+func (s *ClassificationService) InvalidateRounds(ctx context.Context, bolaoID uuid.UUID, rounds ...int) error {
+	_, err := s.roundScoreRepo.Invalidate(ctx, bolaoID, rounds)
+	return err
+}
+
+// InvalidateFromRound marks the materialized scores of round and every later round stale.
+func (s *ClassificationService) InvalidateFromRound(ctx context.Context, bolaoID uuid.UUID, round int) error {
+	return s.roundScoreRepo.InvalidateFrom(ctx, bolaoID, round)
+}
+
+// RoundScoreMismatch is a participant whose stored round score differs from a full
+// recompute. Cached or Computed is nil when that side has no row.
+type RoundScoreMismatch struct {
+	Round    int                   `json:"round"`
+	UserID   uuid.UUID             `json:"user_id"`
+	Cached   *models.RoundScoreRow `json:"cached"`
+	Computed *models.RoundScoreRow `json:"computed"`
+}
+
+// RoundScoreCheck compares the materialized round scores with a full recompute. Stale
+// rounds are not compared: the next read recomputes them anyway.
+type RoundScoreCheck struct {
+	RoundsChecked int                  `json:"rounds_checked"`
+	StaleRounds   []int                `json:"stale_rounds"`
+	Mismatches    []RoundScoreMismatch `json:"mismatches"`
+}
+
+// CheckRoundScores rescores every round of the bolão and reports where the fresh
+// materialized scores disagree — a write that should have invalidated a round and
+// didn't. It only reads.
+func (s *ClassificationService) CheckRoundScores(ctx context.Context, bolaoID uuid.UUID) (*RoundScoreCheck, error) {
+	d, err := s.loadSnapshot(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	cache, err := s.loadRoundScoreCache(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+
+	rounds := make([]int, 0, len(d.byRound))
+	for round := range d.byRound {
+		rounds = append(rounds, round)
+	}
+	sort.Ints(rounds)
+
+	check := &RoundScoreCheck{StaleRounds: []int{}, Mismatches: []RoundScoreMismatch{}}
+	for _, round := range rounds {
+		if !cache.fresh(round, d.participants, now) {
+			check.StaleRounds = append(check.StaleRounds, round)
+			continue
+		}
+		check.RoundsChecked++
+		check.Mismatches = append(check.Mismatches, compareRoundRows(round, d.participants, cache.rows[round], d.roundRows(round, now))...)
+	}
+	return check, nil
+}
+
+// compareRoundRows lists the participants whose cached and computed rows differ.
+func compareRoundRows(round int, participants []models.ParticipantView, cached, computed []models.RoundScoreRow) []RoundScoreMismatch {
+	cachedBy := make(map[uuid.UUID]models.RoundScoreRow, len(cached))
+	for _, r := range cached {
+		cachedBy[r.UserID] = r
+	}
+	computedBy := make(map[uuid.UUID]models.RoundScoreRow, len(computed))
+	for _, r := range computed {
+		computedBy[r.UserID] = r
+	}
+
+	var mismatches []RoundScoreMismatch
+	for _, p := range participants {
+		c, hasCached := cachedBy[p.ID]
+		f, hasComputed := computedBy[p.ID]
+		if hasCached == hasComputed && (!hasCached || sameRoundRow(c, f)) {
+			continue
+		}
+		m := RoundScoreMismatch{Round: round, UserID: p.ID}
+		if hasCached {
+			m.Cached = &c
+		}
+		if hasComputed {
+			m.Computed = &f
+		}
+		mismatches = append(mismatches, m)
+	}
+	return mismatches
+}
+
+func sameRoundRow(a, b models.RoundScoreRow) bool {
+	if a.Points != b.Points || a.ExactScores != b.ExactScores || a.CorrectResults != b.CorrectResults ||
+		a.Missed != b.Missed || a.RoundTotalHits != b.RoundTotalHits {
+		return false
+	}
+	if a.FirstSubmission == nil || b.FirstSubmission == nil {
+		return a.FirstSubmission == nil && b.FirstSubmission == nil
+	}
+	return a.FirstSubmission.Equal(*b.FirstSubmission)
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Three rounds: Ana takes the first, the second is level and goes to Bruno's earlier
+// submission, the third has no result yet.
+func roundScoreFixture() (d *bolaoSnapshot, ana, bruno models.User) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	r1 := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	r2 := exportMatch("Bahia", "Remo", 1, 0, closed)
+	r2.Round = 2
+	r3 := models.Match{ID: uuid.New(), Round: 3, HomeTeam: "Palmeiras", AwayTeam: "Santos", MarketClosesAt: timePtr(testNow.Add(time.Hour))}
+	ana, bruno = exportUser("Ana"), exportUser("Bruno")
+
+	brunoEarly := testPrediction(bruno, r2, 1, 0)
+	brunoEarly.CreatedAt = testNow.Add(-3 * time.Hour)
+	d = testSnapshot([]models.User{ana, bruno}, []models.Match{r1, r2, r3}, []models.Prediction{
+		testPrediction(ana, r1, 2, 1), testPrediction(bruno, r1, 1, 0),
+		testPrediction(ana, r2, 1, 0), brunoEarly,
+		testPrediction(ana, r3, 0, 0), testPrediction(bruno, r3, 3, 0),
+	})
+	d.settings.RoundWeights = []models.RoundWeight{{FromRound: 2, ToRound: 2, Percent: 150}}
+	d.settings.DropWorstRounds = 1
+	d.settings.Tiebreakers = []string{TiebreakExactScores, TiebreakEarliestSubmission}
+	return d, ana, bruno
+}
+
+// Adding up the materialized rows gives the standings a full rescoring gives.
+func TestStandingsFromRows(t *testing.T) {
+	d, _, _ := roundScoreFixture()
+	want := d.standings(3, finalResults, testNow)
+
+	rows := map[int][]models.RoundScoreRow{}
+	for round := 1; round <= 3; round++ {
+		if r := d.roundRows(round, testNow); r != nil {
+			rows[round] = r
+		}
+	}
+	if _, ok := rows[3]; ok {
+		t.Error("round 3 has rows without a result")
+	}
+	// A player who left the bolão keeps rows until the round is recomputed.
+	rows[1] = append(rows[1], models.RoundScoreRow{UserID: uuid.New(), Round: 1, Points: 99})
+
+	cached := &bolaoSnapshot{settings: d.settings, participants: d.participants, rulesets: d.rulesets}
+	if got := cached.standingsFromRows(3, rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("standings from rows = %+v\nwant %+v", got, want)
+	}
+}
+
+func TestRoundRowsFirstSubmission(t *testing.T) {
+	d, ana, bruno := roundScoreFixture()
+	first := map[uuid.UUID]*time.Time{}
+	for _, r := range d.roundRows(2, testNow) {
+		first[r.UserID] = r.FirstSubmission
+	}
+	if first[bruno.ID] == nil || !first[bruno.ID].Equal(testNow.Add(-3*time.Hour)) {
+		t.Errorf("Bruno's first submission = %v, want 3h before now", first[bruno.ID])
+	}
+	if first[ana.ID] == nil || !first[ana.ID].Equal(testNow.Add(-2*time.Hour)) {
+		t.Errorf("Ana's first submission = %v, want 2h before now", first[ana.ID])
+	}
+}
+
+func TestRoundValidUntil(t *testing.T) {
+	soon, later := testNow.Add(time.Hour), testNow.Add(2*time.Hour)
+	matches := []models.Match{
+		matchClosingAt(timePtr(testNow.Add(-time.Hour))),
+		matchClosingAt(&later),
+		matchClosingAt(&soon),
+		matchClosingAt(nil),
+	}
+	if got := roundValidUntil(matches, testNow); got == nil || !got.Equal(soon) {
+		t.Errorf("valid until = %v, want the next close %v", got, soon)
+	}
+	if got := roundValidUntil(matches[:1], testNow); got != nil {
+		t.Errorf("valid until = %v with every market closed, want nil", got)
+	}
+}
+
+func TestRoundScoreCacheFresh(t *testing.T) {
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	participants := []models.ParticipantView{{User: ana}, {User: bruno}}
+	gen := func(n int64) *int64 { return &n }
+	row := func(u models.User) models.RoundScoreRow { return models.RoundScoreRow{UserID: u.ID, Round: 1} }
+
+	tests := []struct {
+		name  string
+		state *models.RoundScoreState
+		rows  []models.RoundScoreRow
+		want  bool
+	}{
+		{"never computed", nil, nil, false},
+		{"computed", &models.RoundScoreState{Round: 1, Generation: 2, ComputedGeneration: gen(2)}, []models.RoundScoreRow{row(ana), row(bruno)}, true},
+		{"invalidated since", &models.RoundScoreState{Round: 1, Generation: 3, ComputedGeneration: gen(2)}, []models.RoundScoreRow{row(ana), row(bruno)}, false},
+		{"invalidated, never computed", &models.RoundScoreState{Round: 1, Generation: 1}, nil, false},
+		{"expired", &models.RoundScoreState{Round: 1, Generation: 1, ComputedGeneration: gen(1), ValidUntil: timePtr(testNow)}, nil, false},
+		{"not expired yet", &models.RoundScoreState{Round: 1, Generation: 1, ComputedGeneration: gen(1), ValidUntil: timePtr(testNow.Add(time.Minute))}, nil, true},
+		{"no results", &models.RoundScoreState{Round: 1, Generation: 1, ComputedGeneration: gen(1)}, nil, true},
+		{"participant joined since", &models.RoundScoreState{Round: 1, Generation: 1, ComputedGeneration: gen(1)}, []models.RoundScoreRow{row(ana)}, false},
+	}
+	for _, tt := range tests {
+		c := &roundScoreCache{states: map[int]models.RoundScoreState{}, rows: map[int][]models.RoundScoreRow{1: tt.rows}}
+		if tt.state != nil {
+			c.states[1] = *tt.state
+		}
+		if got := c.fresh(1, participants, testNow); got != tt.want {
+			t.Errorf("%s: fresh = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCompareRoundRows(t *testing.T) {
+	ana, bruno, caio := exportUser("Ana"), exportUser("Bruno"), exportUser("Caio")
+	participants := []models.ParticipantView{{User: ana}, {User: bruno}, {User: caio}}
+	submitted := testNow.Add(-time.Hour)
+	cached := []models.RoundScoreRow{
+		{UserID: ana.ID, Round: 1, Points: 10, FirstSubmission: timePtr(submitted)},
+		{UserID: bruno.ID, Round: 1, Points: 5},
+	}
+	computed := []models.RoundScoreRow{
+		{UserID: ana.ID, Round: 1, Points: 10, FirstSubmission: timePtr(submitted.In(time.FixedZone("BRT", -3*3600)))},
+		{UserID: bruno.ID, Round: 1, Points: 7},
+		{UserID: caio.ID, Round: 1},
+	}
+
+	got := map[uuid.UUID]RoundScoreMismatch{}
+	for _, m := range compareRoundRows(1, participants, cached, computed) {
+		got[m.UserID] = m
+	}
+	if _, ok := got[ana.ID]; ok {
+		t.Error("Ana's rows differ only in time zone, reported as a mismatch")
+	}
+	if m, ok := got[bruno.ID]; !ok || m.Cached.Points != 5 || m.Computed.Points != 7 {
+		t.Errorf("Bruno's mismatch = %+v, want cached 5 and computed 7", m)
+	}
+	if m, ok := got[caio.ID]; !ok || m.Cached != nil || m.Computed == nil {
+		t.Errorf("Caio's mismatch = %+v, want a missing cached row", m)
+	}
+}
@@ -101,7 +101,7 @@ func TestSummarizeRoundsActive(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		got := SummarizeRounds(tt.matches, roundNow)
+		got := SummarizeRounds(tt.matches, nil, roundNow)
 		if got.Active != tt.wantActive {
 			t.Errorf("%s: active = %d, want %d", tt.name, got.Active, tt.wantActive)
 		}
@@ -122,7 +122,7 @@ func TestSummarizeRoundsActive(t *testing.T) {
 func TestSummarizeRoundsHalfFilledResult(t *testing.T) {
 	half := models.Match{ID: uuid.New(), Round: 2, HomeGoals: roundIntPtr(1)}
 
-	got := SummarizeRounds([]models.Match{played(1), half}, roundNow)
+	got := SummarizeRounds([]models.Match{played(1), half}, nil, roundNow)
 	if got.Active != 2 {
 		t.Errorf("active = %d, want 2 (a half-filled result does not finish the round)", got.Active)
 	}
@@ -180,7 +180,7 @@ func TestSummarizeRoundsPendingResults(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		if got := SummarizeRounds(tt.matches, roundNow); got.PendingResults != tt.want {
+		if got := SummarizeRounds(tt.matches, nil, roundNow); got.PendingResults != tt.want {
 			t.Errorf("%s: pending = %d, want %d", tt.name, got.PendingResults, tt.want)
 		}
 	}
@@ -188,7 +188,7 @@ func TestSummarizeRoundsPendingResults(t *testing.T) {
 
 // Rounds must come back sorted even when the matches do not.
 func TestSummarizeRoundsSortsRounds(t *testing.T) {
-	got := SummarizeRounds([]models.Match{scheduled(3), played(1), scheduled(2)}, roundNow)
+	got := SummarizeRounds([]models.Match{scheduled(3), played(1), scheduled(2)}, nil, roundNow)
 
 	want := []int{1, 2, 3}
 	for i, r := range want {
@@ -197,3 +197,13 @@ func TestSummarizeRoundsSortsRounds(t *testing.T) {
 		}
 	}
 }
+
+// Only the rounds that exist and don't weigh 100% are listed as boosted.
+func TestSummarizeRoundsWeights(t *testing.T) {
+	weights := []models.RoundWeight{{FromRound: 2, ToRound: 5, Percent: 150}}
+	got := SummarizeRounds([]models.Match{played(1), scheduled(2), scheduled(3)}, weights, roundNow)
+
+	if len(got.Weights) != 2 || got.Weights[2] != 150 || got.Weights[3] != 150 {
+		t.Errorf("weights = %v, want rounds 2 and 3 at 150", got.Weights)
+	}
+}
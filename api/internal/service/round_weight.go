@@ -0,0 +1,52 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/bolao-app/api/internal/models"
+)
+
+// fullWeight is the weight of a round no range boosts.
+const fullWeight = 100
+
+var ErrInvalidRoundWeights = errors.New("pesos de rodada inválidos")
+
+// ValidateRoundWeights accepts ranges of rounds that don't overlap, each with a positive
+// weight. An empty list is valid: every round weighs 100%.
+func ValidateRoundWeights(weights []models.RoundWeight) error {
+	for i, w := range weights {
+		if w.FromRound < 1 || w.ToRound < w.FromRound {
+			return fmt.Errorf("%w: faixa de rodadas %d–%d", ErrInvalidRoundWeights, w.FromRound, w.ToRound)
+		}
+		if w.Percent <= 0 {
+			return fmt.Errorf("%w: peso deve ser positivo", ErrInvalidRoundWeights)
+		}
+		for _, other := range weights[:i] {
+			if w.FromRound <= other.ToRound && other.FromRound <= w.ToRound {
+				return fmt.Errorf("%w: faixas %d–%d e %d–%d se sobrepõem",
+					ErrInvalidRoundWeights, other.FromRound, other.ToRound, w.FromRound, w.ToRound)
+			}
+		}
+	}
+	return nil
+}
+
+// RoundWeightPercent is the weight of round, in percent: that of the range it falls in,
+// 100 outside every range.
+func RoundWeightPercent(weights []models.RoundWeight, round int) int {
+	for _, w := range weights {
+		if round >= w.FromRound && round <= w.ToRound {
+			return w.Percent
+		}
+	}
+	return fullWeight
+}
+
+// weighRoundPoints scales a round's points by percent, rounded half up (SCORING.md §3.5).
+func weighRoundPoints(points, percent int) int {
+	if percent == fullWeight {
+		return points
+	}
+	return (2*points*percent + fullWeight) / (2 * fullWeight)
+}
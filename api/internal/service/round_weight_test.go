@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+)
+
+func TestValidateRoundWeights(t *testing.T) {
+	if err := ValidateRoundWeights([]models.RoundWeight{{FromRound: 1, ToRound: 34, Percent: 100}, {FromRound: 35, ToRound: 38, Percent: 150}}); err != nil {
+		t.Errorf("valid weights rejected: %v", err)
+	}
+	for name, weights := range map[string][]models.RoundWeight{
+		"inverted range": {{FromRound: 38, ToRound: 35, Percent: 150}},
+		"round zero":     {{FromRound: 0, ToRound: 1, Percent: 150}},
+		"zero weight":    {{FromRound: 35, ToRound: 38, Percent: 0}},
+		"overlap":        {{FromRound: 30, ToRound: 36, Percent: 120}, {FromRound: 35, ToRound: 38, Percent: 150}},
+	} {
+		if err := ValidateRoundWeights(weights); !errors.Is(err, ErrInvalidRoundWeights) {
+			t.Errorf("%s: err = %v, want ErrInvalidRoundWeights", name, err)
+		}
+	}
+}
+
+func TestWeighRoundPoints(t *testing.T) {
+	tests := []struct{ points, percent, want int }{
+		{20, 100, 20},
+		{20, 150, 30},
+		{15, 150, 23}, // 22.5 rounds half up
+		{7, 125, 9},   // 8.75
+		{0, 200, 0},
+	}
+	for _, tt := range tests {
+		if got := weighRoundPoints(tt.points, tt.percent); got != tt.want {
+			t.Errorf("weighRoundPoints(%d, %d) = %d, want %d", tt.points, tt.percent, got, tt.want)
+		}
+	}
+}
+
+// A boosted round adds its weighted points to the season, but its own table and winner
+// stay on the points scored.
+func TestStandingsApplyRoundWeights(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	first := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	last := exportMatch("Palmeiras", "Santos", 3, 1, closed)
+	last.Round = 2
+	ana := exportUser("Ana")
+	d := testSnapshot([]models.User{ana}, []models.Match{first, last}, []models.Prediction{
+		testPrediction(ana, first, 2, 1), testPrediction(ana, last, 3, 0),
+	})
+	d.settings.RoundWeights = []models.RoundWeight{{FromRound: 2, ToRound: 2, Percent: 150}}
+
+	round1 := d.roundTable(1, finalResults, testNow)[0].TotalPoints
+	round2 := d.roundTable(2, finalResults, testNow)[0].TotalPoints
+	if round2 == 0 {
+		t.Fatal("fixture scores nothing in round 2")
+	}
+
+	got := d.standings(d.maxRound, finalResults, testNow)[0]
+	if want := round1 + weighRoundPoints(round2, 150); got.TotalPoints != want {
+		t.Errorf("total = %d, want %d + 1.5 × %d = %d", got.TotalPoints, round1, round2, want)
+	}
+	if got.RoundsWon != 2 {
+		t.Errorf("rounds won = %d, want 2", got.RoundsWon)
+	}
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/bolao-app/api/internal/repository"
+	"github.com/google/uuid"
+)
+
+var ErrNoRuleset = errors.New("regras de pontuação não encontradas")
+
+// RulesetHistory is a bolão's rule versions, ordered by EffectiveFromRound. It is never
+// empty: version 1 is created with the bolão and covers round 1 onwards.
+type RulesetHistory []models.ScoringRuleset
+
+// NewRulesetHistory orders versions by the round they take effect from.
+func NewRulesetHistory(versions []models.ScoringRuleset) (RulesetHistory, error) {
+	if len(versions) == 0 {
+		return nil, ErrNoRuleset
+	}
+	h := append(RulesetHistory(nil), versions...)
+	sort.Slice(h, func(i, j int) bool { return h[i].EffectiveFromRound < h[j].EffectiveFromRound })
+	return h, nil
+}
+
+// LoadRulesetHistory fetches a bolão's rule versions; ErrNoRuleset when it has none.
+func LoadRulesetHistory(ctx context.Context, repo *repository.ScoringRulesetRepository, bolaoID uuid.UUID) (RulesetHistory, error) {
+	versions, err := repo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	return NewRulesetHistory(versions)
+}
+
+// ForRound returns the version in force at round: the last one that took effect at or
+// before it. The first version covers any round before its own start, so a history is
+// never without rules.
+func (h RulesetHistory) ForRound(round int) models.ScoringRuleset {
+	current := h[0]
+	for _, rs := range h[1:] {
+		if rs.EffectiveFromRound > round {
+			break
+		}
+		current = rs
+	}
+	return current
+}
+
+// Latest is the version in force from its round onwards — the one a new round gets.
+func (h RulesetHistory) Latest() models.ScoringRuleset {
+	return h[len(h)-1]
+}
+
+// NextRulesetVersion validates a rule change and numbers it. It must start after the
+// latest version — earlier rounds keep the rules they were played under — and keep the
+// engine, which is fixed when the bolão is created.
+func NextRulesetVersion(h RulesetHistory, next models.ScoringRuleset) (models.ScoringRuleset, error) {
+	if err := ValidateScoringRuleset(next); err != nil {
+		return next, err
+	}
+	latest := h.Latest()
+	if next.EffectiveFromRound <= latest.EffectiveFromRound {
+		return next, fmt.Errorf("%w: effective_from_round deve ser maior que %d, a rodada da versão atual", ErrInvalidRuleset, latest.EffectiveFromRound)
+	}
+	if next.Engine != latest.Engine {
+		return next, fmt.Errorf("%w: o sistema de pontuação (engine) não pode mudar depois da criação do bolão", ErrInvalidRuleset)
+	}
+	next.ID = uuid.New()
+	next.BolaoID = latest.BolaoID
+	next.Version = latest.Version + 1
+	return next, nil
+}
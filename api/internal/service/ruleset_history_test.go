@@ -0,0 +1,73 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// The mid-season change SCORING.md §7 describes: a draw without the exact score paid 9
+// before round 10 and 12 from then on.
+func drawRuleChange() RulesetHistory {
+	v1 := DefaultScoringRuleset()
+	v1.CorrectDraw = 9
+	v2 := DefaultScoringRuleset()
+	v2.Version, v2.EffectiveFromRound = 2, 10
+	h, _ := NewRulesetHistory([]models.ScoringRuleset{v2, v1})
+	return h
+}
+
+func TestRulesetHistoryForRound(t *testing.T) {
+	h := drawRuleChange()
+	for _, tt := range []struct{ round, wantVersion int }{{1, 1}, {9, 1}, {10, 2}, {38, 2}} {
+		if got := h.ForRound(tt.round).Version; got != tt.wantVersion {
+			t.Errorf("ForRound(%d) = version %d, want %d", tt.round, got, tt.wantVersion)
+		}
+	}
+	if got := h.Latest().Version; got != 2 {
+		t.Errorf("Latest() = version %d, want 2", got)
+	}
+
+	// 1×1 predicted, 2×2 played: the draw rule is the one that changed.
+	if got := CalculateMatchPoints(h.ForRound(9), 1, 1, 2, 2); got != 9 {
+		t.Errorf("draw in round 9 = %d, want 9 under the old rules", got)
+	}
+	if got := CalculateMatchPoints(h.ForRound(10), 1, 1, 2, 2); got != 12 {
+		t.Errorf("draw in round 10 = %d, want 12 under the new rules", got)
+	}
+}
+
+func TestNewRulesetHistoryEmpty(t *testing.T) {
+	if _, err := NewRulesetHistory(nil); !errors.Is(err, ErrNoRuleset) {
+		t.Errorf("empty history = %v, want ErrNoRuleset", err)
+	}
+}
+
+func TestNextRulesetVersion(t *testing.T) {
+	h := drawRuleChange()
+
+	next := h.Latest()
+	next.EffectiveFromRound = 20
+	next.CorrectDraw = 15
+	got, err := NextRulesetVersion(h, next)
+	if err != nil {
+		t.Fatalf("valid change rejected: %v", err)
+	}
+	if got.Version != 3 || got.ID == uuid.Nil || got.ID == h.Latest().ID {
+		t.Errorf("new version = %+v, want version 3 with a fresh ID", got)
+	}
+
+	// Rounds up to the current version's start were already played under it.
+	next.EffectiveFromRound = 10
+	if _, err := NextRulesetVersion(h, next); !errors.Is(err, ErrInvalidRuleset) {
+		t.Errorf("change rewriting round 10 accepted: %v", err)
+	}
+
+	next.EffectiveFromRound = 20
+	next.Engine = EngineClassic
+	if _, err := NextRulesetVersion(h, next); !errors.Is(err, ErrInvalidRuleset) {
+		t.Errorf("engine change accepted: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package service
+
+import "github.com/bolao-app/api/internal/models"
+
+// Scoring engines a bolão can pick at creation (models.ScoringRuleset.Engine).
+const (
+	EngineBolao    = "bolao"    // SCORING.md, with the ruleset's point values
+	EngineClassic  = "classic"  // 3 pontos pelo placar exato, 1 pelo resultado
+	EngineKicktipp = "kicktipp" // 4 placar exato, 3 saldo de gols, 2 resultado
+)
+
+// Fixed point values of the alternative engines. Unlike EngineBolao they ignore the
+// ruleset's point values; only JokerMultiplier and UnderdogMultiplier apply to every engine.
+const (
+	ClassicPointsExactScore    = 3
+	ClassicPointsCorrectResult = 1
+
+	KicktippPointsExactScore     = 4
+	KicktippPointsGoalDifference = 3
+	KicktippPointsCorrectResult  = 2
+)
+
+// Scorer is a scoring system. Every score in the app — standings, partials, breakdowns,
+// the CSV export — goes through the bolão's Scorer, picked by ScorerFor.
+type Scorer interface {
+	// ScoreMatch lists the rules one prediction earned against a result.
+	ScoreMatch(predHome, predAway, realHome, realAway int) []ScoreItem
+	// ScoreRound scores a participant's round: predictions[i] is the effective prediction
+	// for matches[i], with noPredSentinel for a match that doesn't count.
+	ScoreRound(predictions []PredEntry, matches []MatchScore, awardRoundTotalBonus bool) RoundBreakdown
+}
+
+// ScorerFor returns the engine the ruleset selects. An empty engine is a ruleset from before
+// engines existed, which always scored by SCORING.md.
+func ScorerFor(rules models.ScoringRuleset) Scorer {
+	switch rules.Engine {
+	case EngineClassic:
+		return classicScorer{rules}
+	case EngineKicktipp:
+		return kicktippScorer{rules}
+	default:
+		return bolaoScorer{rules}
+	}
+}
+
+func knownEngine(engine string) bool {
+	switch engine {
+	case EngineBolao, EngineClassic, EngineKicktipp:
+		return true
+	}
+	return false
+}
+
+// scoreRoundMatches is the aggregation every engine shares: it scores each counted
+// prediction with s, adds the underdog, favorite-team and qualifier bonuses, multiplies the
+// coringa and keeps the tiebreaker counts. Engines with round bonuses add them on top.
+func scoreRoundMatches(s Scorer, rules models.ScoringRuleset, predictions []PredEntry, matches []MatchScore) RoundBreakdown {
+	b := RoundBreakdown{Matches: make([][]ScoreItem, len(matches))}
+	for i, m := range matches {
+		p := predAt(predictions, i)
+		// Sentinel: missing prediction, market still open (see EffectivePredEntry).
+		if p.PredHome < 0 || p.PredAway < 0 {
+			continue
+		}
+
+		b.Matches[i] = s.ScoreMatch(p.PredHome, p.PredAway, m.HomeGoals, m.AwayGoals)
+		b.Matches[i] = applyUnderdog(rules, b.Matches[i], p, m)
+		b.Matches[i] = applyFavoriteTeam(rules, b.Matches[i], p, m)
+		b.Matches[i] = applyQualifier(rules, b.Matches[i], p)
+		if p.Joker {
+			b.Matches[i] = applyJoker(rules, b.Matches[i])
+		}
+		b.Points += sumItems(b.Matches[i])
+
+		if p.PredHome == m.HomeGoals && p.PredAway == m.AwayGoals {
+			b.ExactScores++
+		}
+		if matchResult(p.PredHome, p.PredAway) == matchResult(m.HomeGoals, m.AwayGoals) {
+			b.CorrectResults++
+		}
+	}
+	return b
+}
+
+// predAt returns predictions[i], or the zero entry — a 0×0 that counts — past the end of
+// the slice, which is what callers passing fewer predictions than matches always got.
+func predAt(predictions []PredEntry, i int) PredEntry {
+	if i < len(predictions) {
+		return predictions[i]
+	}
+	return PredEntry{}
+}
+
+// classicScorer is the classic pool: 3 points for the exact score, 1 for the right result
+// otherwise, no round bonuses.
+type classicScorer struct {
+	rules models.ScoringRuleset
+}
+
+func (classicScorer) ScoreMatch(predHome, predAway, realHome, realAway int) []ScoreItem {
+	if predHome == realHome && predAway == realAway {
+		return []ScoreItem{{Rule: "exact_score", Constant: "ClassicPointsExactScore", Points: ClassicPointsExactScore}}
+	}
+	if matchResult(predHome, predAway) == matchResult(realHome, realAway) {
+		return []ScoreItem{{Rule: "correct_result", Constant: "ClassicPointsCorrectResult", Points: ClassicPointsCorrectResult}}
+	}
+	return nil
+}
+
+func (s classicScorer) ScoreRound(predictions []PredEntry, matches []MatchScore, _ bool) RoundBreakdown {
+	return scoreRoundMatches(s, s.rules, predictions, matches)
+}
+
+// kicktippScorer is the Kicktipp default: 4 points for the exact score, 3 for the right
+// goal difference, 2 for the right result. A draw with the wrong score has the right goal
+// difference by definition, so it earns the 2 of the result only, as on Kicktipp.
+type kicktippScorer struct {
+	rules models.ScoringRuleset
+}
+
+func (kicktippScorer) ScoreMatch(predHome, predAway, realHome, realAway int) []ScoreItem {
+	if predHome == realHome && predAway == realAway {
+		return []ScoreItem{{Rule: "exact_score", Constant: "KicktippPointsExactScore", Points: KicktippPointsExactScore}}
+	}
+	result := matchResult(realHome, realAway)
+	if matchResult(predHome, predAway) != result {
+		return nil
+	}
+	if result != "draw" && predHome-predAway == realHome-realAway {
+		return []ScoreItem{{Rule: "goal_difference", Constant: "KicktippPointsGoalDifference", Points: KicktippPointsGoalDifference}}
+	}
+	return []ScoreItem{{Rule: "correct_result", Constant: "KicktippPointsCorrectResult", Points: KicktippPointsCorrectResult}}
+}
+
+func (s kicktippScorer) ScoreRound(predictions []PredEntry, matches []MatchScore, _ bool) RoundBreakdown {
+	return scoreRoundMatches(s, s.rules, predictions, matches)
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bolao-app/api/internal/models"
+)
+
+func rulesWithEngine(engine string) models.ScoringRuleset {
+	r := DefaultScoringRuleset()
+	r.Engine = engine
+	return r
+}
+
+func TestScorerForSelectsEngine(t *testing.T) {
+	if _, ok := ScorerFor(rulesWithEngine(EngineClassic)).(classicScorer); !ok {
+		t.Error("classic ruleset did not get the classic engine")
+	}
+	if _, ok := ScorerFor(rulesWithEngine(EngineKicktipp)).(kicktippScorer); !ok {
+		t.Error("kicktipp ruleset did not get the kicktipp engine")
+	}
+	// Rulesets stored before engines existed have no engine and always scored by SCORING.md.
+	if _, ok := ScorerFor(rulesWithEngine("")).(bolaoScorer); !ok {
+		t.Error("a ruleset without engine did not get the SCORING.md engine")
+	}
+}
+
+func TestValidateScoringRulesetEngine(t *testing.T) {
+	if err := ValidateScoringRuleset(rulesWithEngine("fantasy")); !errors.Is(err, ErrInvalidRuleset) {
+		t.Errorf("unknown engine accepted: %v", err)
+	}
+	for _, engine := range []string{EngineBolao, EngineClassic, EngineKicktipp} {
+		if err := ValidateScoringRuleset(rulesWithEngine(engine)); err != nil {
+			t.Errorf("engine %q rejected: %v", engine, err)
+		}
+	}
+}
+
+func TestAlternativeEngineMatchPoints(t *testing.T) {
+	tests := []struct {
+		engine             string
+		predHome, predAway int
+		realHome, realAway int
+		want               int
+	}{
+		{EngineClassic, 2, 1, 2, 1, 3},
+		{EngineClassic, 3, 1, 2, 1, 1},
+		{EngineClassic, 1, 1, 2, 2, 1},
+		{EngineClassic, 0, 1, 2, 1, 0},
+		{EngineKicktipp, 2, 1, 2, 1, 4},
+		{EngineKicktipp, 3, 2, 2, 1, 3}, // right goal difference
+		{EngineKicktipp, 3, 1, 2, 1, 2}, // right winner only
+		{EngineKicktipp, 1, 1, 2, 2, 2}, // a draw is a tendency, not a goal difference
+		{EngineKicktipp, 0, 1, 2, 1, 0},
+	}
+	for _, tt := range tests {
+		rules := rulesWithEngine(tt.engine)
+		if got := CalculateMatchPoints(rules, tt.predHome, tt.predAway, tt.realHome, tt.realAway); got != tt.want {
+			t.Errorf("%s: %d-%d against %d-%d = %d, want %d",
+				tt.engine, tt.predHome, tt.predAway, tt.realHome, tt.realAway, got, tt.want)
+		}
+	}
+}
+
+// The alternative engines have no round bonuses, but the coringa and the tiebreaker counts
+// come from the shared aggregation.
+func TestAlternativeEngineRound(t *testing.T) {
+	preds := []PredEntry{
+		{PredHome: 1, PredAway: 0, Joker: true},
+		{PredHome: 0, PredAway: 0},
+	}
+	results := []MatchScore{{HomeGoals: 1, AwayGoals: 0}, {HomeGoals: 0, AwayGoals: 0}}
+
+	b := CalculateRoundBreakdown(rulesWithEngine(EngineClassic), preds, results, true)
+	// 3×2 for the exact coringa, 3 for the other exact score. The SCORING.md engine would add
+	// the round-total and score-type bonuses here.
+	if b.Points != 9 || b.ExactScores != 2 || b.CorrectResults != 2 || len(b.Bonuses) != 0 {
+		t.Errorf("classic round = %+v, want 9 points, 2 exact, 2 correct, no bonuses", b)
+	}
+}
@@ -1,8 +1,14 @@
 package service
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
 
-// Scoring implements the rules from SCORING.md
+	"github.com/bolao-app/api/internal/models"
+)
+
+// Scoring implements the rules from SCORING.md. These are the defaults a new bolão gets;
+// scoring itself always goes through the bolão's own models.ScoringRuleset.
 const (
 	PointsCorrectResult    = 9  // Indicação correta do time vencedor
 	PointsCorrectDraw      = 12 // Indicação de empate sem acerto do placar
@@ -12,19 +18,113 @@ const (
 	PointsExactScoreHigh   = 10 // Acerto placar em jogos com 4+ gols
 	PointsTotalGoalsHigh   = 3  // Acerto número de gols em jogos com 4+ gols
 	PointsRoundTotalGoals  = 10 // Acerto número de gols da rodada
+	HighScoringGoals       = 4  // A partir de quantos gols o jogo conta como "4+"
+	JokerMultiplier        = 2  // Multiplicador dos pontos do jogo escolhido como coringa
+	PointsCorrectQualifier = 6  // Acerto de quem avança em jogo de mata-mata
 )
 
 // Bonus por quantidade diferente de placares acertados (tipos de resultado)
 // 1 tipo=0, 2 tipos=10, 3 tipos=20, 4+=30
-var bonusByScoreTypes = map[int]int{
-	1: 0,
-	2: 10,
-	3: 20,
-	4: 30,
+var bonusByScoreTypes = []int{0, 10, 20, 30}
+
+var ErrInvalidRuleset = errors.New("regras de pontuação inválidas")
+
+// DefaultScoringRuleset returns the rules from SCORING.md, unattached to any bolão.
+func DefaultScoringRuleset() models.ScoringRuleset {
+	return models.ScoringRuleset{
+		Version:            1,
+		EffectiveFromRound: 1,
+		Engine:             EngineBolao,
+		CorrectResult:      PointsCorrectResult,
+		CorrectDraw:        PointsCorrectDraw,
+		CorrectHomeGoals:   PointsCorrectHomeGoals,
+		CorrectAwayGoals:   PointsCorrectAwayGoals,
+		ExactScore:         PointsExactScore,
+		ExactScoreHigh:     PointsExactScoreHigh,
+		TotalGoalsHigh:     PointsTotalGoalsHigh,
+		HighScoringGoals:   HighScoringGoals,
+		RoundTotalGoals:    PointsRoundTotalGoals,
+		ScoreTypeBonus:     append([]int(nil), bonusByScoreTypes...),
+		JokerMultiplier:    JokerMultiplier,
+		QualifierPoints:    PointsCorrectQualifier,
+	}
+}
+
+// ValidateScoringRuleset rejects an unknown engine, negative point values, a high-scoring threshold below one
+// goal, which would make every match — 0×0 included — count as high-scoring, and a joker
+// or underdog multiplier that would take points away.
+func ValidateScoringRuleset(r models.ScoringRuleset) error {
+	if !knownEngine(r.Engine) {
+		return fmt.Errorf("%w: engine deve ser %q, %q ou %q", ErrInvalidRuleset, EngineBolao, EngineClassic, EngineKicktipp)
+	}
+	values := []int{
+		r.CorrectResult, r.CorrectDraw, r.CorrectHomeGoals, r.CorrectAwayGoals,
+		r.ExactScore, r.ExactScoreHigh, r.TotalGoalsHigh, r.RoundTotalGoals,
+		r.FavoriteTeamExactBonus, r.FavoriteTeamResultBonus, r.QualifierPoints,
+	}
+	values = append(values, r.ScoreTypeBonus...)
+	for _, v := range values {
+		if v < 0 {
+			return fmt.Errorf("%w: pontos não podem ser negativos", ErrInvalidRuleset)
+		}
+	}
+	if r.HighScoringGoals < 1 {
+		return fmt.Errorf("%w: high_scoring_goals deve ser pelo menos 1", ErrInvalidRuleset)
+	}
+	if r.JokerMultiplier < 1 {
+		return fmt.Errorf("%w: joker_multiplier deve ser pelo menos 1", ErrInvalidRuleset)
+	}
+	if r.UnderdogMultiplier < 0 {
+		return fmt.Errorf("%w: underdog_multiplier não pode ser negativo", ErrInvalidRuleset)
+	}
+	return nil
+}
+
+// ScoreItem is one rule that fired and what it paid. Constant names the SCORING.md default
+// the rule is configured by, so a player arguing about a score can look the rule up.
+// Detail says which instance of the rule it was, for rules that have several per round
+// (the prompt of a bonus question).
+type ScoreItem struct {
+	Rule     string `json:"rule"`
+	Constant string `json:"constant"`
+	Points   int    `json:"points"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+func sumItems(items []ScoreItem) int {
+	total := 0
+	for _, it := range items {
+		total += it.Points
+	}
+	return total
+}
+
+func CalculateMatchPoints(rules models.ScoringRuleset, predHome, predAway, realHome, realAway int) int {
+	return sumItems(MatchBreakdown(rules, predHome, predAway, realHome, realAway))
+}
+
+// MatchBreakdown lists every rule that awarded points for one match under the bolão's
+// scoring engine.
+func MatchBreakdown(rules models.ScoringRuleset, predHome, predAway, realHome, realAway int) []ScoreItem {
+	return ScorerFor(rules).ScoreMatch(predHome, predAway, realHome, realAway)
+}
+
+// bolaoScorer is the EngineBolao engine: the rules of SCORING.md, with the point values
+// taken from the ruleset.
+type bolaoScorer struct {
+	rules models.ScoringRuleset
 }
 
-func CalculateMatchPoints(predHome, predAway, realHome, realAway int) int {
-	points := 0
+// ScoreMatch lists the rules in the order SCORING.md §2 applies them. Rules worth 0 in the
+// ruleset are left out: they explain nothing.
+func (s bolaoScorer) ScoreMatch(predHome, predAway, realHome, realAway int) []ScoreItem {
+	rules := s.rules
+	var items []ScoreItem
+	award := func(rule, constant string, points int) {
+		if points != 0 {
+			items = append(items, ScoreItem{Rule: rule, Constant: constant, Points: points})
+		}
+	}
 
 	// Correct result: 9 pts (winner) ou 12 pts (empate sem acerto do placar)
 	predResult := matchResult(predHome, predAway)
@@ -32,43 +132,43 @@ func CalculateMatchPoints(predHome, predAway, realHome, realAway int) int {
 	if predResult == realResult {
 		exactScore := predHome == realHome && predAway == realAway
 		if realResult == "draw" && !exactScore {
-			points += PointsCorrectDraw // 12: empate sem acertar o placar
+			award("correct_draw", "PointsCorrectDraw", rules.CorrectDraw) // 12: empate sem acertar o placar
 		} else {
-			points += PointsCorrectResult // 9: vencedor ou empate exato
+			award("correct_result", "PointsCorrectResult", rules.CorrectResult) // 9: vencedor ou empate exato
 		}
 	}
 
 	// Correct home goals: 3 points
 	if predHome == realHome {
-		points += PointsCorrectHomeGoals
+		award("correct_home_goals", "PointsCorrectHomeGoals", rules.CorrectHomeGoals)
 	}
 
 	// Correct away goals: 3 points
 	if predAway == realAway {
-		points += PointsCorrectAwayGoals
+		award("correct_away_goals", "PointsCorrectAwayGoals", rules.CorrectAwayGoals)
 	}
 
 	// Exact score bonus
 	realTotal := realHome + realAway
 	if predHome == realHome && predAway == realAway {
-		if realTotal >= 4 {
-			points += PointsExactScoreHigh // 10 em jogos com 4+ gols
+		if realTotal >= rules.HighScoringGoals {
+			award("exact_score_high", "PointsExactScoreHigh", rules.ExactScoreHigh) // 10 em jogos com 4+ gols
 		} else {
-			points += PointsExactScore // 3 em jogos normais
+			award("exact_score", "PointsExactScore", rules.ExactScore) // 3 em jogos normais
 		}
 	}
 
 	// Bônus em jogos com 4+ gols (planilha: coluna P = P4:P13).
 	// Placar exato: +10 (já somado acima). Total de gols certo (com ou sem exato): +3.
 	// Na planilha exact em 4+ = 18(O)+10(P)=28; nós damos 9+3+3+10+3=28.
-	if realTotal >= 4 {
+	if realTotal >= rules.HighScoringGoals {
 		predTotal := predHome + predAway
 		if predTotal == realTotal {
-			points += PointsTotalGoalsHigh
+			award("total_goals_high", "PointsTotalGoalsHigh", rules.TotalGoalsHigh)
 		}
 	}
 
-	return points
+	return items
 }
 
 func matchResult(home, away int) string {
@@ -82,42 +182,51 @@ func matchResult(home, away int) string {
 }
 
 // awardRoundTotalBonus: true = rodada completa (classificação final/export); false = parciais (não dar bônus, pois a soma dos palpites é da rodada inteira).
-func CalculateRoundPoints(predictions []PredEntry, matches []MatchScore, awardRoundTotalBonus bool) (int, int, int) {
-	totalPoints := 0
-	exactScores := 0
-	correctResults := 0
+func CalculateRoundPoints(rules models.ScoringRuleset, predictions []PredEntry, matches []MatchScore, awardRoundTotalBonus bool) (int, int, int) {
+	b := CalculateRoundBreakdown(rules, predictions, matches, awardRoundTotalBonus)
+	return b.Points, b.ExactScores, b.CorrectResults
+}
 
-	// Build prediction map by match index
-	predMap := make(map[int]PredEntry)
-	for i, p := range predictions {
-		predMap[i] = p
-	}
+// RoundBreakdown is CalculateRoundPoints with the working shown.
+type RoundBreakdown struct {
+	// Matches[i] explains matches[i]; nil when the match was skipped (see noPredSentinel)
+	// or scored nothing.
+	Matches [][]ScoreItem
+	// Bonuses are the round-level rules of SCORING.md §3.
+	Bonuses        []ScoreItem
+	Points         int
+	ExactScores    int
+	CorrectResults int
+	// RoundTotalHits is 1 when the round-total-goals bonus was earned. Missed counts the
+	// matches scored on a 0×0 filled in for a missing prediction; only the caller knows
+	// which those are, so the calculators leave it at 0.
+	RoundTotalHits int
+	Missed         int
+}
+
+func CalculateRoundBreakdown(rules models.ScoringRuleset, predictions []PredEntry, matches []MatchScore, awardRoundTotalBonus bool) RoundBreakdown {
+	return ScorerFor(rules).ScoreRound(predictions, matches, awardRoundTotalBonus)
+}
+
+// ScoreRound adds the round bonuses of SCORING.md §3 to the shared per-match aggregation.
+func (s bolaoScorer) ScoreRound(predictions []PredEntry, matches []MatchScore, awardRoundTotalBonus bool) RoundBreakdown {
+	rules := s.rules
+	b := scoreRoundMatches(s, rules, predictions, matches)
 
 	// Track exact score types for bonus
 	exactScoreTypes := make(map[string]bool)
 	roundPredTotal := 0
 	counted := 0
-
 	for i, m := range matches {
-		p := predMap[i]
-		// Sentinel: missing prediction, market still open (see EffectivePredEntry).
+		p := predAt(predictions, i)
 		if p.PredHome < 0 || p.PredAway < 0 {
 			continue
 		}
 		counted++
 		roundPredTotal += p.PredHome + p.PredAway
-
-		pts := CalculateMatchPoints(p.PredHome, p.PredAway, m.HomeGoals, m.AwayGoals)
-		totalPoints += pts
-
 		if p.PredHome == m.HomeGoals && p.PredAway == m.AwayGoals {
-			exactScores++
 			exactScoreTypes[scoreKey(m.HomeGoals, m.AwayGoals)] = true
 		}
-
-		if matchResult(p.PredHome, p.PredAway) == matchResult(m.HomeGoals, m.AwayGoals) {
-			correctResults++
-		}
 	}
 
 	// Round total goals: 10 points — só quando rodada completa (parciais não: os palpites são da rodada inteira).
@@ -127,20 +236,40 @@ func CalculateRoundPoints(predictions []PredEntry, matches []MatchScore, awardRo
 	}
 	// counted > 0: with nothing counted, roundPredTotal is 0 vacuously and would match an
 	// all-0-0 round, rewarding a player who predicted nothing.
-	if awardRoundTotalBonus && counted > 0 && roundPredTotal == actualRoundTotal {
-		totalPoints += PointsRoundTotalGoals
+	if awardRoundTotalBonus && counted > 0 && roundPredTotal == actualRoundTotal && rules.RoundTotalGoals != 0 {
+		b.Bonuses = append(b.Bonuses, ScoreItem{Rule: "round_total_goals", Constant: "PointsRoundTotalGoals", Points: rules.RoundTotalGoals})
+		b.RoundTotalHits = 1
 	}
 
 	// Bonus by different exact score types
-	scoreTypes := len(exactScoreTypes)
-	if scoreTypes >= 4 {
-		scoreTypes = 4
+	if bonus := scoreTypeBonus(rules, len(exactScoreTypes)); bonus != 0 {
+		b.Bonuses = append(b.Bonuses, ScoreItem{Rule: "score_type_bonus", Constant: "bonusByScoreTypes", Points: bonus})
 	}
-	if bonus, ok := bonusByScoreTypes[scoreTypes]; ok {
-		totalPoints += bonus
+	b.Points += sumItems(b.Bonuses)
+
+	return b
+}
+
+// applyJoker multiplies a coringa match's points by adding them again, multiplier-1 times,
+// as one more item. Only the match is multiplied: round bonuses never are.
+func applyJoker(rules models.ScoringRuleset, items []ScoreItem) []ScoreItem {
+	extra := sumItems(items) * (rules.JokerMultiplier - 1)
+	if extra == 0 {
+		return items
 	}
+	return append(items, ScoreItem{Rule: "joker", Constant: "JokerMultiplier", Points: extra})
+}
 
-	return totalPoints, exactScores, correctResults
+// scoreTypeBonus caps types at the last ScoreTypeBonus entry, the way "4+" caps the
+// default table. No exact score at all earns nothing.
+func scoreTypeBonus(rules models.ScoringRuleset, types int) int {
+	if types < 1 || len(rules.ScoreTypeBonus) == 0 {
+		return 0
+	}
+	if types > len(rules.ScoreTypeBonus) {
+		types = len(rules.ScoreTypeBonus)
+	}
+	return rules.ScoreTypeBonus[types-1]
 }
 
 func scoreKey(home, away int) string {
@@ -2,15 +2,19 @@ package service
 
 import "testing"
 
+// The SCORING.md values, which every test written before rulesets existed assumes.
+var defaultRules = DefaultScoringRuleset()
+var defaultScorer = ScorerFor(defaultRules)
+
 func TestCalculateRoundPoints(t *testing.T) {
 	// User scenario: 2 exact (1-0, 0-1), 1 correct result only, 1 home goals only, 1 away goals only
 	// Expected: 18+18+9+3+3 = 51 base, 2 types = 10 bonus, total 61
-	preds := []struct{ PredHome, PredAway int }{
-		{1, 0}, // exact 1-0
-		{0, 1}, // exact 0-1
-		{2, 1}, // correct result only (real 1-0)
-		{1, 0}, // home only (real 1-2)
-		{1, 1}, // away only (real 2-1)
+	preds := []PredEntry{
+		{PredHome: 1, PredAway: 0}, // exact 1-0
+		{PredHome: 0, PredAway: 1}, // exact 0-1
+		{PredHome: 2, PredAway: 1}, // correct result only (real 1-0)
+		{PredHome: 1, PredAway: 0}, // home only (real 1-2)
+		{PredHome: 1, PredAway: 1}, // away only (real 2-1)
 	}
 	matches := []struct{ HomeGoals, AwayGoals int }{
 		{1, 0},
@@ -19,7 +23,7 @@ func TestCalculateRoundPoints(t *testing.T) {
 		{1, 2},
 		{2, 1},
 	}
-	got, _, _ := CalculateRoundPoints(preds, matches, true)
+	got, _, _ := CalculateRoundPoints(defaultRules, preds, matches, true)
 	if got != 61 {
 		t.Errorf("CalculateRoundPoints = %d, want 61 (51 base + 10 bonus)", got)
 	}
@@ -28,11 +32,11 @@ func TestCalculateRoundPoints(t *testing.T) {
 // The counted > 0 guard must not cost a real player the round-total bonus: predicted
 // total 1 against an actual total of 1, so the 10 points still apply.
 func TestCalculateRoundPointsAwardsRoundTotalBonus(t *testing.T) {
-	preds := []PredEntry{{1, 0}, {0, 0}}
+	preds := []PredEntry{{PredHome: 1, PredAway: 0}, {PredHome: 0, PredAway: 0}}
 	matches := []MatchScore{{0, 1}, {0, 0}}
 
-	withBonus, _, _ := CalculateRoundPoints(preds, matches, true)
-	withoutBonus, _, _ := CalculateRoundPoints(preds, matches, false)
+	withBonus, _, _ := CalculateRoundPoints(defaultRules, preds, matches, true)
+	withoutBonus, _, _ := CalculateRoundPoints(defaultRules, preds, matches, false)
 
 	if withBonus != withoutBonus+PointsRoundTotalGoals {
 		t.Errorf("with bonus = %d, without = %d, want a %d difference",
@@ -44,10 +48,10 @@ func TestCalculateRoundPointsAwardsRoundTotalBonus(t *testing.T) {
 // prediction totals 1 goal against an actual total of 1, so the bonus applies even though
 // a skipped match sits alongside it.
 func TestCalculateRoundPointsSentinelIgnoredInRoundTotal(t *testing.T) {
-	preds := []PredEntry{{1, 0}, {noPredSentinel, noPredSentinel}}
+	preds := []PredEntry{{PredHome: 1, PredAway: 0}, {PredHome: noPredSentinel, PredAway: noPredSentinel}}
 	matches := []MatchScore{{1, 0}, {0, 0}}
 
-	got, exact, correct := CalculateRoundPoints(preds, matches, true)
+	got, exact, correct := CalculateRoundPoints(defaultRules, preds, matches, true)
 	// 18 for the exact 1-0 + 10 round total. The skipped match scores nothing and is not
 	// counted as an exact score or a correct result.
 	if got != 28 || exact != 1 || correct != 1 {
@@ -60,11 +64,11 @@ func TestCalculateRoundPointsSentinelIgnoredInRoundTotal(t *testing.T) {
 func TestCalculateRoundPointsNoShowAddsScoreType(t *testing.T) {
 	matches := []MatchScore{{1, 0}, {0, 0}}
 
-	oneType := []PredEntry{{1, 0}, {noPredSentinel, noPredSentinel}}
-	twoTypes := []PredEntry{{1, 0}, {0, 0}}
+	oneType := []PredEntry{{PredHome: 1, PredAway: 0}, {PredHome: noPredSentinel, PredAway: noPredSentinel}}
+	twoTypes := []PredEntry{{PredHome: 1, PredAway: 0}, {PredHome: 0, PredAway: 0}}
 
-	before, _, _ := CalculateRoundPoints(oneType, matches, false)
-	after, _, _ := CalculateRoundPoints(twoTypes, matches, false)
+	before, _, _ := CalculateRoundPoints(defaultRules, oneType, matches, false)
+	after, _, _ := CalculateRoundPoints(defaultRules, twoTypes, matches, false)
 
 	// The extra match is worth 18 on its own, plus the 10-point bonus for a second
 	// distinct exact-score type.
@@ -90,10 +94,200 @@ func TestCalculateMatchPoints(t *testing.T) {
 		{1, 2, 1, 2, 18}, // exact 3 goals
 	}
 	for _, tt := range tests {
-		got := CalculateMatchPoints(tt.predHome, tt.predAway, tt.realHome, tt.realAway)
+		got := CalculateMatchPoints(defaultRules, tt.predHome, tt.predAway, tt.realHome, tt.realAway)
 		if got != tt.want {
 			t.Errorf("CalculateMatchPoints(%d,%d,%d,%d) = %d, want %d",
 				tt.predHome, tt.predAway, tt.realHome, tt.realAway, got, tt.want)
 		}
 	}
 }
+
+// A bolão created with different values must be scored with them, not with the defaults.
+func TestCalculateMatchPointsCustomRuleset(t *testing.T) {
+	rules := DefaultScoringRuleset()
+	rules.CorrectResult = 5
+	rules.CorrectDraw = 5
+	rules.ExactScoreHigh = 20
+	rules.HighScoringGoals = 5
+
+	tests := []struct {
+		predHome, predAway, realHome, realAway int
+		want                                   int
+	}{
+		{1, 0, 1, 0, 14}, // exact: 5+3+3+3
+		{0, 0, 1, 1, 5},  // draw without the exact score now pays CorrectDraw=5
+		{3, 1, 3, 1, 14}, // 4 goals is no longer high-scoring: 5+3+3+3
+		{3, 2, 3, 2, 34}, // 5 goals is: 5+3+3+20+3
+	}
+	for _, tt := range tests {
+		got := CalculateMatchPoints(rules, tt.predHome, tt.predAway, tt.realHome, tt.realAway)
+		if got != tt.want {
+			t.Errorf("CalculateMatchPoints(%d,%d,%d,%d) = %d, want %d",
+				tt.predHome, tt.predAway, tt.realHome, tt.realAway, got, tt.want)
+		}
+	}
+}
+
+func TestScoreTypeBonusCapsAtLastEntry(t *testing.T) {
+	rules := DefaultScoringRuleset()
+	rules.ScoreTypeBonus = []int{0, 5}
+
+	tests := []struct{ types, want int }{
+		{0, 0},
+		{1, 0},
+		{2, 5},
+		{6, 5}, // everything past the table pays the last entry
+	}
+	for _, tt := range tests {
+		if got := scoreTypeBonus(rules, tt.types); got != tt.want {
+			t.Errorf("scoreTypeBonus(%d types) = %d, want %d", tt.types, got, tt.want)
+		}
+	}
+
+	rules.ScoreTypeBonus = nil
+	if got := scoreTypeBonus(rules, 3); got != 0 {
+		t.Errorf("scoreTypeBonus with an empty table = %d, want 0", got)
+	}
+}
+
+func TestValidateScoringRuleset(t *testing.T) {
+	if err := ValidateScoringRuleset(DefaultScoringRuleset()); err != nil {
+		t.Errorf("the default ruleset is invalid: %v", err)
+	}
+
+	negative := DefaultScoringRuleset()
+	negative.CorrectDraw = -1
+	if err := ValidateScoringRuleset(negative); err == nil {
+		t.Error("a negative point value was accepted")
+	}
+
+	negativeBonus := DefaultScoringRuleset()
+	negativeBonus.ScoreTypeBonus = []int{0, -10}
+	if err := ValidateScoringRuleset(negativeBonus); err == nil {
+		t.Error("a negative score-type bonus was accepted")
+	}
+
+	noThreshold := DefaultScoringRuleset()
+	noThreshold.HighScoringGoals = 0
+	if err := ValidateScoringRuleset(noThreshold); err == nil {
+		t.Error("a zero high-scoring threshold was accepted")
+	}
+
+	zeroJoker := DefaultScoringRuleset()
+	zeroJoker.JokerMultiplier = 0
+	if err := ValidateScoringRuleset(zeroJoker); err == nil {
+		t.Error("a joker multiplier that wipes the match out was accepted")
+	}
+}
+
+// The 3×3 case of SCORING.md §6.1, rule by rule.
+func TestMatchBreakdownHighScoringExact(t *testing.T) {
+	items := MatchBreakdown(defaultRules, 3, 3, 3, 3)
+
+	want := []ScoreItem{
+		{Rule: "correct_result", Constant: "PointsCorrectResult", Points: PointsCorrectResult},
+		{Rule: "correct_home_goals", Constant: "PointsCorrectHomeGoals", Points: PointsCorrectHomeGoals},
+		{Rule: "correct_away_goals", Constant: "PointsCorrectAwayGoals", Points: PointsCorrectAwayGoals},
+		{Rule: "exact_score_high", Constant: "PointsExactScoreHigh", Points: PointsExactScoreHigh},
+		{Rule: "total_goals_high", Constant: "PointsTotalGoalsHigh", Points: PointsTotalGoalsHigh},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("MatchBreakdown(3,3,3,3) = %+v, want %+v", items, want)
+	}
+	for i := range want {
+		if items[i] != want[i] {
+			t.Errorf("item %d = %+v, want %+v", i, items[i], want[i])
+		}
+	}
+	if sumItems(items) != 28 {
+		t.Errorf("items sum to %d, want 28", sumItems(items))
+	}
+}
+
+// A rule set to 0 in the ruleset fired but explains nothing, so it is left out.
+func TestMatchBreakdownSkipsZeroValueRules(t *testing.T) {
+	rules := DefaultScoringRuleset()
+	rules.CorrectHomeGoals = 0
+
+	for _, it := range MatchBreakdown(rules, 1, 0, 1, 0) {
+		if it.Rule == "correct_home_goals" {
+			t.Errorf("a 0-point rule was listed: %+v", it)
+		}
+	}
+}
+
+func TestCalculateRoundBreakdownAgreesWithPoints(t *testing.T) {
+	preds := []PredEntry{
+		{PredHome: 1, PredAway: 0},
+		{PredHome: 0, PredAway: 1},
+		{PredHome: 2, PredAway: 1},
+		{PredHome: 1, PredAway: 0},
+		{PredHome: 1, PredAway: 1},
+	}
+	matches := []MatchScore{{1, 0}, {0, 1}, {1, 0}, {1, 2}, {2, 1}}
+
+	b := CalculateRoundBreakdown(defaultRules, preds, matches, true)
+	points, exact, correct := CalculateRoundPoints(defaultRules, preds, matches, true)
+	if b.Points != points || b.ExactScores != exact || b.CorrectResults != correct {
+		t.Errorf("breakdown {%d %d %d} disagrees with CalculateRoundPoints {%d %d %d}",
+			b.Points, b.ExactScores, b.CorrectResults, points, exact, correct)
+	}
+
+	sum := sumItems(b.Bonuses)
+	for _, items := range b.Matches {
+		sum += sumItems(items)
+	}
+	if sum != b.Points {
+		t.Errorf("items sum to %d, breakdown total is %d", sum, b.Points)
+	}
+}
+
+// The coringa multiplies the match's own points only: the variety and round-total bonuses
+// of the round are added once, as always.
+func TestCalculateRoundPointsJoker(t *testing.T) {
+	matches := []MatchScore{{1, 0}, {2, 2}}
+	plain := []PredEntry{{PredHome: 1, PredAway: 0}, {PredHome: 1, PredAway: 1}}
+	withJoker := []PredEntry{{PredHome: 1, PredAway: 0, Joker: true}, {PredHome: 1, PredAway: 1}}
+
+	before, exactBefore, correctBefore := CalculateRoundPoints(defaultRules, plain, matches, true)
+	after, exactAfter, correctAfter := CalculateRoundPoints(defaultRules, withJoker, matches, true)
+
+	// The exact 1-0 is worth 18, so doubling it adds 18 more.
+	if after-before != 18 {
+		t.Errorf("the coringa added %d points, want 18 (the 1-0 counted twice)", after-before)
+	}
+	if exactAfter != exactBefore || correctAfter != correctBefore {
+		t.Errorf("the coringa changed the tiebreaker counts: exact %d→%d, correct %d→%d",
+			exactBefore, exactAfter, correctBefore, correctAfter)
+	}
+
+	b := CalculateRoundBreakdown(defaultRules, withJoker, matches, true)
+	last := b.Matches[0][len(b.Matches[0])-1]
+	if last.Rule != "joker" || last.Points != 18 {
+		t.Errorf("last item of the coringa match = %+v, want the 18-point joker item", last)
+	}
+}
+
+func TestCalculateRoundPointsJokerOnMiss(t *testing.T) {
+	matches := []MatchScore{{3, 0}}
+	preds := []PredEntry{{PredHome: 0, PredAway: 2, Joker: true}}
+
+	got, _, _ := CalculateRoundPoints(defaultRules, preds, matches, false)
+	if got != 0 {
+		t.Errorf("a coringa on a missed match scored %d, want 0", got)
+	}
+	if items := CalculateRoundBreakdown(defaultRules, preds, matches, false).Matches[0]; len(items) != 0 {
+		t.Errorf("a coringa worth nothing was listed: %+v", items)
+	}
+}
+
+func TestCalculateRoundPointsJokerMultiplierFromRuleset(t *testing.T) {
+	rules := DefaultScoringRuleset()
+	rules.JokerMultiplier = 3
+	matches := []MatchScore{{1, 0}}
+
+	got, _, _ := CalculateRoundPoints(rules, []PredEntry{{PredHome: 1, PredAway: 0, Joker: true}}, matches, false)
+	if got != 54 {
+		t.Errorf("a triple coringa on an exact 1-0 scored %d, want 54", got)
+	}
+}
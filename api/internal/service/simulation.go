@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidSimulation = errors.New("simulação inválida")
+
+// HypotheticalResult is a score to assume for a match that has no final result yet.
+type HypotheticalResult struct {
+	MatchID   uuid.UUID `json:"match_id"`
+	HomeGoals int       `json:"home_goals"`
+	AwayGoals int       `json:"away_goals"`
+}
+
+// Simulation is the standings as they would be with the hypothetical results.
+type Simulation struct {
+	Round          int                    `json:"round"`
+	RoundStandings []models.UserWithStats `json:"round_standings"`
+	Standings      []models.UserWithStats `json:"standings"`
+}
+
+// simulatedResults layers the hypothetical results over the real ones: a final result
+// always counts as is, a hypothetical one stands in for a match without it, and the
+// parcial of a match in play counts for the rest, as in GetClassificationByPartials.
+func simulatedResults(hypothetical map[uuid.UUID]HypotheticalResult, partials map[uuid.UUID]models.MatchPartial) resultSource {
+	return func(m models.Match) (countedResult, bool) {
+		if r, ok := finalResults(m); ok {
+			return r, true
+		}
+		if h, ok := hypothetical[m.ID]; ok {
+			return countedResult{home: h.HomeGoals, away: h.AwayGoals}, true
+		}
+		if p, ok := partials[m.ID]; ok && p.HomeGoals != nil && p.AwayGoals != nil {
+			return countedResult{home: *p.HomeGoals, away: *p.AwayGoals, partial: true}, true
+		}
+		return countedResult{}, false
+	}
+}
+
+// simulate validates the hypothetical results against the bolão's matches and computes
+// the round and cumulative standings with them. round 0 means the latest round the
+// results touch.
+func (d *bolaoSnapshot) simulate(round int, results []HypotheticalResult, partials map[uuid.UUID]models.MatchPartial, now time.Time) (*Simulation, error) {
+	matches := make(map[uuid.UUID]models.Match)
+	for _, ms := range d.byRound {
+		for _, m := range ms {
+			matches[m.ID] = m
+		}
+	}
+
+	hypothetical := make(map[uuid.UUID]HypotheticalResult, len(results))
+	latest := 0
+	for _, r := range results {
+		m, ok := matches[r.MatchID]
+		if !ok {
+			return nil, fmt.Errorf("%w: jogo %s não pertence ao bolão", ErrInvalidSimulation, r.MatchID)
+		}
+		if m.HomeGoals != nil && m.AwayGoals != nil {
+			return nil, fmt.Errorf("%w: %s x %s já tem resultado", ErrInvalidSimulation, m.HomeTeam, m.AwayTeam)
+		}
+		if r.HomeGoals < 0 || r.AwayGoals < 0 {
+			return nil, fmt.Errorf("%w: gols não podem ser negativos", ErrInvalidSimulation)
+		}
+		if _, dup := hypothetical[r.MatchID]; dup {
+			return nil, fmt.Errorf("%w: jogo %s repetido", ErrInvalidSimulation, r.MatchID)
+		}
+		hypothetical[r.MatchID] = r
+		latest = max(latest, m.Round)
+	}
+	if round == 0 {
+		round = latest
+	}
+	if round <= 0 {
+		return nil, fmt.Errorf("%w: informe a rodada", ErrInvalidSimulation)
+	}
+
+	source := simulatedResults(hypothetical, partials)
+	return &Simulation{
+		Round:          round,
+		RoundStandings: d.roundTable(round, source, now),
+		Standings:      d.standings(d.maxRound, source, now),
+	}, nil
+}
+
+// Simulate answers "what if": the standings with hypothetical scores for matches still
+// without a result. Nothing is written.
+func (s *ClassificationService) Simulate(ctx context.Context, bolaoID uuid.UUID, round int, results []HypotheticalResult) (*Simulation, error) {
+	d, err := s.loadSnapshot(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	partials, err := s.partialRepo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	return d.simulate(round, results, partials, time.Now())
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// testSnapshot is a bolão under the default rules, without coringas, questions or
+// season-long bets.
+func testSnapshot(users []models.User, matches []models.Match, preds []models.Prediction) *bolaoSnapshot {
+	participants := make([]models.ParticipantView, 0, len(users))
+	for _, u := range users {
+		participants = append(participants, models.ParticipantView{User: u})
+	}
+	d := &bolaoSnapshot{
+		participants: participants,
+		rulesets:     RulesetHistory{defaultRules},
+		predictions:  indexPredictions(preds),
+		submissions:  firstSubmissions(preds, matchRounds(matches)),
+	}
+	d.setMatches(matches)
+	return d
+}
+
+func testPrediction(user models.User, m models.Match, home, away int) models.Prediction {
+	return models.Prediction{UserID: user.ID, MatchID: m.ID, HomeGoals: home, AwayGoals: away, CreatedAt: testNow.Add(-2 * time.Hour)}
+}
+
+// A round with one final result (2×1) and one match still to finish. Ana got the 2×1;
+// Bruno got its result and home goals, and bets on a 2×0 in the other match.
+func simulationFixture() (d *bolaoSnapshot, ana, bruno models.User, played, pending models.Match) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	played = exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	pending = models.Match{ID: uuid.New(), Round: 1, HomeTeam: "Palmeiras", AwayTeam: "Santos", MarketClosesAt: closed}
+	ana, bruno = exportUser("Ana"), exportUser("Bruno")
+	d = testSnapshot([]models.User{ana, bruno}, []models.Match{played, pending}, []models.Prediction{
+		testPrediction(ana, played, 2, 1), testPrediction(ana, pending, 1, 1),
+		testPrediction(bruno, played, 2, 0), testPrediction(bruno, pending, 2, 0),
+	})
+	return
+}
+
+func TestSimulateHypotheticalResultDecidesRound(t *testing.T) {
+	d, ana, bruno, _, pending := simulationFixture()
+
+	actual := d.standings(d.maxRound, finalResults, testNow)
+	if actual[0].ID != ana.ID {
+		t.Fatalf("real standings led by %s, want Ana", actual[0].DisplayName)
+	}
+
+	sim, err := d.simulate(0, []HypotheticalResult{{MatchID: pending.ID, HomeGoals: 2, AwayGoals: 0}}, nil, testNow)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if sim.Round != 1 {
+		t.Errorf("round = %d, want 1, the round of the simulated match", sim.Round)
+	}
+	if sim.RoundStandings[0].ID != bruno.ID || sim.Standings[0].ID != bruno.ID {
+		t.Errorf("with 2×0 the leader is %s (round) / %s (overall), want Bruno",
+			sim.RoundStandings[0].DisplayName, sim.Standings[0].DisplayName)
+	}
+
+	sim, err = d.simulate(0, []HypotheticalResult{{MatchID: pending.ID, HomeGoals: 1, AwayGoals: 1}}, nil, testNow)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if sim.Standings[0].ID != ana.ID {
+		t.Errorf("with 1×1 the leader is %s, want Ana", sim.Standings[0].DisplayName)
+	}
+	// A hypothetical result completes the round: Ana's 5 predicted goals match.
+	if sim.Standings[0].RoundTotalHits != 1 {
+		t.Errorf("Ana's round-total hits = %d, want 1 with the round complete", sim.Standings[0].RoundTotalHits)
+	}
+}
+
+// Parciais count for matches the simulation leaves out, but as partial scores: the round
+// isn't complete, so no round-total bonus.
+func TestSimulateLayersPartials(t *testing.T) {
+	d, ana, _, _, pending := simulationFixture()
+	partials := map[uuid.UUID]models.MatchPartial{pending.ID: {MatchID: pending.ID, HomeGoals: intPtr(1), AwayGoals: intPtr(1)}}
+
+	sim, err := d.simulate(1, nil, partials, testNow)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	withPartial := sim.Standings[0]
+	if withPartial.ID != ana.ID || withPartial.RoundTotalHits != 0 {
+		t.Errorf("leader = %s with %d round-total hits, want Ana with 0 on a parcial", withPartial.DisplayName, withPartial.RoundTotalHits)
+	}
+
+	// A hypothetical result wins over the parcial.
+	sim, err = d.simulate(1, []HypotheticalResult{{MatchID: pending.ID, HomeGoals: 1, AwayGoals: 1}}, partials, testNow)
+	if err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+	if got := sim.Standings[0].TotalPoints; got != withPartial.TotalPoints+PointsRoundTotalGoals {
+		t.Errorf("1×1 as a hypothetical final = %d points, want the parcial's %d plus the round-total bonus", got, withPartial.TotalPoints)
+	}
+}
+
+func TestSimulateRejectsInvalidResults(t *testing.T) {
+	d, _, _, played, pending := simulationFixture()
+	tests := []struct {
+		name    string
+		round   int
+		results []HypotheticalResult
+	}{
+		{"final result", 0, []HypotheticalResult{{MatchID: played.ID, HomeGoals: 0, AwayGoals: 0}}},
+		{"unknown match", 0, []HypotheticalResult{{MatchID: uuid.New()}}},
+		{"negative goals", 0, []HypotheticalResult{{MatchID: pending.ID, HomeGoals: -1}}},
+		{"repeated match", 0, []HypotheticalResult{{MatchID: pending.ID}, {MatchID: pending.ID}}},
+		{"no round", 0, nil},
+	}
+	for _, tt := range tests {
+		if _, err := d.simulate(tt.round, tt.results, nil, testNow); !errors.Is(err, ErrInvalidSimulation) {
+			t.Errorf("%s: err = %v, want ErrInvalidSimulation", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// countedResult is the score a match counts with in the standings.
+type countedResult struct {
+	home, away int
+	// partial marks a score still in play (match_partials): a round with one doesn't earn
+	// the round-total bonus (SCORING.md §3.1).
+	partial bool
+}
+
+// resultSource says which score a match counts with: the final result, or in a simulation
+// a hypothetical or partial one. ok=false leaves the match out of its round.
+type resultSource func(m models.Match) (r countedResult, ok bool)
+
+// finalResults counts the final results only — the real standings.
+func finalResults(m models.Match) (countedResult, bool) {
+	if m.HomeGoals == nil || m.AwayGoals == nil {
+		return countedResult{}, false
+	}
+	return countedResult{home: *m.HomeGoals, away: *m.AwayGoals}, true
+}
+
+// bolaoSnapshot is everything a bolão's standings are computed from, fetched in bulk once
+// (see loadSnapshot) so the standings can be recomputed over other results without going
+// back to the database.
+type bolaoSnapshot struct {
+	settings       models.BolaoSettings
+	participants   []models.ParticipantView
+	rulesets       RulesetHistory
+	byRound        map[int][]models.Match
+	maxRound       int
+	predictions    predictionIndex
+	submissions    map[uuid.UUID]map[int]time.Time
+	jokers         jokerIndex
+	questions      questionIndex
+	outrightPoints map[uuid.UUID]int
+	shares         OutcomeShareIndex
+	favorites      FavoriteTeamIndex
+	ties           TieIndex
+}
+
+// loadSnapshot fetches the bolão in a fixed number of queries, regardless of round count,
+// instead of one query per round per participant.
+func (s *ClassificationService) loadSnapshot(ctx context.Context, bolaoID uuid.UUID) (*bolaoSnapshot, error) {
+	d, err := s.loadStandingsBase(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	return d, s.loadAllRounds(ctx, d, bolaoID)
+}
+
+// perRoundLoadLimit is the most rounds loadRounds fetches round by round; past it, five
+// queries per round cost more than fetching the whole bolão.
+const perRoundLoadLimit = 4
+
+// loadRounds returns a copy of the standings base d with the matches, predictions, jokers
+// and bonus questions of rounds only — enough to score those rounds and nothing else.
+func (s *ClassificationService) loadRounds(ctx context.Context, d *bolaoSnapshot, bolaoID uuid.UUID, rounds []int) (*bolaoSnapshot, error) {
+	rd := *d
+	if len(rounds) > perRoundLoadLimit {
+		return &rd, s.loadAllRounds(ctx, &rd, bolaoID)
+	}
+	var (
+		matches     []models.Match
+		predictions []models.Prediction
+		jokers      []models.Joker
+		questions   []models.RoundQuestion
+		answers     []models.RoundQuestionAnswer
+	)
+	for _, round := range rounds {
+		roundMatches, err := s.matchRepo.ListByRound(ctx, bolaoID, round)
+		if err != nil {
+			return nil, err
+		}
+		roundPredictions, err := s.predictionRepo.GetAllPredictionsForRound(ctx, bolaoID, round)
+		if err != nil {
+			return nil, err
+		}
+		roundJokers, err := s.jokerRepo.ListByRound(ctx, bolaoID, round)
+		if err != nil {
+			return nil, err
+		}
+		roundQuestions, err := s.questionRepo.ListByRound(ctx, bolaoID, round)
+		if err != nil {
+			return nil, err
+		}
+		roundAnswers, err := s.questionRepo.ListAnswersByRound(ctx, bolaoID, round)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, roundMatches...)
+		predictions = append(predictions, roundPredictions...)
+		jokers = append(jokers, roundJokers...)
+		questions = append(questions, roundQuestions...)
+		answers = append(answers, roundAnswers...)
+	}
+	return &rd, s.indexRounds(ctx, &rd, bolaoID, matches, predictions, jokers, questions, answers)
+}
+
+// loadAllRounds fills the standings base d with every round of the bolão.
+func (s *ClassificationService) loadAllRounds(ctx context.Context, d *bolaoSnapshot, bolaoID uuid.UUID) error {
+	allMatches, err := s.matchRepo.ListAllByBolao(ctx, bolaoID)
+	if err != nil {
+		return err
+	}
+	allPredictions, err := s.predictionRepo.GetAllForBolao(ctx, bolaoID)
+	if err != nil {
+		return err
+	}
+	allJokers, err := s.jokerRepo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return err
+	}
+	allQuestions, err := s.questionRepo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return err
+	}
+	allAnswers, err := s.questionRepo.ListAnswersByBolao(ctx, bolaoID)
+	if err != nil {
+		return err
+	}
+	return s.indexRounds(ctx, d, bolaoID, allMatches, allPredictions, allJokers, allQuestions, allAnswers)
+}
+
+// indexRounds fills d with the rounds of matches and what scoring them reads beyond their
+// own rows: the outcome splits, the favorite teams and the two-legged ties.
+func (s *ClassificationService) indexRounds(
+	ctx context.Context,
+	d *bolaoSnapshot,
+	bolaoID uuid.UUID,
+	matches []models.Match,
+	predictions []models.Prediction,
+	jokers []models.Joker,
+	questions []models.RoundQuestion,
+	answers []models.RoundQuestionAnswer,
+) error {
+	var err error
+	d.shares, err = LoadOutcomeShares(ctx, s.shareRepo, bolaoID, d.rulesets, matches, participantIDs(d.participants), predictions, time.Now())
+	if err != nil {
+		return err
+	}
+	d.favorites, err = LoadFavoriteTeams(ctx, s.userRepo, bolaoID, d.rulesets)
+	if err != nil {
+		return err
+	}
+	d.ties, err = LoadTies(ctx, s.tieRepo, s.matchRepo, s.predictionRepo, bolaoID, d.rulesets)
+	if err != nil {
+		return err
+	}
+
+	d.predictions = indexPredictions(predictions)
+	d.submissions = firstSubmissions(predictions, matchRounds(matches))
+	d.jokers = indexJokers(jokers)
+	d.questions = indexRoundQuestions(questions, answers)
+	d.setMatches(matches)
+	return nil
+}
+
+// loadStandingsBase fetches what adding up the standings needs beyond the round scores:
+// the settings, the participants, the rules and the season-long bets.
+func (s *ClassificationService) loadStandingsBase(ctx context.Context, bolaoID uuid.UUID) (*bolaoSnapshot, error) {
+	bolao, err := s.bolaoRepo.GetByID(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	participants, err := s.bolaoRepo.ListParticipants(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	rulesets, err := LoadRulesetHistory(ctx, s.rulesetRepo, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	// Season-long bets only score once an admin enters their outcome, so before the end of
+	// the season this adds nothing.
+	outrights, err := s.outrightRepo.ListQuestions(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	outrightAnswers, err := s.outrightRepo.ListAnswersByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	return &bolaoSnapshot{
+		settings:       bolao.BolaoSettings,
+		participants:   participants,
+		rulesets:       rulesets,
+		outrightPoints: outrightPointsByUser(outrights, outrightAnswers),
+	}, nil
+}
+
+func (d *bolaoSnapshot) setMatches(matches []models.Match) {
+	d.byRound = make(map[int][]models.Match)
+	d.maxRound = 0
+	for _, m := range matches {
+		d.byRound[m.Round] = append(d.byRound[m.Round], m)
+		if m.Round > d.maxRound {
+			d.maxRound = m.Round
+		}
+	}
+}
+
+// ranking is the ranking of the bolão's settings.
+func (d *bolaoSnapshot) ranking() Ranking {
+	return RankingFor(d.settings)
+}
+
+// scoreRound scores every participant over the round's matches that source counts;
+// ok=false when it counts none. Each round is scored by the rules in force when it was
+// played.
+func (d *bolaoSnapshot) scoreRound(round int, source resultSource, now time.Time) (map[uuid.UUID]roundScore, bool) {
+	counted, awardRoundTotalBonus := d.countedMatches(round, source)
+	if len(counted) == 0 {
+		return nil, false
+	}
+
+	rules := d.rulesets.ForRound(round)
+	scores := make(map[uuid.UUID]roundScore, len(d.participants))
+	for _, participant := range d.participants {
+		// A snapshot of a few rounds (loadRounds) may not have the first legs of its ties.
+		lookup := d.ties.withFirstLegs(participant.ID, d.predictions.of(participant.ID))
+		scores[participant.ID] = scoreParticipantRound(rules, counted, lookup, d.jokers.match(participant.ID, round), d.favorites.forRound(participant.ID, d.byRound[round], now),
+			d.questions.items(participant.ID, round), awardRoundTotalBonus, now)
+	}
+	return scores, true
+}
+
+// countedMatches is the round's matches that source counts, with their results, and
+// whether the round-total bonus can be paid on them (none of them is partial).
+func (d *bolaoSnapshot) countedMatches(round int, source resultSource) (counted []matchWithResult, awardRoundTotalBonus bool) {
+	awardRoundTotalBonus = true
+	for _, m := range d.byRound[round] {
+		r, ok := source(m)
+		if !ok {
+			continue
+		}
+		counted = append(counted, matchWithResult{m, r.home, r.away, d.shares.of(m.ID), d.ties[m.ID]})
+		awardRoundTotalBonus = awardRoundTotalBonus && !r.partial
+	}
+	return counted, awardRoundTotalBonus
+}
+
+// standings is the cumulative classification over rounds 1..upToRound, each scored with
+// the results source counts.
+func (d *bolaoSnapshot) standings(upToRound int, source resultSource, now time.Time) []models.UserWithStats {
+	return d.aggregate(upToRound, func(round int) (map[uuid.UUID]roundScore, bool) {
+		return d.scoreRound(round, source, now)
+	})
+}
+
+// aggregate adds up the round scores scoresOf returns for rounds 1..upToRound (see
+// seasonTotals). scoresOf may only return participants; ok=false leaves the round out.
+func (d *bolaoSnapshot) aggregate(upToRound int, scoresOf func(round int) (map[uuid.UUID]roundScore, bool)) []models.UserWithStats {
+	t := d.newSeasonTotals()
+	for round := 1; round <= upToRound; round++ {
+		if scores, ok := scoresOf(round); ok {
+			t.add(round, scores)
+		}
+	}
+	return t.table()
+}
+
+// seasonTotals accumulates the cumulative standings round by round, so the table after
+// every round can be read in a single pass.
+type seasonTotals struct {
+	d         *bolaoSnapshot
+	userStats map[uuid.UUID]*models.UserWithStats
+	// What the tiebreakers need beyond the counters of UserWithStats.
+	standings map[uuid.UUID]Standing
+	// rangeOnly leaves the season-long bets and the discarded rounds out of the table, for
+	// standings over part of the season (see partStandings and history).
+	rangeOnly bool
+}
+
+func (d *bolaoSnapshot) newSeasonTotals() *seasonTotals {
+	t := &seasonTotals{
+		d:         d,
+		userStats: make(map[uuid.UUID]*models.UserWithStats, len(d.participants)),
+		standings: make(map[uuid.UUID]Standing, len(d.participants)),
+	}
+	for _, p := range d.participants {
+		t.userStats[p.ID] = &models.UserWithStats{User: p.User, AmountPaid: p.AmountPaid}
+	}
+	return t
+}
+
+// add counts a round's scores, weighted by the bolão's round weights, and returns the
+// round's winner (ok=false when nobody scored).
+func (t *seasonTotals) add(round int, scores map[uuid.UUID]roundScore) (winner uuid.UUID, ok bool) {
+	d := t.d
+	// The weight scales what the round adds to the season; the round's own table and its
+	// winner stay on the points actually scored.
+	weight := RoundWeightPercent(d.settings.RoundWeights, round)
+	for userID, rs := range scores {
+		points := weighRoundPoints(rs.points, weight)
+		u := t.userStats[userID]
+		u.TotalPoints += points
+		u.ExactScores += rs.exactScores
+		u.CorrectResults += rs.correctResults
+		u.MissedPredictions += rs.missed
+		u.RoundTotalHits += rs.roundTotalHits
+
+		st := t.standings[userID]
+		if st.RoundPoints == nil {
+			st.RoundPoints = make(map[int]int)
+		}
+		st.RoundPoints[round] = points
+		if at, ok := d.submissions[userID][round]; ok {
+			st.FirstSubmission = earliest(st.FirstSubmission, at)
+		}
+		t.standings[userID] = st
+	}
+
+	winner, ok = pickRoundWinner(d.ranking(), roundStandings(scores, d.submissions, round))
+	if ok {
+		t.userStats[winner].RoundsWon++
+	}
+	return winner, ok
+}
+
+// table is the ranked standings over the rounds added so far, plus the season-long bets,
+// less each player's discarded worst rounds. It leaves the totals as they are, so more
+// rounds can be added after.
+func (t *seasonTotals) table() []models.UserWithStats {
+	d := t.d
+	// Build result in participant order, so ties the chain can't break stay by name.
+	result := make([]models.UserWithStats, 0, len(t.userStats))
+	for _, p := range d.participants {
+		u := *t.userStats[p.ID]
+		if t.rangeOnly {
+			result = append(result, u)
+			continue
+		}
+		if pts, ok := d.outrightPoints[p.ID]; ok {
+			u.OutrightPoints = pts
+			u.TotalPoints += pts
+		}
+
+		// Only the points are net of the discarded rounds: the counters the tiebreakers
+		// read keep every round.
+		u.GrossPoints = u.TotalPoints
+		roundPoints := t.standings[p.ID].RoundPoints
+		u.DiscardedRounds = worstRounds(roundPoints, d.settings.DropWorstRounds)
+		for _, round := range u.DiscardedRounds {
+			u.TotalPoints -= roundPoints[round]
+		}
+		result = append(result, u)
+	}
+	rankUsers(d.ranking(), result, t.standings)
+	return result
+}
+
+// roundTable is the classification of a single round (points in that round only). When
+// source counts no match of the round, everyone is on zero.
+func (d *bolaoSnapshot) roundTable(round int, source resultSource, now time.Time) []models.UserWithStats {
+	scores, _ := d.scoreRound(round, source, now)
+	result := make([]models.UserWithStats, 0, len(d.participants))
+	for _, p := range d.participants {
+		rs := scores[p.ID]
+		result = append(result, models.UserWithStats{
+			User:              p.User,
+			AmountPaid:        p.AmountPaid,
+			TotalPoints:       rs.points,
+			ExactScores:       rs.exactScores,
+			CorrectResults:    rs.correctResults,
+			MissedPredictions: rs.missed,
+			RoundTotalHits:    rs.roundTotalHits,
+		})
+	}
+	rankUsers(d.ranking(), result, roundStandings(scores, d.submissions, round))
+	return result
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/bolao-app/api/internal/constants"
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Accuracy is how well a set of predictions read the final results: the 0×0 filled in for
+// a missing one counts, as in the scoring.
+type Accuracy struct {
+	Predictions int `json:"predictions"`
+	// CorrectResults counts the predictions that called the outcome, exact scores included.
+	CorrectResults    int     `json:"correct_results"`
+	CorrectResultRate float64 `json:"correct_result_rate"`
+	ExactScores       int     `json:"exact_scores"`
+	ExactScoreRate    float64 `json:"exact_score_rate"`
+	// AvgGoalError is how many goals a prediction is off by, both sides added up.
+	AvgGoalError float64 `json:"avg_goal_error"`
+}
+
+// PlayerAccuracy is one participant's Accuracy.
+type PlayerAccuracy struct {
+	UserID      uuid.UUID `json:"user_id"`
+	DisplayName string    `json:"display_name"`
+	Accuracy
+}
+
+// SideAccuracy is the accuracy on a team's games at home, or away.
+type SideAccuracy struct {
+	Matches int              `json:"matches"`
+	Group   Accuracy         `json:"group"`
+	Players []PlayerAccuracy `json:"players"`
+}
+
+// TeamAccuracy is how the group and each participant read a team's games.
+type TeamAccuracy struct {
+	Team string       `json:"team"`
+	Home SideAccuracy `json:"home"`
+	Away SideAccuracy `json:"away"`
+}
+
+// accuracyTally adds up predictions into an Accuracy.
+type accuracyTally struct {
+	predictions, correct, exact, goalError int
+}
+
+func (t *accuracyTally) add(predHome, predAway, home, away int) {
+	t.predictions++
+	if predHome == home && predAway == away {
+		t.exact++
+	}
+	if winnerSide(predHome, predAway) == winnerSide(home, away) {
+		t.correct++
+	}
+	t.goalError += abs(predHome-home) + abs(predAway-away)
+}
+
+func (t accuracyTally) accuracy() Accuracy {
+	a := Accuracy{Predictions: t.predictions, CorrectResults: t.correct, ExactScores: t.exact}
+	if t.predictions > 0 {
+		n := float64(t.predictions)
+		a.CorrectResultRate = float64(t.correct) / n
+		a.ExactScoreRate = float64(t.exact) / n
+		a.AvgGoalError = float64(t.goalError) / n
+	}
+	return a
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// sideTally is the tallies of one side of a team's games.
+type sideTally struct {
+	matches int
+	group   accuracyTally
+	players map[uuid.UUID]*accuracyTally
+}
+
+func (st *sideTally) accuracy(users []models.User) SideAccuracy {
+	sa := SideAccuracy{Matches: st.matches, Group: st.group.accuracy(), Players: make([]PlayerAccuracy, 0, len(users))}
+	for _, u := range users {
+		pa := PlayerAccuracy{UserID: u.ID, DisplayName: u.DisplayName}
+		if t := st.players[u.ID]; t != nil {
+			pa.Accuracy = t.accuracy()
+		}
+		sa.Players = append(sa.Players, pa)
+	}
+	return sa
+}
+
+// teamAccuracy is the accuracy on the games of every team of constants.Teams with a final
+// result, in that order, home and away apart. Teams without one are left out.
+func (d *bolaoSnapshot) teamAccuracy(now time.Time) []TeamAccuracy {
+	users := participantUsers(d.participants)
+	type sides struct{ home, away sideTally }
+	byTeam := make(map[string]*sides, len(constants.Teams))
+	for _, team := range constants.Teams {
+		byTeam[team] = &sides{
+			home: sideTally{players: make(map[uuid.UUID]*accuracyTally)},
+			away: sideTally{players: make(map[uuid.UUID]*accuracyTally)},
+		}
+	}
+	for _, ms := range d.byRound {
+		for _, m := range ms {
+			r, ok := finalResults(m)
+			if !ok {
+				continue
+			}
+			var tallies []*sideTally
+			if s := byTeam[m.HomeTeam]; s != nil {
+				tallies = append(tallies, &s.home)
+			}
+			if s := byTeam[m.AwayTeam]; s != nil {
+				tallies = append(tallies, &s.away)
+			}
+			for _, st := range tallies {
+				st.matches++
+			}
+			for _, u := range users {
+				p, has := d.predictions[u.ID][m.ID]
+				ph, pa, counts := EffectivePrediction(m, p.Home, p.Away, has, now)
+				if !counts {
+					continue
+				}
+				for _, st := range tallies {
+					st.group.add(ph, pa, r.home, r.away)
+					if st.players[u.ID] == nil {
+						st.players[u.ID] = &accuracyTally{}
+					}
+					st.players[u.ID].add(ph, pa, r.home, r.away)
+				}
+			}
+		}
+	}
+
+	teams := make([]TeamAccuracy, 0, len(constants.Teams))
+	for _, team := range constants.Teams {
+		s := byTeam[team]
+		if s.home.matches+s.away.matches == 0 {
+			continue
+		}
+		teams = append(teams, TeamAccuracy{Team: team, Home: s.home.accuracy(users), Away: s.away.accuracy(users)})
+	}
+	return teams
+}
+
+// GetTeamAccuracy returns, team by team, how well the bolão's participants read its games.
+func (s *ClassificationService) GetTeamAccuracy(ctx context.Context, bolaoID uuid.UUID) ([]TeamAccuracy, error) {
+	d, err := s.loadSnapshot(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	return d.teamAccuracy(time.Now()), nil
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// Flamengo wins 2×1 at home and loses 0×1 away; Ana reads both, Bruno neither. The match
+// against a club outside constants.Teams counts only for Flamengo.
+func TestTeamAccuracy(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	home := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	away := exportMatch("Sport", "Flamengo", 0, 1, closed)
+	away.Round = 2
+	open := models.Match{ID: uuid.New(), Round: 3, HomeTeam: "Santos", AwayTeam: "Bahia", MarketClosesAt: timePtr(testNow.Add(time.Hour))}
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	d := testSnapshot([]models.User{ana, bruno}, []models.Match{home, away, open}, []models.Prediction{
+		testPrediction(ana, home, 2, 1), testPrediction(bruno, home, 0, 3),
+		testPrediction(ana, away, 1, 2),
+	})
+
+	teams := d.teamAccuracy(testNow)
+	if len(teams) != 2 || teams[0].Team != "Flamengo" || teams[1].Team != "Vasco" {
+		t.Fatalf("teams = %+v, want Flamengo and Vasco in constants.Teams order", teams)
+	}
+
+	fla := teams[0]
+	if fla.Home.Matches != 1 || fla.Away.Matches != 1 {
+		t.Errorf("Flamengo matches = %d home, %d away, want 1 and 1", fla.Home.Matches, fla.Away.Matches)
+	}
+	want := Accuracy{Predictions: 2, CorrectResults: 1, CorrectResultRate: 0.5, ExactScores: 1, ExactScoreRate: 0.5, AvgGoalError: 2}
+	if fla.Home.Group != want {
+		t.Errorf("Flamengo at home = %+v, want %+v", fla.Home.Group, want)
+	}
+	// Bruno's missing prediction counts as the 0×0 it became at the close.
+	if p := fla.Away.Players[1]; p.UserID != bruno.ID || p.Predictions != 1 || p.CorrectResults != 0 || p.AvgGoalError != 1 {
+		t.Errorf("Bruno away = %+v, want one 0×0 a goal off", p)
+	}
+	if p := fla.Away.Players[0]; p.CorrectResults != 1 || p.ExactScores != 0 || p.AvgGoalError != 2 {
+		t.Errorf("Ana away = %+v, want the result two goals off", p)
+	}
+	if v := teams[1]; v.Home.Matches != 0 || v.Away.Matches != 1 || v.Away.Group.ExactScores != 1 {
+		t.Errorf("Vasco = %+v, want one away game read exactly once", v)
+	}
+}
+
+func TestBuildCSVTeamAccuracy(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	m := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	ana := exportUser("Ana")
+	raw, err := buildCSV(RulesetHistory{defaultRules}, models.BolaoSettings{}, []int{1}, nil, []models.Match{m},
+		[]models.User{ana}, []models.Prediction{testPrediction(ana, m, 1, 0)}, nil, nil, nil, nil, nil, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := sectionAfter(parseCSV(t, raw), "Erro_Medio_Gols")
+	if len(rows) != 4 {
+		t.Fatalf("team rows = %v, want the group and Ana for each side", rows)
+	}
+	if got := rows[0]; got[0] != "Flamengo" || got[1] != "Mandante" || got[2] != teamGroupRow || got[5] != "100,0" || got[7] != "0,0" || got[8] != "2,00" {
+		t.Errorf("Flamengo group row = %v", got)
+	}
+	if got := rows[3]; got[0] != "Vasco" || got[1] != "Visitante" || got[2] != "Ana" {
+		t.Errorf("last row = %v, want Ana on Vasco away", got)
+	}
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/bolao-app/api/internal/repository"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidTie = errors.New("confronto inválido")
+
+// ValidateTie checks that first and second can be the legs of a tie: two matches of the
+// same bolão between the same teams with home and away swapped, the first not played
+// after the second. The first leg can end level, so it can't be a knockout match.
+func ValidateTie(first, second models.Match) error {
+	if first.ID == second.ID {
+		return fmt.Errorf("%w: os dois jogos são o mesmo", ErrInvalidTie)
+	}
+	if first.BolaoID != second.BolaoID {
+		return fmt.Errorf("%w: os jogos são de bolões diferentes", ErrInvalidTie)
+	}
+	if first.HomeTeam != second.AwayTeam || first.AwayTeam != second.HomeTeam {
+		return fmt.Errorf("%w: a volta de %s x %s é %s x %s", ErrInvalidTie, first.HomeTeam, first.AwayTeam, first.AwayTeam, first.HomeTeam)
+	}
+	if first.Round > second.Round {
+		return fmt.Errorf("%w: o jogo de ida é da rodada %d, depois da volta", ErrInvalidTie, first.Round)
+	}
+	if first.Knockout {
+		return fmt.Errorf("%w: o jogo de ida não pode ser de mata-mata", ErrInvalidTie)
+	}
+	return nil
+}
+
+// tieWinner is the side of the second leg ahead on the aggregate of the first leg's
+// firstHome×firstAway and the second leg's secondHome×secondAway, breaking a level
+// aggregate on away goals when the tie uses them; "" while level.
+func tieWinner(t models.Tie, firstHome, firstAway, secondHome, secondAway int) string {
+	// The second leg's home side played the first leg away.
+	if w := winnerSide(secondHome+firstAway, secondAway+firstHome); w != "" || !t.AwayGoals {
+		return w
+	}
+	return winnerSide(firstAway, secondAway)
+}
+
+// ValidateTieResult is ValidateMatchResult for the second leg of t, whose first leg is
+// first: extra time and penalties only when the aggregate leaves the tie undecided.
+func ValidateTieResult(t models.Tie, first models.Match, r models.MatchResult) error {
+	if first.HomeGoals == nil || first.AwayGoals == nil {
+		if r.ExtraTimeHomeGoals != nil || r.ExtraTimeAwayGoals != nil || r.PenaltyWinner != nil {
+			return fmt.Errorf("%w: informe antes o resultado do jogo de ida", ErrInvalidKnockout)
+		}
+		return validateDecider(r, func(home, away int) bool { return true })
+	}
+	return validateDecider(r, func(home, away int) bool {
+		return tieWinner(t, *first.HomeGoals, *first.AwayGoals, home, away) == ""
+	})
+}
+
+// ValidateTiePick checks the advances pick on the second leg of a tie. The aggregate a
+// player predicts also depends on their first-leg prediction, which may still change, so
+// either side is accepted: the pick only counts when that aggregate ends level.
+func ValidateTiePick(advances *string) error {
+	if advances != nil && !validSide(*advances) {
+		return fmt.Errorf("%w: advances deve ser %q ou %q", ErrInvalidKnockout, SideHome, SideAway)
+	}
+	return nil
+}
+
+// tieLegs is a tie as scoring its second leg needs it.
+type tieLegs struct {
+	tie   models.Tie
+	first models.Match
+	// firstPredictions are the first-leg predictions by user, for the scoring paths that
+	// only load the second leg's round (see withFirstLegs).
+	firstPredictions map[uuid.UUID]storedPrediction
+}
+
+// qualifier is the side of second that went through the tie: on aggregate, then on the
+// aggregate after extra time, then on penalties. "" until both legs have a result and the
+// tie is decided.
+func (l *tieLegs) qualifier(second models.Match) string {
+	first := l.first
+	if first.HomeGoals == nil || first.AwayGoals == nil || second.HomeGoals == nil || second.AwayGoals == nil {
+		return ""
+	}
+	if w := tieWinner(l.tie, *first.HomeGoals, *first.AwayGoals, *second.HomeGoals, *second.AwayGoals); w != "" {
+		return w
+	}
+	if second.ExtraTimeHomeGoals != nil && second.ExtraTimeAwayGoals != nil {
+		if w := tieWinner(l.tie, *first.HomeGoals, *first.AwayGoals, *second.ExtraTimeHomeGoals, *second.ExtraTimeAwayGoals); w != "" {
+			return w
+		}
+	}
+	if second.PenaltyWinner != nil {
+		return *second.PenaltyWinner
+	}
+	return ""
+}
+
+// predictedQualifier is the side a player's two predictions send through: the one ahead
+// on their aggregate, or their advances pick when it is level.
+func (l *tieLegs) predictedQualifier(firstHome, firstAway, secondHome, secondAway int, advances string) string {
+	if w := tieWinner(l.tie, firstHome, firstAway, secondHome, secondAway); w != "" {
+		return w
+	}
+	return advances
+}
+
+// TieIndex maps the second leg of each two-legged tie to the tie, where it is decided.
+type TieIndex map[uuid.UUID]*tieLegs
+
+func indexTies(ties []models.Tie, matches []models.Match, firstLegPredictions []models.Prediction) TieIndex {
+	byID := make(map[uuid.UUID]models.Match, len(matches))
+	for _, m := range matches {
+		byID[m.ID] = m
+	}
+	byFirstLeg := make(map[uuid.UUID]*tieLegs, len(ties))
+	index := make(TieIndex, len(ties))
+	for _, t := range ties {
+		l := &tieLegs{tie: t, first: byID[t.FirstLegID], firstPredictions: make(map[uuid.UUID]storedPrediction)}
+		index[t.SecondLegID] = l
+		byFirstLeg[t.FirstLegID] = l
+	}
+	for _, p := range firstLegPredictions {
+		if l, ok := byFirstLeg[p.MatchID]; ok {
+			l.firstPredictions[p.UserID] = stored(p)
+		}
+	}
+	return index
+}
+
+// withFirstLegs extends userID's lookup to their first-leg predictions, which a scoring
+// path that loads a single round misses when the legs are in different rounds.
+func (idx TieIndex) withFirstLegs(userID uuid.UUID, lookup predictionLookup) predictionLookup {
+	if len(idx) == 0 {
+		return lookup
+	}
+	firstLegs := make(map[uuid.UUID]*tieLegs, len(idx))
+	for _, l := range idx {
+		firstLegs[l.first.ID] = l
+	}
+	return func(matchID uuid.UUID) (storedPrediction, bool) {
+		if p, has := lookup(matchID); has {
+			return p, true
+		}
+		if l, ok := firstLegs[matchID]; ok {
+			p, has := l.firstPredictions[userID]
+			return p, has
+		}
+		return storedPrediction{}, false
+	}
+}
+
+// LoadTies returns the bolão's two-legged ties with their first legs and the predictions
+// on them. When no version of the rules pays the qualifier points, or there is no tie, it
+// returns nil after at most one query.
+func LoadTies(
+	ctx context.Context,
+	tieRepo *repository.TieRepository,
+	matchRepo *repository.MatchRepository,
+	predictionRepo *repository.PredictionRepository,
+	bolaoID uuid.UUID,
+	rulesets RulesetHistory,
+) (TieIndex, error) {
+	if !rulesets.usesQualifier() {
+		return nil, nil
+	}
+	ties, err := tieRepo.ListByBolao(ctx, bolaoID)
+	if err != nil || len(ties) == 0 {
+		return nil, err
+	}
+	matches, err := matchRepo.ListAllByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	predictions, err := predictionRepo.GetFirstLegsForBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+	return indexTies(ties, matches, predictions), nil
+}
+
+// usesQualifier reports whether any version of the rules pays the qualifier points.
+func (h RulesetHistory) usesQualifier() bool {
+	for _, rs := range h {
+		if rs.QualifierPoints > 0 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+// tieFixture is a tie between Bahia and Remo: the first leg in Belém in round 1, the
+// second in Salvador in round 2, neither with a result.
+func tieFixture(awayGoals bool) (models.Tie, models.Match, models.Match) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	first := models.Match{ID: uuid.New(), Round: 1, HomeTeam: "Remo", AwayTeam: "Bahia", MarketClosesAt: closed}
+	second := models.Match{ID: uuid.New(), Round: 2, HomeTeam: "Bahia", AwayTeam: "Remo", MarketClosesAt: closed, Knockout: true}
+	tie := models.Tie{ID: uuid.New(), FirstLegID: first.ID, SecondLegID: second.ID, AwayGoals: awayGoals}
+	return tie, first, second
+}
+
+func withResult(m models.Match, home, away int) models.Match {
+	m.HomeGoals, m.AwayGoals = intPtr(home), intPtr(away)
+	return m
+}
+
+func TestValidateTie(t *testing.T) {
+	_, first, second := tieFixture(false)
+	knockoutFirst := first
+	knockoutFirst.Knockout = true
+	sameSides := second
+	sameSides.HomeTeam, sameSides.AwayTeam = first.HomeTeam, first.AwayTeam
+	earlier := second
+	earlier.Round = 0
+
+	if err := ValidateTie(first, second); err != nil {
+		t.Errorf("valid tie: %v", err)
+	}
+	tests := []struct {
+		name          string
+		first, second models.Match
+	}{
+		{"same match", first, first},
+		{"home and away not swapped", first, sameSides},
+		{"second leg before the first", first, earlier},
+		{"knockout first leg", knockoutFirst, second},
+	}
+	for _, tt := range tests {
+		if err := ValidateTie(tt.first, tt.second); !errors.Is(err, ErrInvalidTie) {
+			t.Errorf("%s: error = %v, want ErrInvalidTie", tt.name, err)
+		}
+	}
+}
+
+func TestTieQualifier(t *testing.T) {
+	tie, first, second := tieFixture(false)
+	awayTie, _, _ := tieFixture(true)
+	awayTie.FirstLegID, awayTie.SecondLegID = first.ID, second.ID
+
+	// Bahia won 2×1 in Belém, so Remo need to win by two in Salvador.
+	firstResult := withResult(first, 1, 2)
+	extraTime := withResult(second, 0, 1)
+	extraTime.ExtraTimeHomeGoals, extraTime.ExtraTimeAwayGoals = intPtr(1), intPtr(1)
+	penalties := withResult(second, 0, 1)
+	penalties.ExtraTimeHomeGoals, penalties.ExtraTimeAwayGoals = intPtr(0), intPtr(1)
+	penalties.PenaltyWinner = sidePtr(SideAway)
+
+	tests := []struct {
+		name   string
+		tie    models.Tie
+		first  models.Match
+		second models.Match
+		want   string
+	}{
+		{"ahead on aggregate", tie, firstResult, withResult(second, 1, 1), SideHome},
+		{"turned around in the second leg", tie, firstResult, withResult(second, 0, 2), SideAway},
+		{"level on aggregate, undecided", tie, firstResult, withResult(second, 0, 1), ""},
+		// 2×2 on aggregate, but Bahia scored two in Belém and Remo one in Salvador.
+		{"away goals", awayTie, firstResult, withResult(second, 0, 1), SideHome},
+		{"extra time", tie, firstResult, extraTime, SideHome},
+		{"penalties", tie, firstResult, penalties, SideAway},
+		{"second leg not played", tie, firstResult, second, ""},
+		{"first leg without result", tie, first, withResult(second, 3, 0), ""},
+	}
+	for _, tt := range tests {
+		l := &tieLegs{tie: tt.tie, first: tt.first}
+		if got := l.qualifier(tt.second); got != tt.want {
+			t.Errorf("%s: qualifier = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateTieResult(t *testing.T) {
+	tie, first, _ := tieFixture(false)
+	firstResult := withResult(first, 1, 2)
+
+	tests := []struct {
+		name  string
+		first models.Match
+		res   models.MatchResult
+		ok    bool
+	}{
+		// 1×2 in the second leg wins it but levels the aggregate, so extra time is on.
+		{"extra time after a level aggregate", firstResult, models.MatchResult{HomeGoals: 0, AwayGoals: 1,
+			ExtraTimeHomeGoals: intPtr(0), ExtraTimeAwayGoals: intPtr(1), PenaltyWinner: sidePtr(SideHome)}, true},
+		// A level second leg leaves Bahia ahead on aggregate.
+		{"extra time after a level second leg", firstResult, models.MatchResult{HomeGoals: 1, AwayGoals: 1,
+			ExtraTimeHomeGoals: intPtr(1), ExtraTimeAwayGoals: intPtr(1)}, false},
+		{"penalties after extra time decided it", firstResult, models.MatchResult{HomeGoals: 0, AwayGoals: 1,
+			ExtraTimeHomeGoals: intPtr(1), ExtraTimeAwayGoals: intPtr(1), PenaltyWinner: sidePtr(SideAway)}, false},
+		{"regulation before the first leg", first, models.MatchResult{HomeGoals: 1, AwayGoals: 0}, true},
+		{"penalties before the first leg", first, models.MatchResult{HomeGoals: 0, AwayGoals: 0, PenaltyWinner: sidePtr(SideHome)}, false},
+	}
+	for _, tt := range tests {
+		err := ValidateTieResult(tie, tt.first, tt.res)
+		if tt.ok && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidKnockout) {
+			t.Errorf("%s: error = %v, want ErrInvalidKnockout", tt.name, err)
+		}
+	}
+}
+
+// The qualifier points of a tie are paid on the second leg, on the aggregate of each
+// player's two predictions.
+func TestStandingsTieQualifier(t *testing.T) {
+	tie, first, second := tieFixture(false)
+	// 1×2 in Belém and 1×1 in Salvador: Bahia through, 3×2.
+	matches := []models.Match{withResult(first, 1, 2), withResult(second, 1, 1)}
+	ana, bruno, caio := exportUser("Ana"), exportUser("Bruno"), exportUser("Caio")
+	preds := []models.Prediction{
+		// 1×2 and 1×1: Bahia through on aggregate, without needing a pick.
+		testPrediction(ana, first, 1, 2), testPrediction(ana, second, 1, 1),
+		// 2×0 and 2×0: 2×2, and the pick sends Remo through.
+		testPrediction(bruno, first, 2, 0), withAdvances(testPrediction(bruno, second, 2, 0), SideAway),
+		// 0×1 and 1×0: the pick doesn't count, Bahia are ahead 2×0 on aggregate.
+		testPrediction(caio, first, 0, 1), withAdvances(testPrediction(caio, second, 1, 0), SideAway),
+	}
+	d := testSnapshot([]models.User{ana, bruno, caio}, matches, preds)
+
+	points := func() map[string]int {
+		out := map[string]int{}
+		for _, u := range d.standings(2, finalResults, testNow) {
+			out[u.DisplayName] = u.TotalPoints
+		}
+		return out
+	}
+	without := points()
+	d.ties = indexTies([]models.Tie{tie}, matches, preds)
+	with := points()
+
+	want := map[string]int{"Ana": PointsCorrectQualifier, "Bruno": 0, "Caio": PointsCorrectQualifier}
+	for name, w := range want {
+		if got := with[name] - without[name]; got != w {
+			t.Errorf("%s: the tie adds %d points, want %d", name, got, w)
+		}
+	}
+}
+
+// A snapshot of the second leg's round alone, as loadRounds builds it, scores the tie the
+// same as one of the whole bolão.
+func TestRoundSnapshotTieQualifier(t *testing.T) {
+	tie, first, second := tieFixture(false)
+	matches := []models.Match{withResult(first, 1, 2), withResult(second, 1, 1)}
+	ana, bruno := exportUser("Ana"), exportUser("Bruno")
+	firstLegs := []models.Prediction{testPrediction(ana, first, 1, 2), testPrediction(bruno, first, 2, 0)}
+	secondLegs := []models.Prediction{testPrediction(ana, second, 1, 1), withAdvances(testPrediction(bruno, second, 2, 0), SideAway)}
+	users := []models.User{ana, bruno}
+
+	full := testSnapshot(users, matches, append(append([]models.Prediction(nil), firstLegs...), secondLegs...))
+	full.ties = indexTies([]models.Tie{tie}, matches, firstLegs)
+	round := testSnapshot(users, matches[1:], secondLegs)
+	round.ties = full.ties
+
+	want, got := full.roundRows(2, testNow), round.roundRows(2, testNow)
+	if len(got) != len(want) {
+		t.Fatalf("%d rows, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].Points != want[i].Points {
+			t.Errorf("%s: %d points from the round alone, want %d", users[i].DisplayName, got[i].Points, want[i].Points)
+		}
+	}
+}
+
+// A scoring path that only loads the second leg's round still finds the first-leg
+// prediction.
+func TestTieWithFirstLegs(t *testing.T) {
+	tie, first, second := tieFixture(false)
+	ana := exportUser("Ana")
+	firstPred := testPrediction(ana, first, 3, 0)
+	idx := indexTies([]models.Tie{tie}, []models.Match{first, second}, []models.Prediction{firstPred})
+
+	lookup := idx.withFirstLegs(ana.ID, indexPredictions(nil).of(ana.ID))
+	if p, has := lookup(first.ID); !has || p.Home != 3 || p.Away != 0 {
+		t.Errorf("first leg = %+v (has %v), want 3×0", p, has)
+	}
+	if _, has := lookup(second.ID); has {
+		t.Error("second leg found without a prediction")
+	}
+}
+
+func withAdvances(p models.Prediction, side string) models.Prediction {
+	p.Advances = &side
+	return p
+}
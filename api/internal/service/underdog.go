@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/bolao-app/api/internal/repository"
+	"github.com/google/uuid"
+)
+
+// OutcomeShareIndex maps a match to its outcome split. FrozenAt is zero for a split
+// computed on the fly, not stored yet.
+type OutcomeShareIndex map[uuid.UUID]models.OutcomeShares
+
+// of returns the match's split, nil when it has none.
+func (idx OutcomeShareIndex) of(matchID uuid.UUID) *models.OutcomeShares {
+	if s, ok := idx[matchID]; ok {
+		return &s
+	}
+	return nil
+}
+
+// usesUnderdog reports whether any version of the rules turns the underdog bonus on.
+func (h RulesetHistory) usesUnderdog() bool {
+	for _, rs := range h {
+		if rs.UnderdogMultiplier > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// ComputeOutcomeShares counts the participants' effective predictions for m. A no-show's
+// 0×0 counts as a draw: it is what they are scored on (SCORING.md §4), so leaving it out
+// would make a draw nobody else called pay the no-show the full bonus.
+func ComputeOutcomeShares(m models.Match, participants []uuid.UUID, predictions predictionIndex, now time.Time) models.OutcomeShares {
+	shares := models.OutcomeShares{MatchID: m.ID}
+	for _, userID := range participants {
+		p, has := predictions[userID][m.ID]
+		home, away, counts := EffectivePrediction(m, p.Home, p.Away, has, now)
+		if !counts {
+			continue
+		}
+		switch matchResult(home, away) {
+		case "home":
+			shares.Home++
+		case "draw":
+			shares.Draw++
+		default:
+			shares.Away++
+		}
+	}
+	return shares
+}
+
+// LoadOutcomeShares returns the split of the bolão's matches: the frozen one, or for a
+// match in matches whose market has closed and whose round uses the underdog bonus, the
+// split it would freeze with now. It never writes; FreezeOutcomeShares does. When no
+// version of the rules uses the bonus, it returns nil without touching the database.
+func LoadOutcomeShares(
+	ctx context.Context,
+	repo *repository.OutcomeShareRepository,
+	bolaoID uuid.UUID,
+	rulesets RulesetHistory,
+	matches []models.Match,
+	participants []uuid.UUID,
+	predictions []models.Prediction,
+	now time.Time,
+) (OutcomeShareIndex, error) {
+	if !rulesets.usesUnderdog() {
+		return nil, nil
+	}
+	index, err := repo.ListByBolao(ctx, bolaoID)
+	if err != nil {
+		return nil, err
+	}
+
+	var predIndex predictionIndex
+	for _, m := range matches {
+		if _, frozen := index[m.ID]; frozen || !MarketClosed(m, now) || rulesets.ForRound(m.Round).UnderdogMultiplier <= 1 {
+			continue
+		}
+		if predIndex == nil {
+			predIndex = indexPredictions(predictions)
+		}
+		index[m.ID] = ComputeOutcomeShares(m, participants, predIndex, now)
+	}
+	return index, nil
+}
+
+// FreezeOutcomeShares stores the split LoadOutcomeShares computed for each of matches that
+// has none frozen yet, and updates shares with what was stored. Call it from a write path:
+// the reads only compute the split.
+func FreezeOutcomeShares(ctx context.Context, repo *repository.OutcomeShareRepository, shares OutcomeShareIndex, matches []models.Match) error {
+	for _, m := range matches {
+		split, ok := shares[m.ID]
+		if !ok || !split.FrozenAt.IsZero() {
+			continue
+		}
+		if err := repo.Freeze(ctx, &split); err != nil {
+			return err
+		}
+		shares[m.ID] = split
+	}
+	return nil
+}
+
+// applyUnderdog scales the match points of a right outcome by how few participants called
+// it (SCORING.md §2.5): the rarer the outcome, the closer to UnderdogMultiplier times the
+// points. A wrong outcome, or a match without a frozen split, is left as is.
+func applyUnderdog(rules models.ScoringRuleset, items []ScoreItem, p PredEntry, m MatchScore) []ScoreItem {
+	if rules.UnderdogMultiplier <= 1 || p.Shares == nil {
+		return items
+	}
+	outcome := matchResult(m.HomeGoals, m.AwayGoals)
+	if matchResult(p.PredHome, p.PredAway) != outcome {
+		return items
+	}
+	hits, total := outcomeCount(*p.Shares, outcome), p.Shares.Home+p.Shares.Draw+p.Shares.Away
+	extra := underdogBonus(rules.UnderdogMultiplier, sumItems(items), hits, total)
+	if extra == 0 {
+		return items
+	}
+	return append(items, ScoreItem{
+		Rule: "underdog", Constant: "UnderdogMultiplier", Points: extra,
+		Detail: fmt.Sprintf("%d de %d acertaram o resultado", hits, total),
+	})
+}
+
+func outcomeCount(s models.OutcomeShares, outcome string) int {
+	switch outcome {
+	case "home":
+		return s.Home
+	case "draw":
+		return s.Draw
+	}
+	return s.Away
+}
+
+// underdogBonus is points × (multiplier−1) × the share of participants who missed the
+// outcome, rounded half up: nothing when everyone called it, up to (multiplier−1) × points
+// when nobody else did.
+func underdogBonus(multiplier, points, hits, total int) int {
+	if total == 0 || hits >= total || points <= 0 {
+		return 0
+	}
+	num := points * (multiplier - 1) * (total - hits)
+	return (2*num + total) / (2 * total)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bolao-app/api/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestUnderdogBonus(t *testing.T) {
+	tests := []struct {
+		multiplier, points, hits, total int
+		want                            int
+	}{
+		{2, 18, 1, 10, 16}, // 18 × 9/10 = 16.2
+		{2, 18, 9, 10, 2},  // 18 × 1/10 = 1.8
+		{3, 10, 1, 4, 15},  // 10 × 2 × 3/4
+		{2, 3, 2, 4, 2},    // 1.5 rounds half up
+		{2, 18, 10, 10, 0}, // everyone called it
+		{2, 0, 1, 10, 0},
+		{2, 18, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := underdogBonus(tt.multiplier, tt.points, tt.hits, tt.total); got != tt.want {
+			t.Errorf("underdogBonus(%d, %d, %d, %d) = %d, want %d",
+				tt.multiplier, tt.points, tt.hits, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestComputeOutcomeSharesCountsNoShowAsDraw(t *testing.T) {
+	closed := timePtr(testNow.Add(-time.Hour))
+	m := exportMatch("Flamengo", "Vasco", 2, 1, closed)
+	ana, bruno, caio, duda := exportUser("Ana"), exportUser("Bruno"), exportUser("Caio"), exportUser("Duda")
+	preds := indexPredictions([]models.Prediction{
+		testPrediction(ana, m, 2, 1), testPrediction(bruno, m, 0, 2), testPrediction(caio, m, 3, 0),
+	})
+	participants := []uuid.UUID{ana.ID, bruno.ID, caio.ID, duda.ID}
+
+	got := ComputeOutcomeShares(m, participants, preds, testNow)
+	if got.Home != 2 || got.Draw != 1 || got.Away != 1 {
+		t.Errorf("shares = %d/%d/%d, want 2/1/1 with Duda's missing prediction as a draw", got.Home, got.Draw, got.Away)
+	}
+
+	// While the market is open a missing prediction counts for nothing.
+	m.MarketClosesAt = timePtr(testNow.Add(time.Hour))
+	got = ComputeOutcomeShares(m, participants, preds, testNow)
+	if got.Home != 2 || got.Draw != 0 || got.Away != 1 {
+		t.Errorf("open market shares = %d/%d/%d, want 2/0/1", got.Home, got.Draw, got.Away)
+	}
+}
+
+func TestUnderdogInRoundBreakdown(t *testing.T) {
+	rules := rulesWithEngine(EngineClassic)
+	rules.UnderdogMultiplier = 2
+	// 1 of 4 called the home win.
+	shares := &models.OutcomeShares{Home: 1, Draw: 2, Away: 1}
+	matches := []MatchScore{{HomeGoals: 2, AwayGoals: 1}}
+
+	exact := CalculateRoundBreakdown(rules, []PredEntry{{PredHome: 2, PredAway: 1, Shares: shares}}, matches, true)
+	// 3 for the exact score, plus 3 × 3/4 rounded.
+	if exact.Points != 5 {
+		t.Errorf("exact score of the underdog = %d points, want 5", exact.Points)
+	}
+
+	wrong := CalculateRoundBreakdown(rules, []PredEntry{{PredHome: 1, PredAway: 1, Shares: shares}}, matches, true)
+	if wrong.Points != 0 {
+		t.Errorf("wrong outcome = %d points, want 0", wrong.Points)
+	}
+
+	// The coringa doubles the match with its underdog bonus.
+	joker := CalculateRoundBreakdown(rules, []PredEntry{{PredHome: 2, PredAway: 1, Joker: true, Shares: shares}}, matches, true)
+	if joker.Points != 10 {
+		t.Errorf("underdog on the coringa = %d points, want 10", joker.Points)
+	}
+
+	rules.UnderdogMultiplier = 0
+	off := CalculateRoundBreakdown(rules, []PredEntry{{PredHome: 2, PredAway: 1, Shares: shares}}, matches, true)
+	if off.Points != 3 {
+		t.Errorf("with the bonus off = %d points, want 3", off.Points)
+	}
+}
+
+func TestValidateScoringRulesetUnderdog(t *testing.T) {
+	rules := DefaultScoringRuleset()
+	rules.UnderdogMultiplier = -1
+	if err := ValidateScoringRuleset(rules); !errors.Is(err, ErrInvalidRuleset) {
+		t.Errorf("negative underdog multiplier accepted: %v", err)
+	}
+}
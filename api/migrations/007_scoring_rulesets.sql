@@ -0,0 +1,25 @@
+-- Regras de pontuação por bolão. Cada bolão guarda os próprios valores, então mudar as
+-- regras para a próxima temporada não repontua as temporadas já encerradas.
+CREATE TABLE IF NOT EXISTS scoring_rulesets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bolao_id UUID NOT NULL UNIQUE REFERENCES boloes(id) ON DELETE CASCADE,
+    correct_result INT NOT NULL,
+    correct_draw INT NOT NULL,
+    correct_home_goals INT NOT NULL,
+    correct_away_goals INT NOT NULL,
+    exact_score INT NOT NULL,
+    exact_score_high INT NOT NULL,
+    total_goals_high INT NOT NULL,
+    high_scoring_goals INT NOT NULL,
+    round_total_goals INT NOT NULL,
+    score_type_bonus INT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Bolões criados antes desta tabela foram pontuados com os valores do SCORING.md,
+-- que até aqui eram constantes no código. Só insere para quem ainda não tem regras.
+INSERT INTO scoring_rulesets (bolao_id, correct_result, correct_draw, correct_home_goals, correct_away_goals,
+    exact_score, exact_score_high, total_goals_high, high_scoring_goals, round_total_goals, score_type_bonus)
+SELECT b.id, 9, 12, 3, 3, 3, 10, 3, 4, 10, '{0,10,20,30}'
+FROM boloes b
+WHERE NOT EXISTS (SELECT 1 FROM scoring_rulesets r WHERE r.bolao_id = b.id);
@@ -0,0 +1,14 @@
+-- Coringa: cada jogador escolhe um jogo por rodada cujos pontos valem em dobro.
+-- A chave (user_id, bolao_id, round) garante no máximo um coringa por rodada.
+CREATE TABLE IF NOT EXISTS jokers (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    bolao_id UUID NOT NULL REFERENCES boloes(id) ON DELETE CASCADE,
+    round INT NOT NULL,
+    match_id UUID NOT NULL REFERENCES matches(id) ON DELETE CASCADE,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, bolao_id, round)
+);
+
+-- O multiplicador faz parte das regras do bolão. Bolões anteriores nunca tiveram
+-- coringas, então o default não muda a pontuação deles.
+ALTER TABLE scoring_rulesets ADD COLUMN IF NOT EXISTS joker_multiplier INT NOT NULL DEFAULT 2;
@@ -0,0 +1,25 @@
+-- Apostas de temporada (campeão, rebaixados, G4...): perguntas criadas pelo admin por
+-- bolão, com prazo. outcome fica NULL até o admin informar o resultado.
+CREATE TABLE IF NOT EXISTS outright_questions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bolao_id UUID NOT NULL REFERENCES boloes(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    picks INT NOT NULL DEFAULT 1,
+    points_per_hit INT NOT NULL,
+    closes_at TIMESTAMPTZ NOT NULL,
+    outcome TEXT[],
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT outright_questions_picks_check CHECK (picks >= 1),
+    CONSTRAINT outright_questions_points_check CHECK (points_per_hit >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_outright_questions_bolao ON outright_questions (bolao_id);
+
+CREATE TABLE IF NOT EXISTS outright_answers (
+    question_id UUID NOT NULL REFERENCES outright_questions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    teams TEXT[] NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (question_id, user_id)
+);
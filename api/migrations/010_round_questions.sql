@@ -0,0 +1,27 @@
+-- Perguntas bônus por rodada ("vai ter cartão vermelho no Fla x Flu?"). kind 'choice'
+-- usa options; kind 'number' aceita um inteiro. result fica NULL até o admin informar.
+CREATE TABLE IF NOT EXISTS round_questions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bolao_id UUID NOT NULL REFERENCES boloes(id) ON DELETE CASCADE,
+    round INT NOT NULL,
+    prompt VARCHAR(200) NOT NULL,
+    kind VARCHAR(10) NOT NULL,
+    options TEXT[],
+    points INT NOT NULL,
+    result TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT round_questions_kind_check CHECK (kind IN ('choice', 'number')),
+    CONSTRAINT round_questions_points_check CHECK (points >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_round_questions_bolao_round ON round_questions (bolao_id, round);
+
+-- A resposta é guardada como texto já normalizado (a opção escolhida ou o número).
+CREATE TABLE IF NOT EXISTS round_question_answers (
+    question_id UUID NOT NULL REFERENCES round_questions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    answer TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (question_id, user_id)
+);
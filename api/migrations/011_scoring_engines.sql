@@ -0,0 +1,3 @@
+-- Sistema de pontuação do bolão. 'bolao' é o regulamento do SCORING.md, que todos os
+-- bolões existentes já usavam; 'classic' e 'kicktipp' usam pontos fixos.
+ALTER TABLE scoring_rulesets ADD COLUMN IF NOT EXISTS engine VARCHAR(20) NOT NULL DEFAULT 'bolao';
@@ -0,0 +1,10 @@
+-- Versões das regras de pontuação: uma mudança no meio da temporada vale a partir de uma
+-- rodada, e as rodadas anteriores continuam pontuadas pela versão antiga. As regras que
+-- já existiam viram a versão 1, valendo desde a rodada 1.
+ALTER TABLE scoring_rulesets ADD COLUMN IF NOT EXISTS version INT NOT NULL DEFAULT 1;
+ALTER TABLE scoring_rulesets ADD COLUMN IF NOT EXISTS effective_from_round INT NOT NULL DEFAULT 1;
+
+-- Um bolão passa a ter várias linhas; a unicidade agora é por versão e por rodada inicial.
+ALTER TABLE scoring_rulesets DROP CONSTRAINT IF EXISTS scoring_rulesets_bolao_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scoring_rulesets_bolao_version ON scoring_rulesets (bolao_id, version);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_scoring_rulesets_bolao_round ON scoring_rulesets (bolao_id, effective_from_round);
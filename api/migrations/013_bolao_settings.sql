@@ -0,0 +1,5 @@
+-- Desempate configurável por bolão. Lista vazia = critérios padrão do sistema de
+-- pontuação (placares exatos, resultados certos, rodadas vencidas), como sempre foi.
+ALTER TABLE boloes ADD COLUMN IF NOT EXISTS tiebreakers TEXT[] NOT NULL DEFAULT '{}';
+-- Posição compartilhada: empatados em todos os critérios aparecem com a mesma posição.
+ALTER TABLE boloes ADD COLUMN IF NOT EXISTS shared_positions BOOLEAN NOT NULL DEFAULT FALSE;
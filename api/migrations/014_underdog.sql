@@ -0,0 +1,13 @@
+-- Zebra: os pontos de quem acertou um resultado que pouca gente previu são multiplicados
+-- pela raridade do acerto. 0 desliga, e é o default: os bolões existentes não mudam.
+ALTER TABLE scoring_rulesets ADD COLUMN IF NOT EXISTS underdog_multiplier INT NOT NULL DEFAULT 0;
+
+-- Quantos participantes previram vitória do mandante, empate ou vitória do visitante,
+-- congelado depois do fechamento do mercado para a pontuação ser reproduzível.
+CREATE TABLE IF NOT EXISTS match_outcome_shares (
+    match_id UUID PRIMARY KEY REFERENCES matches(id) ON DELETE CASCADE,
+    home INT NOT NULL,
+    draw INT NOT NULL,
+    away INT NOT NULL,
+    frozen_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- Peso por rodada: faixas de rodadas cujos pontos valem mais (ex.: 35–38 valendo 150%).
+-- Lista vazia = todas as rodadas com peso 100%, como sempre foi.
+ALTER TABLE boloes ADD COLUMN IF NOT EXISTS round_weights JSONB NOT NULL DEFAULT '[]';
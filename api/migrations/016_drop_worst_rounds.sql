@@ -0,0 +1,3 @@
+-- Descarte das piores rodadas: as N menores pontuações de rodada de cada participante saem
+-- do total acumulado. 0 = nenhuma rodada descartada, como sempre foi.
+ALTER TABLE boloes ADD COLUMN IF NOT EXISTS drop_worst_rounds INT NOT NULL DEFAULT 0;
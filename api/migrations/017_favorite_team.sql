@@ -0,0 +1,19 @@
+-- Bônus do time do coração: placar exato ou resultado certo em jogo do time do
+-- participante vale pontos extras. 0 desliga, e é o default: os bolões existentes não mudam.
+ALTER TABLE scoring_rulesets ADD COLUMN IF NOT EXISTS favorite_team_exact_bonus INT NOT NULL DEFAULT 0;
+ALTER TABLE scoring_rulesets ADD COLUMN IF NOT EXISTS favorite_team_result_bonus INT NOT NULL DEFAULT 0;
+
+-- Histórico do time do coração, para o bônus usar o time que o participante tinha quando o
+-- mercado da rodada fechou: trocar de time no meio do campeonato não vale para trás.
+CREATE TABLE IF NOT EXISTS favorite_team_changes (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    team VARCHAR(50),
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_favorite_team_changes_user ON favorite_team_changes(user_id, changed_at);
+
+-- Usuários de antes do histórico: o time atual vale desde a criação da conta.
+INSERT INTO favorite_team_changes (user_id, team, changed_at)
+SELECT u.id, u.favorite_team, u.created_at FROM users u
+WHERE NOT EXISTS (SELECT 1 FROM favorite_team_changes c WHERE c.user_id = u.id);
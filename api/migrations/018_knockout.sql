@@ -0,0 +1,14 @@
+-- Mata-mata (Copa do Brasil, Libertadores): o placar dos 90 minutos continua em
+-- home_goals/away_goals; a prorrogação e os pênaltis decidem quem avança.
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS knockout BOOLEAN NOT NULL DEFAULT FALSE;
+-- Placar ao fim da prorrogação, incluindo os gols do tempo normal.
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS extra_time_home_goals INT;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS extra_time_away_goals INT;
+ALTER TABLE matches ADD COLUMN IF NOT EXISTS penalty_winner VARCHAR(4) CHECK (penalty_winner IN ('home', 'away'));
+
+-- Quem o participante acha que avança; só precisa ser informado em um palpite de empate.
+ALTER TABLE predictions ADD COLUMN IF NOT EXISTS advances VARCHAR(4) CHECK (advances IN ('home', 'away'));
+
+-- Pontos por acertar quem avança. Só vale para jogos de mata-mata, então os bolões de
+-- pontos corridos não mudam.
+ALTER TABLE scoring_rulesets ADD COLUMN IF NOT EXISTS qualifier_points INT NOT NULL DEFAULT 6;
@@ -0,0 +1,15 @@
+-- Confrontos de ida e volta: quem avança sai do agregado dos dois jogos. Os lados do
+-- confronto são os do jogo de volta, onde ele se decide (prorrogação, pênaltis e o palpite
+-- de quem avança ficam nele). Um jogo faz parte de no máximo um confronto.
+CREATE TABLE IF NOT EXISTS ties (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    bolao_id UUID NOT NULL REFERENCES boloes(id) ON DELETE CASCADE,
+    first_leg_id UUID NOT NULL UNIQUE REFERENCES matches(id) ON DELETE CASCADE,
+    second_leg_id UUID NOT NULL UNIQUE REFERENCES matches(id) ON DELETE CASCADE,
+    -- Desempata o agregado pelos gols marcados fora de casa.
+    away_goals BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT ties_distinct_legs_check CHECK (first_leg_id <> second_leg_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ties_bolao ON ties (bolao_id);
@@ -0,0 +1,28 @@
+-- Pontuação materializada por participante e rodada, para a classificação geral somar
+-- linhas prontas em vez de repontuar o bolão inteiro a cada consulta.
+CREATE TABLE IF NOT EXISTS round_scores (
+    bolao_id UUID NOT NULL REFERENCES boloes(id) ON DELETE CASCADE,
+    round INT NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    points INT NOT NULL,
+    exact_scores INT NOT NULL,
+    correct_results INT NOT NULL,
+    missed INT NOT NULL,
+    round_total_hits INT NOT NULL,
+    first_submission TIMESTAMPTZ,
+    PRIMARY KEY (bolao_id, round, user_id)
+);
+
+-- Estado de cada rodada materializada. generation sobe a cada invalidação; as linhas de
+-- round_scores valem enquanto computed_generation for igual a ela e valid_until (o próximo
+-- fechamento de mercado da rodada, que muda a pontuação sozinho) não tiver passado. Uma
+-- rodada sem linha aqui nunca foi calculada.
+CREATE TABLE IF NOT EXISTS round_score_rounds (
+    bolao_id UUID NOT NULL REFERENCES boloes(id) ON DELETE CASCADE,
+    round INT NOT NULL,
+    generation BIGINT NOT NULL DEFAULT 0,
+    computed_generation BIGINT,
+    valid_until TIMESTAMPTZ,
+    computed_at TIMESTAMPTZ,
+    PRIMARY KEY (bolao_id, round)
+);
@@ -0,0 +1,4 @@
+-- Faixas de rodadas com classificação própria (ex.: turno 1–19 e returno 20–38), para
+-- premiar o melhor de cada metade do campeonato. Os bolões existentes ganham as duas.
+ALTER TABLE boloes ADD COLUMN IF NOT EXISTS round_ranges JSONB NOT NULL
+    DEFAULT '[{"name": "turno", "from_round": 1, "to_round": 19}, {"name": "returno", "from_round": 20, "to_round": 38}]';
@@ -0,0 +1,3 @@
+-- Prêmio do jogador do mês: valor pago ao vencedor de cada mês (dividido entre os empatados
+-- em posições compartilhadas). 0 = sem prêmio mensal, como sempre foi.
+ALTER TABLE boloes ADD COLUMN IF NOT EXISTS monthly_prize DECIMAL(10, 2) NOT NULL DEFAULT 0;